@@ -0,0 +1,88 @@
+// PACKAGE backup WRITES THE BACKUP OF A SEASON AS ONE PORTABLE ARCHIVE FILE: GZIP COMPRESSED JSON HOLDING EVERY
+// ROW RETURNED BY BackupSeason, READ BACK BY Read FOR RestoreSeason IN THE SAME OR ANOTHER DATABASE
+package backup
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+)
+
+// IDENTIFIES THE ARCHIVES OF THIS PACKAGE
+const Format = "playoffs-season-backup"
+
+// VERSION OF THE ARCHIVES THIS PACKAGE WRITES AND READS
+const Version = 1
+
+// EXTENSION OF THE ARCHIVE FILES
+const Extension = ".json.gz"
+
+// THE LARGEST DECOMPRESSED ARCHIVE Read ACCEPTS, SO A SMALL GZIP FILE CAN NOT EXPAND INTO ALL THE MEMORY
+const MaxSize = 256 << 20
+
+// RETURNED BY Read FOR A FILE THAT IS NOT A BACKUP OF A VERSION IT KNOWS
+var ErrInvalidArchive = errors.New("invalid backup archive")
+
+type archive struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	models.SeasonBackupModel
+}
+
+// WRITES THE ARCHIVE OF A BACKUP
+func Write(w io.Writer, backup models.SeasonBackupModel) error {
+	zw := gzip.NewWriter(w)
+	zw.Name = FileName(backup.League, backup.Season)
+	zw.ModTime = backup.CreatedAt
+	// NOT INDENTED, WHICH WOULD REWRITE THE JSON OF THE AUDIT AND CHANGE LOGS
+	if err := json.NewEncoder(zw).Encode(archive{Format: Format, Version: Version, SeasonBackupModel: backup}); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// READS AN ARCHIVE WRITTEN BY Write, OF AT MOST MaxSize BYTES ONCE DECOMPRESSED. THE ERRORS OF r ARE WRAPPED
+// IN ErrInvalidArchive
+func Read(r io.Reader) (models.SeasonBackupModel, error) {
+	return read(r, MaxSize)
+}
+
+func read(r io.Reader, maxSize int64) (models.SeasonBackupModel, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return models.SeasonBackupModel{}, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	defer zr.Close()
+	var a archive
+	limited := &io.LimitedReader{R: zr, N: maxSize}
+	decoder := json.NewDecoder(limited)
+	decoder.DisallowUnknownFields()
+	if errD := decoder.Decode(&a); errD != nil {
+		if limited.N == 0 {
+			return models.SeasonBackupModel{}, fmt.Errorf("%w: it is larger than %d bytes once decompressed", ErrInvalidArchive, maxSize)
+		}
+		return models.SeasonBackupModel{}, fmt.Errorf("%w: %w", ErrInvalidArchive, errD)
+	}
+	if a.Format != Format {
+		return models.SeasonBackupModel{}, fmt.Errorf("%w: the file is not a season backup", ErrInvalidArchive)
+	}
+	if a.Version != Version {
+		return models.SeasonBackupModel{}, fmt.Errorf("%w: version %d is not supported, expected %d", ErrInvalidArchive, a.Version, Version)
+	}
+	return a.SeasonBackupModel, nil
+}
+
+var unsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// THE FILE NAME OF THE ARCHIVE OF A SEASON
+func FileName(league string, season string) string {
+	name := unsafe.ReplaceAllString(league+"-"+season, "_")
+	return strings.Trim(name, "_") + Extension
+}
@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleBackup() models.SeasonBackupModel {
+	teamId, gameId := uuid.New(), uuid.New()
+	round, count := 1, "FINAL"
+	archived := time.Date(2024, 7, 1, 8, 0, 0, 0, time.UTC)
+	return models.SeasonBackupModel{
+		League:    "north",
+		Season:    "2023-2024",
+		CreatedAt: time.Date(2024, 7, 2, 9, 30, 0, 0, time.UTC),
+		Standings: []models.StandingsModel{{StandingsId: uuid.New(), TeamId: &teamId, TeamName: "Lions", Pts: 40, WinPercentage: 0.75, Conference: "East", Season: "2023-2024", League: "north"}},
+		Playoffs:  []models.PlayoffsModel{{PlayoffsId: gameId, FixtureRound: &round, GameCount: &count, GameRound: "1", HomeTeamId: &teamId, Winner: &teamId, Season: "2023-2024", League: "north", Competition: "main", Version: 3, ArchivedAt: &archived}},
+		Audit:     []models.AuditModel{{AuditId: uuid.New(), Season: "2023-2024", League: "north", Competition: "main", PlayoffsId: &gameId, Operation: "UPDATE_PLAYOFFS", Actor: "admin", Before: types.JSONText(`{"winner":null}`), After: types.JSONText(`{"winner":"x"}`), CreatedAt: archived}},
+		Changes:   []models.ChangeModel{{ChangeSeq: 7, BatchId: uuid.New(), Season: "2023-2024", League: "north", Competition: "main", PlayoffsId: gameId, Operation: "UPDATE_PLAYOFFS", Before: types.JSONText(`{}`), After: types.JSONText(`{}`), CreatedAt: archived}},
+	}
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	backup := sampleBackup()
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, backup))
+	header, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, "north-2023-2024.json.gz", header.Name)
+	restored, err := Read(&buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, backup, restored)
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte(`{"format":"playoffs-season-backup"}`)))
+	assert.ErrorIs(t, err, ErrInvalidArchive, "not compressed")
+
+	for doc, message := range map[string]string{
+		`{"format":"tournament","version":1}`:             "not a season backup",
+		`{"format":"playoffs-season-backup","version":9}`: "version 9 is not supported",
+		`{"format":"playoffs-season-backup","rows":[]}`:   "unknown field",
+	} {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(doc))
+		require.NoError(t, zw.Close())
+
+		_, err := Read(&buf)
+
+		assert.ErrorIs(t, err, ErrInvalidArchive, doc)
+		assert.ErrorContains(t, err, message, doc)
+	}
+}
+
+func TestRead_TooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, sampleBackup()))
+	archive := buf.Bytes()
+
+	_, err := read(bytes.NewReader(archive), 64)
+
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+	assert.ErrorContains(t, err, "larger than 64 bytes once decompressed")
+	_, err = read(bytes.NewReader(archive), MaxSize)
+	assert.NoError(t, err)
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "default-2023-2024.json.gz", FileName("default", "2023-2024"))
+	assert.Equal(t, "north_america-Spring_2024.json.gz", FileName("north america", "Spring/2024"))
+}
@@ -0,0 +1,239 @@
+package bracket
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/internal/bracketfixture"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize_BestOfThree(t *testing.T) {
+	lions, tigers := bracketfixture.NewTeam("Lions", ""), bracketfixture.NewTeam("Tigers", "")
+
+	open := Summarize(bracketfixture.Series(1, "1", &lions, &tigers, 3, &lions, &tigers))
+	assert.Equal(t, "R1 #1", open.Label)
+	assert.Equal(t, 1, open.Home.Wins)
+	assert.Equal(t, 1, open.Away.Wins)
+	assert.False(t, open.Decided)
+
+	won := Summarize(bracketfixture.Series(1, "1", &lions, &tigers, 3, &tigers, nil, &tigers))
+	assert.True(t, won.Decided)
+	assert.True(t, won.Away.Winner)
+	assert.False(t, won.Home.Winner)
+
+	final := Summarize(bracketfixture.Series(2, "FINAL", &lions, &tigers, 1, &lions))
+	assert.True(t, final.Final)
+	assert.Equal(t, "FINAL", final.Label)
+	assert.True(t, final.Home.Winner)
+}
+
+func TestNewLayout_CentersSeriesOnTheirFeeders(t *testing.T) {
+	a, b, c, d := bracketfixture.NewTeam("A", ""), bracketfixture.NewTeam("B", ""), bracketfixture.NewTeam("C", ""), bracketfixture.NewTeam("D", "")
+	playoffs := [][][]models.PlayoffsModel{
+		{bracketfixture.Series(1, "1", &a, &b, 3, &a, &a), bracketfixture.Series(1, "2", &c, &d, 3, &d, &c)},
+		{bracketfixture.Series(2, "FINAL", &a, nil, 1)},
+	}
+
+	l := NewLayout(playoffs, DefaultOptions)
+
+	require.Len(t, l.Boxes, 3)
+	semi1, semi2, final := l.Boxes[0], l.Boxes[1], l.Boxes[2]
+	assert.Equal(t, semi1.Y+semi1.Height+DefaultOptions.RowGap, semi2.Y)
+	assert.Equal(t, (semi1.Y+semi2.Y)/2, final.Y)
+	assert.Equal(t, semi1.X+DefaultOptions.BoxWidth+DefaultOptions.ColumnGap, final.X)
+	assert.Equal(t, []string{"Round 1", "Final"}, []string{l.Rounds[0].Text, l.Rounds[1].Text})
+
+	require.Len(t, l.Connectors, 2)
+	assert.True(t, l.Connectors[0].Decided)
+	assert.False(t, l.Connectors[1].Decided)
+	assert.Equal(t, final.Y+final.Height/2, l.Connectors[1].ToY)
+	assert.Nil(t, l.Champion)
+	assert.Equal(t, final.X+final.Width+DefaultOptions.Margin, l.Width)
+	assert.Equal(t, semi2.Y+semi2.Height+DefaultOptions.Margin, l.Height)
+}
+
+func TestWriteSVG_WellFormedWithLogosAndWinners(t *testing.T) {
+	lions, tigers := bracketfixture.NewTeam("Lions & Co", "https://cdn.test/lions.png"), bracketfixture.NewTeam("Tigers", "")
+	playoffs := [][][]models.PlayoffsModel{{bracketfixture.Series(1, "FINAL", &lions, &tigers, 1, &lions)}}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteSVG(&out, NewLayout(playoffs, DefaultOptions), Title("2023-2024", "cup")))
+
+	svg := out.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.Contains(t, svg, "Playoffs 2023-2024 · cup")
+	assert.Contains(t, svg, "Champion: Lions &amp; Co")
+	assert.Contains(t, svg, `xlink:href="https://cdn.test/lions.png"`)
+	assert.Contains(t, svg, `font-weight="bold" fill="#0b6e4f">Lions &amp; Co</text>`)
+	assert.Contains(t, svg, `font-weight="normal" fill="#1f2430">Tigers</text>`)
+
+	decoder := xml.NewDecoder(&out)
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestWriteSVG_EmptySlotsAreTBD(t *testing.T) {
+	lions := bracketfixture.NewTeam("Lions", "")
+	playoffs := [][][]models.PlayoffsModel{{bracketfixture.Series(2, "FINAL", &lions, nil, 1)}}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteSVG(&out, NewLayout(playoffs, DefaultOptions), Title("2023-2024", "")))
+
+	assert.Contains(t, out.String(), ">TBD</text>")
+	assert.Contains(t, out.String(), ">Playoffs 2023-2024</text>")
+	assert.NotContains(t, out.String(), "Champion")
+}
+
+// A BRACKET OF teams TEAMS WITH EVERY FIRST ROUND SERIES UNPLAYED
+func emptyBracket(teams int) [][][]models.PlayoffsModel {
+	var playoffs [][][]models.PlayoffsModel
+	for r, n := 1, teams/2; n >= 1; r, n = r+1, n/2 {
+		var round [][]models.PlayoffsModel
+		for i := 0; i < n; i++ {
+			home, away := bracketfixture.NewTeam("Home", ""), bracketfixture.NewTeam("Away", "")
+			round = append(round, bracketfixture.Series(r, strconv.Itoa(i+1), &home, &away, 3))
+		}
+		playoffs = append(playoffs, round)
+	}
+	return playoffs
+}
+
+func writeLogo(t *testing.T, c color.RGBA) string {
+	logo := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.Draw(logo, logo.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	path := filepath.Join(t.TempDir(), "logo.png")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(f, logo))
+	require.NoError(t, f.Close())
+	return path
+}
+
+func TestLocalLogoPath(t *testing.T) {
+	for url, expected := range map[string]string{
+		"file:///srv/logos/lions.png":          "/srv/logos/lions.png",
+		"file://localhost/srv/logos/lions.png": "/srv/logos/lions.png",
+		"/srv/logos/2024/lions.png":            "/srv/logos/2024/lions.png",
+		"lions.png":                            "/srv/logos/lions.png",
+		"file:///etc/passwd":                   "",
+		"/srv/logos/../secrets/key.png":        "",
+		"../secrets/key.png":                   "",
+		"/srv/logos":                           "",
+		"https://cdn.test/lions.png":           "",
+		"file://cdn.test/lions.png":            "",
+		"":                                     "",
+	} {
+		path, ok := localLogoPath("/srv/logos", url)
+		assert.Equal(t, expected, path, url)
+		assert.Equal(t, expected != "", ok, url)
+	}
+	_, ok := localLogoPath("", "/srv/logos/lions.png")
+	assert.False(t, ok, "no local logo without a directory")
+}
+
+func TestLogoCache_OnlyRegularFiles(t *testing.T) {
+	path := writeLogo(t, color.RGBA{G: 0xff, A: 0xff})
+	dir := filepath.Dir(path)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "logos.png"), 0o755))
+	require.NoError(t, os.Symlink(path, filepath.Join(dir, "link.png")))
+
+	_, ok := newLogoCache(dir).load(path)
+	assert.True(t, ok)
+	_, ok = newLogoCache(dir).load("logos.png")
+	assert.False(t, ok, "a directory")
+	_, ok = newLogoCache(dir).load("link.png")
+	assert.False(t, ok, "a symbolic link")
+	_, ok = newLogoCache("").load(path)
+	assert.False(t, ok, "local logos are off")
+}
+
+func TestWritePNG_ScalesToSizeAndEmbedsLocalLogos(t *testing.T) {
+	red := color.RGBA{R: 0xd0, A: 0xff}
+	logo := writeLogo(t, red)
+	lions, tigers := bracketfixture.NewTeam("Lions", "file://"+logo), bracketfixture.NewTeam("Tigers", "https://cdn.test/tigers.png")
+	opts := DefaultOptions
+	opts.LogoDir = filepath.Dir(logo)
+	l := NewLayout([][][]models.PlayoffsModel{{bracketfixture.Series(1, "FINAL", &lions, &tigers, 1, &lions)}}, opts)
+
+	var out bytes.Buffer
+	require.NoError(t, WritePNG(&out, l, Title("2023-2024", ""), 1200, 675))
+
+	img, err := png.Decode(&out)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 1200, 675), img.Bounds())
+	// THE CENTER OF THE LOGO OF THE HOME TEAM, MAPPED TO PIXELS
+	scale := min(1200/l.Width, 675/l.Height)
+	box, row := l.Boxes[0], l.Options.BoxHeight/2
+	x := (1200-l.Width*scale)/2 + (box.X+row/2)*scale
+	y := (675-l.Height*scale)/2 + (box.Y+row/2)*scale
+	assert.Equal(t, red, color.RGBAModel.Convert(img.At(int(x), int(y))))
+}
+
+func TestWritePNG_KeepsAspectRatio(t *testing.T) {
+	l := NewLayout(emptyBracket(4), DefaultOptions)
+
+	var out bytes.Buffer
+	require.NoError(t, WritePNG(&out, l, "", 600, 0))
+
+	cfg, err := png.DecodeConfig(&out)
+	require.NoError(t, err)
+	assert.Equal(t, 600, cfg.Width)
+	assert.Equal(t, int(math.Ceil(600*l.Height/l.Width)), cfg.Height)
+	assert.ErrorIs(t, WritePNG(io.Discard, l, "", MaxPNGSize+1, 10), ErrInvalidSize)
+}
+
+func TestWritePDF_SinglePage(t *testing.T) {
+	logo := writeLogo(t, color.RGBA{B: 0xff, A: 0xff})
+	lions := bracketfixture.NewTeam("Lions", logo)
+	opts := DefaultOptions
+	opts.LogoDir = filepath.Dir(logo)
+	l := NewLayout([][][]models.PlayoffsModel{{bracketfixture.Series(1, "FINAL", &lions, nil, 1)}}, opts)
+
+	var out bytes.Buffer
+	require.NoError(t, WritePDF(&out, l, Title("2023-2024", ""), ""))
+
+	assert.True(t, bytes.HasPrefix(out.Bytes(), []byte("%PDF-")))
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("/Type /Page\n")))
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("/Subtype /Image")))
+	assert.ErrorIs(t, WritePDF(io.Discard, l, "", "B9"), ErrInvalidPageSize)
+}
+
+func TestWritePDF_SplitsLargeBracketsBetweenSeries(t *testing.T) {
+	l := NewLayout(emptyBracket(64), DefaultOptions)
+
+	var out bytes.Buffer
+	require.NoError(t, WritePDF(&out, l, "", "A4"))
+
+	assert.Greater(t, bytes.Count(out.Bytes(), []byte("/Type /Page\n")), 2)
+
+	pages := pdfPages(l, 1000, 500)
+	assert.Len(t, pages, 15)
+	for _, page := range pages {
+		for _, b := range l.Boxes {
+			printed := b.X >= page.x && b.X < page.x+page.width
+			if printed && b.Y < page.y+page.height && b.Y+b.Height > page.y {
+				assert.True(t, b.Y >= page.y && b.Y+b.Height <= page.y+page.height, "series %s is cut by page %+v", b.Label, page)
+			}
+		}
+	}
+}
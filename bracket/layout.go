@@ -0,0 +1,204 @@
+// PACKAGE bracket LAYS OUT THE BRACKET RETURNED BY ListPlayoffs AS BOXES AND CONNECTORS IN ABSTRACT UNITS,
+// SO EVERY OUTPUT FORMAT DRAWS THE SAME PICTURE
+package bracket
+
+import (
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// ONE SIDE OF A SERIES
+type Team struct {
+	Id      *uuid.UUID
+	Name    string
+	LogoURL string
+	Wins    int
+	// SET ON THE TEAM THAT WON THE SERIES
+	Winner bool
+}
+
+// THE GAMES OF ONE SERIES SUMMED UP. A SERIES IS DECIDED WHEN A TEAM WON MORE THAN HALF OF ITS GAMES
+type Series struct {
+	Label   string
+	Final   bool
+	Home    Team
+	Away    Team
+	Decided bool
+	Games   []models.PlayoffsModel
+}
+
+func teamOf(id *uuid.UUID, name *string, url *string) Team {
+	t := Team{Id: id}
+	if name != nil {
+		t.Name = *name
+	}
+	if url != nil {
+		t.LogoURL = *url
+	}
+	return t
+}
+
+// SUMS UP THE GAMES OF A SERIES AS LISTED BY ListPlayoffs. THE TEAMS ARE TAKEN FROM THE FIRST GAME
+func Summarize(games []models.PlayoffsModel) Series {
+	s := Series{Games: games}
+	if len(games) == 0 {
+		return s
+	}
+	first := games[0]
+	s.Final = first.GameCount != nil && *first.GameCount == "FINAL"
+	switch {
+	case s.Final:
+		s.Label = "FINAL"
+	case first.FixtureRound != nil && first.GameCount != nil:
+		s.Label = "R" + strconv.Itoa(*first.FixtureRound) + " #" + *first.GameCount
+	}
+	s.Home = teamOf(first.HomeTeamId, first.HomeTeamName, first.HomeTeamURL)
+	s.Away = teamOf(first.AwayTeamId, first.AwayTeamName, first.AwayTeamURL)
+	for _, g := range games {
+		switch {
+		case g.Winner == nil:
+		case s.Home.Id != nil && *g.Winner == *s.Home.Id:
+			s.Home.Wins++
+		case s.Away.Id != nil && *g.Winner == *s.Away.Id:
+			s.Away.Wins++
+		}
+	}
+	needed := len(games)/2 + 1
+	s.Home.Winner = s.Home.Wins >= needed
+	s.Away.Winner = s.Away.Wins >= needed
+	s.Decided = s.Home.Winner || s.Away.Winner
+	return s
+}
+
+// SIZES OF THE LAYOUT, IN THE UNITS OF THE OUTPUT (PIXELS FOR SVG AND PNG, POINTS FOR PDF)
+type Options struct {
+	BoxWidth  float64
+	BoxHeight float64
+	// HORIZONTAL SPACE BETWEEN ROUNDS, WHERE THE CONNECTORS ARE DRAWN
+	ColumnGap float64
+	// VERTICAL SPACE BETWEEN TWO SERIES OF THE FIRST ROUND
+	RowGap float64
+	Margin float64
+	// SPACE ABOVE THE FIRST ROUND FOR THE TITLE AND ROUND NAMES
+	HeaderHeight float64
+	// DIRECTORY THE PNG AND PDF EXPORTS READ THE LOGOS OF LOCAL FILES FROM. EMPTY, AS IN DefaultOptions, LEAVES
+	// THEM OUT
+	LogoDir string
+}
+
+var DefaultOptions = Options{
+	BoxWidth:     220,
+	BoxHeight:    64,
+	ColumnGap:    48,
+	RowGap:       24,
+	Margin:       24,
+	HeaderHeight: 64,
+}
+
+// A SERIES PLACED ON THE PAGE. (X, Y) IS ITS TOP LEFT CORNER
+type Box struct {
+	Series
+	Round    int
+	Position int
+	X        float64
+	Y        float64
+	Width    float64
+	Height   float64
+}
+
+// AN ELBOW LINE FROM THE RIGHT SIDE OF A SERIES TO THE LEFT SIDE OF THE SERIES ITS WINNER ADVANCES TO
+type Connector struct {
+	FromX, FromY float64
+	ToX, ToY     float64
+	// SET WHEN THE SERIES IS DECIDED, SO THE PATH OF THE WINNER CAN BE HIGHLIGHTED
+	Decided bool
+}
+
+// A ROUND NAME CENTERED ABOVE ITS COLUMN
+type RoundTitle struct {
+	Text string
+	X    float64
+	Y    float64
+}
+
+type Layout struct {
+	Width      float64
+	Height     float64
+	Options    Options
+	Rounds     []RoundTitle
+	Boxes      []Box
+	Connectors []Connector
+	// THE TEAM THAT WON THE FINAL, NIL UNTIL IT IS PLAYED
+	Champion *Team
+}
+
+// PLACES EVERY SERIES OF THE BRACKET. ROUNDS ARE COLUMNS FROM LEFT TO RIGHT AND THE SERIES AT POSITION p OF A ROUND
+// IS CENTERED BETWEEN THE SERIES 2p AND 2p+1 OF THE PREVIOUS ROUND THAT FEED IT
+func NewLayout(playoffs [][][]models.PlayoffsModel, opts Options) Layout {
+	l := Layout{Options: opts}
+	centers := make([][]float64, len(playoffs))
+	boxes := make([][]int, len(playoffs))
+	top := opts.Margin + opts.HeaderHeight
+	bottom := top
+	for r, round := range playoffs {
+		x := opts.Margin + float64(r)*(opts.BoxWidth+opts.ColumnGap)
+		title := "Round " + strconv.Itoa(r+1)
+		centers[r] = make([]float64, len(round))
+		for p, games := range round {
+			center := top + float64(p)*(opts.BoxHeight+opts.RowGap) + opts.BoxHeight/2
+			if r > 0 && 2*p < len(centers[r-1]) {
+				center = centers[r-1][2*p]
+				if 2*p+1 < len(centers[r-1]) {
+					center = (center + centers[r-1][2*p+1]) / 2
+				}
+			}
+			centers[r][p] = center
+			s := Summarize(games)
+			if s.Final {
+				title = "Final"
+				if s.Decided {
+					champion := s.Home
+					if s.Away.Winner {
+						champion = s.Away
+					}
+					l.Champion = &champion
+				}
+			}
+			boxes[r] = append(boxes[r], len(l.Boxes))
+			l.Boxes = append(l.Boxes, Box{
+				Series:   s,
+				Round:    r,
+				Position: p,
+				X:        x,
+				Y:        center - opts.BoxHeight/2,
+				Width:    opts.BoxWidth,
+				Height:   opts.BoxHeight,
+			})
+			if y := center + opts.BoxHeight/2; y > bottom {
+				bottom = y
+			}
+		}
+		l.Rounds = append(l.Rounds, RoundTitle{Text: title, X: x + opts.BoxWidth/2, Y: opts.Margin + opts.HeaderHeight - opts.RowGap/2})
+	}
+	for r := 0; r+1 < len(boxes); r++ {
+		for p, i := range boxes[r] {
+			if p/2 >= len(boxes[r+1]) {
+				continue
+			}
+			from, to := l.Boxes[i], l.Boxes[boxes[r+1][p/2]]
+			l.Connectors = append(l.Connectors, Connector{
+				FromX:   from.X + from.Width,
+				FromY:   from.Y + from.Height/2,
+				ToX:     to.X,
+				ToY:     to.Y + to.Height/2,
+				Decided: from.Decided,
+			})
+		}
+	}
+	l.Width = 2*opts.Margin + float64(len(playoffs))*opts.BoxWidth + float64(max(len(playoffs)-1, 0))*opts.ColumnGap
+	l.Height = bottom + opts.Margin
+	return l
+}
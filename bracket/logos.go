@@ -0,0 +1,91 @@
+package bracket
+
+import (
+	"image"
+	// DECODERS OF THE LOGO FORMATS THAT CAN BE EMBEDDED
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RETURNS THE PATH OF A LOGO URL THAT POINTS TO A FILE UNDER dir (file:///path OR A PLAIN PATH, RELATIVE TO dir).
+// LOGOS ON OTHER HOSTS ARE NOT FETCHED, SO THE PNG AND PDF EXPORTS WORK OFFLINE, AND NO FILE IS READ WHEN dir IS
+// EMPTY SINCE THE URLS ARE WRITTEN BY THE CLIENTS OF THE API
+func localLogoPath(dir string, logoURL string) (string, bool) {
+	if dir == "" || logoURL == "" {
+		return "", false
+	}
+	path := logoURL
+	if strings.Contains(logoURL, "://") {
+		u, err := url.Parse(logoURL)
+		if err != nil || u.Scheme != "file" || (u.Host != "" && u.Host != "localhost") || u.Path == "" {
+			return "", false
+		}
+		path = u.Path
+	}
+	dir = filepath.Clean(dir)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	path = filepath.Clean(path)
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path, true
+}
+
+// DECODES THE LOGOS OF THE REGULAR FILES UNDER dir ONCE. LOGOS THAT CANNOT BE READ ARE LEFT OUT OF THE DRAWING
+type logoCache struct {
+	dir    string
+	images map[string]image.Image
+}
+
+func newLogoCache(dir string) *logoCache {
+	return &logoCache{dir: dir, images: map[string]image.Image{}}
+}
+
+func (c *logoCache) load(logoURL string) (image.Image, bool) {
+	if img, ok := c.images[logoURL]; ok {
+		return img, img != nil
+	}
+	c.images[logoURL] = nil
+	path, ok := localLogoPath(c.dir, logoURL)
+	if !ok {
+		return nil, false
+	}
+	// A FIFO OR A DEVICE WOULD BLOCK OR NEVER END, AND A SYMBOLIC LINK MAY LEAVE dir
+	if info, err := os.Lstat(path); err != nil || !info.Mode().IsRegular() {
+		return nil, false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Println("failed to open logo: ", err.Error())
+		return nil, false
+	}
+	defer f.Close()
+	img, _, errD := image.Decode(f)
+	if errD != nil {
+		log.Println("failed to decode logo ", path, ": ", errD.Error())
+		return nil, false
+	}
+	c.images[logoURL] = img
+	return img, true
+}
+
+// RETURNS THE RECTANGLE OF SIDE size AT (x, y) THE LOGO FITS IN WITHOUT BEING STRETCHED
+func fitLogo(img image.Image, x, y, size float64) (float64, float64, float64, float64) {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	if w == 0 || h == 0 {
+		return x, y, 0, 0
+	}
+	scale := size / max(w, h)
+	w, h = w*scale, h*scale
+	return x + (size-w)/2, y + (size-h)/2, w, h
+}
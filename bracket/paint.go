@@ -0,0 +1,119 @@
+package bracket
+
+import (
+	"image/color"
+	"strconv"
+)
+
+// COLORS OF THE BRACKET IN EVERY FORMAT. WINNERS ARE DRAWN IN THE ACCENT COLOR
+const (
+	colorBackground = "#ffffff"
+	colorBorder     = "#c8ccd4"
+	colorText       = "#1f2430"
+	colorMuted      = "#7a8194"
+	colorAccent     = "#0b6e4f"
+	colorWinnerFill = "#e3f4ec"
+)
+
+// SHOWN FOR A SLOT THE PREVIOUS ROUND HAS NOT FILLED YET
+const tbd = "TBD"
+
+// HORIZONTAL ALIGNMENT OF A TEXT ON ITS x COORDINATE
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// THE DRAWING PRIMITIVES EVERY OUTPUT FORMAT IMPLEMENTS, IN LAYOUT UNITS. COLORS ARE "#rrggbb" AND AN EMPTY
+// COLOR IS NOT DRAWN. TEXT IS PLACED ON ITS BASELINE
+type painter interface {
+	rect(x, y, width, height, radius float64, fill string, stroke string)
+	// THE SEGMENTS OF A POLYLINE ARE ALWAYS HORIZONTAL OR VERTICAL
+	polyline(points [][2]float64, width float64, stroke string)
+	text(x, y, size float64, bold bool, a anchor, fill string, text string)
+	logo(x, y, size float64, url string)
+}
+
+// DRAWS THE TITLE, CHAMPION, ROUND NAMES, CONNECTORS AND SERIES OF THE LAYOUT
+func paint(p painter, l Layout, title string) {
+	o := l.Options
+	font := o.BoxHeight / 2 * 0.45
+
+	p.rect(0, 0, l.Width, l.Height, 0, colorBackground, "")
+	p.text(o.Margin, o.Margin+font*1.6, font*1.6, true, anchorStart, colorText, title)
+	if l.Champion != nil {
+		p.text(l.Width-o.Margin, o.Margin+font*1.6, font*1.3, true, anchorEnd, colorAccent, "Champion: "+teamName(*l.Champion))
+	}
+	paintBody(p, l)
+}
+
+// DRAWS EVERYTHING BELOW THE HEADER OF THE LAYOUT
+func paintBody(p painter, l Layout) {
+	o := l.Options
+	row := o.BoxHeight / 2
+	font := row * 0.45
+
+	for _, r := range l.Rounds {
+		p.text(r.X, r.Y, font, false, anchorMiddle, colorMuted, r.Text)
+	}
+	for _, c := range l.Connectors {
+		stroke, width := colorBorder, 1.5
+		if c.Decided {
+			stroke, width = colorAccent, 2.5
+		}
+		midX := (c.FromX + c.ToX) / 2
+		p.polyline([][2]float64{{c.FromX, c.FromY}, {midX, c.FromY}, {midX, c.ToY}, {c.ToX, c.ToY}}, width, stroke)
+	}
+	for _, b := range l.Boxes {
+		if b.Label != "" && !b.Final {
+			p.text(b.X+2, b.Y-4, font*0.8, false, anchorStart, colorMuted, b.Label)
+		}
+		p.rect(b.X, b.Y, b.Width, b.Height, 6, colorBackground, colorBorder)
+		for i, t := range []Team{b.Home, b.Away} {
+			paintTeamRow(p, b, t, b.Y+float64(i)*row, row, font)
+		}
+		p.polyline([][2]float64{{b.X, b.Y + row}, {b.X + b.Width, b.Y + row}}, 1, colorBorder)
+	}
+}
+
+// DRAWS ONE TEAM OF A SERIES: ITS LOGO, NAME AND WINS, HIGHLIGHTED WHEN IT WON THE SERIES
+func paintTeamRow(p painter, b Box, t Team, y float64, row float64, font float64) {
+	logo := row * 0.75
+	pad := (row - logo) / 2
+	if t.Winner {
+		p.rect(b.X+1, y+1, b.Width-2, row-2, 6, colorWinnerFill, "")
+	}
+	nameX := b.X + pad
+	if t.LogoURL != "" {
+		p.logo(b.X+pad, y+pad, logo, t.LogoURL)
+		nameX += logo + pad
+	}
+	fill := colorText
+	if t.Winner {
+		fill = colorAccent
+	}
+	if t.Id == nil {
+		fill = colorMuted
+	}
+	baseline := y + row/2 + font*0.35
+	p.text(nameX, baseline, font, t.Winner, anchorStart, fill, teamName(t))
+	if t.Id != nil {
+		p.text(b.X+b.Width-pad*2, baseline, font, t.Winner, anchorEnd, fill, strconv.Itoa(t.Wins))
+	}
+}
+
+func teamName(t Team) string {
+	if t.Id == nil || t.Name == "" {
+		return tbd
+	}
+	return t.Name
+}
+
+// PARSES A "#rrggbb" COLOR OF THE PALETTE
+func parseColor(hex string) color.RGBA {
+	v, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
@@ -0,0 +1,242 @@
+package bracket
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// PAGE SIZES ACCEPTED BY WritePDF. PAGES ARE ALWAYS LANDSCAPE
+var PDFPageSizes = []string{"A3", "A4", "A5", "Letter", "Legal"}
+
+var ErrInvalidPageSize = errors.New("invalid page size, expected one of " + strings.Join(PDFPageSizes, ", "))
+
+// SPACE AROUND THE PRINTED BRACKET AND HEIGHT OF THE HEADER REPEATED ON EVERY PAGE, IN POINTS
+const (
+	pdfMargin       = 36
+	pdfHeaderHeight = 44
+)
+
+const pdfFont = "go"
+
+type pdfPainter struct {
+	pdf    *fpdf.Fpdf
+	scale  float64
+	ox, oy float64
+	logos  *logoCache
+	// LOGO URLS ALREADY ADDED TO THE DOCUMENT, AND WHETHER THEY COULD BE LOADED
+	registered map[string]bool
+}
+
+// WRITES THE LAYOUT AS A PDF FOR PRINTING ON LANDSCAPE PAGES OF pageSize (A4 WHEN EMPTY). THE BRACKET IS SCALED
+// DOWN TO THE WIDTH OF THE PAGE, BUT NOT BELOW pdfMinScale: WIDER BRACKETS ARE PRINTED A FEW ROUNDS PER PAGE, AND
+// TALL ONES CONTINUE ON THE NEXT PAGES, CUTTING BETWEEN SERIES. EVERY PAGE REPEATS THE TITLE AND ITS NUMBER.
+// LOGOS ARE EMBEDDED WHEN THEIR URLS POINT TO LOCAL FILES
+func WritePDF(w io.Writer, l Layout, title string, pageSize string) error {
+	if pageSize == "" {
+		pageSize = "A4"
+	}
+	known := false
+	for _, s := range PDFPageSizes {
+		if strings.EqualFold(s, pageSize) {
+			known = true
+		}
+	}
+	if !known {
+		return ErrInvalidPageSize
+	}
+
+	pdf := fpdf.New("L", "pt", pageSize, "")
+	pdf.SetTitle(title, true)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddUTF8FontFromBytes(pdfFont, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", gobold.TTF)
+	pdf.SetLineCapStyle("square")
+	pageW, pageH := pdf.GetPageSize()
+
+	contentW, contentH := pageW-2*pdfMargin, pageH-2*pdfMargin-pdfHeaderHeight
+	scale := min(1, max(contentW/l.Width, pdfMinScale))
+	pages := pdfPages(l, contentW/scale, contentH/scale)
+
+	p := &pdfPainter{pdf: pdf, scale: scale, logos: newLogoCache(l.Options.LogoDir), registered: map[string]bool{}}
+	top := float64(pdfMargin + pdfHeaderHeight)
+	for i, page := range pages {
+		pdf.AddPage()
+		p.header(title, l.Champion, i, len(pages), pageW)
+		p.ox = pdfMargin - page.x*scale
+		if page.x == 0 && page.width >= l.Width {
+			p.ox = (pageW - l.Width*scale) / 2
+		}
+		p.oy = top - page.y*scale
+		pdf.ClipRect(pdfMargin, top, contentW, page.height*scale, false)
+		paintBody(p, l)
+		pdf.ClipEnd()
+	}
+	return pdf.Output(w)
+}
+
+// SMALLEST SCALE THE TEXT OF THE BRACKET STAYS READABLE AT ONCE PRINTED
+const pdfMinScale = 0.5
+
+// THE PART OF THE LAYOUT PRINTED ON ONE PAGE
+type pdfPage struct {
+	x, y          float64
+	width, height float64
+}
+
+// SPLITS THE LAYOUT IN PAGES OF AT MOST width x height UNITS. EACH PAGE HOLDS WHOLE ROUNDS, AND ENDS JUST BELOW
+// THE LOWEST SERIES OF THOSE ROUNDS THAT FITS UNLESS NO SERIES FITS AND ONE HAS TO BE CUT. THE HEADER OF THE
+// LAYOUT IS REPLACED BY THE HEADER OF THE PAGES, SO ONLY THE ROUND NAMES ARE KEPT ABOVE THE FIRST SERIES
+func pdfPages(l Layout, width float64, height float64) []pdfPage {
+	o := l.Options
+	column := o.BoxWidth + o.ColumnGap
+	perPage := max(int((width-o.Margin+o.ColumnGap)/column), 1)
+	rounds := len(l.Rounds)
+	bottom := l.Height - o.Margin
+	var pages []pdfPage
+	for first := 0; first < rounds || first == 0; first += perPage {
+		x := 0.0
+		if first > 0 {
+			x = o.Margin + float64(first)*column - o.ColumnGap/2
+		}
+		right := min(o.Margin+float64(first+perPage)*column-o.ColumnGap/2, l.Width)
+		var boxes []Box
+		for _, b := range l.Boxes {
+			if b.Round >= first && b.Round < first+perPage {
+				boxes = append(boxes, b)
+			}
+		}
+		for start := o.Margin + o.HeaderHeight/2; start < bottom; {
+			end := start + height
+			if end < bottom {
+				cut := 0.0
+				for _, b := range boxes {
+					candidate := b.Y + b.Height + 1
+					if candidate <= start || candidate > end || candidate <= cut || insideBox(boxes, candidate) {
+						continue
+					}
+					cut = candidate
+				}
+				if cut > 0 {
+					end = cut
+				}
+			}
+			end = min(end, bottom)
+			// A BAND WITHOUT SERIES ONLY HAS CONNECTORS LEADING TO OTHER PAGES, SO IT IS NOT PRINTED
+			if len(pages) == 0 || anyBox(boxes, start, end) {
+				pages = append(pages, pdfPage{x: x, y: start, width: right - x, height: end - start})
+			}
+			start = end
+		}
+	}
+	return pages
+}
+
+func anyBox(boxes []Box, from float64, to float64) bool {
+	for _, b := range boxes {
+		if b.Y < to && b.Y+b.Height > from {
+			return true
+		}
+	}
+	return false
+}
+
+func insideBox(boxes []Box, y float64) bool {
+	for _, b := range boxes {
+		if y > b.Y && y < b.Y+b.Height {
+			return true
+		}
+	}
+	return false
+}
+
+// DRAWS THE TITLE, CHAMPION AND PAGE NUMBER ABOVE THE BRACKET
+func (p *pdfPainter) header(title string, champion *Team, page int, pages int, pageW float64) {
+	baseline := pdfMargin + 18.0
+	p.setColor(colorText, p.pdf.SetTextColor)
+	p.pdf.SetFont(pdfFont, "B", 18)
+	p.pdf.Text(pdfMargin, baseline, title)
+	p.setColor(colorMuted, p.pdf.SetTextColor)
+	p.pdf.SetFont(pdfFont, "", 10)
+	number := "Page " + strconv.Itoa(page+1) + " of " + strconv.Itoa(pages)
+	p.pdf.Text(pageW-pdfMargin-p.pdf.GetStringWidth(number), baseline, number)
+	if champion != nil {
+		p.setColor(colorAccent, p.pdf.SetTextColor)
+		p.pdf.SetFont(pdfFont, "B", 12)
+		p.pdf.Text(pdfMargin, baseline+18, "Champion: "+teamName(*champion))
+	}
+}
+
+func (p *pdfPainter) setColor(hex string, set func(r, g, b int)) {
+	c := parseColor(hex)
+	set(int(c.R), int(c.G), int(c.B))
+}
+
+func (p *pdfPainter) rect(x, y, width, height, radius float64, fill string, stroke string) {
+	style := ""
+	if fill != "" {
+		p.setColor(fill, p.pdf.SetFillColor)
+		style += "F"
+	}
+	if stroke != "" {
+		p.setColor(stroke, p.pdf.SetDrawColor)
+		p.pdf.SetLineWidth(1)
+		style += "D"
+	}
+	p.pdf.RoundedRect(p.ox+x*p.scale, p.oy+y*p.scale, width*p.scale, height*p.scale, radius*p.scale, "1234", style)
+}
+
+func (p *pdfPainter) polyline(points [][2]float64, width float64, stroke string) {
+	p.setColor(stroke, p.pdf.SetDrawColor)
+	p.pdf.SetLineWidth(width * p.scale)
+	for i := 1; i < len(points); i++ {
+		p.pdf.Line(p.ox+points[i-1][0]*p.scale, p.oy+points[i-1][1]*p.scale, p.ox+points[i][0]*p.scale, p.oy+points[i][1]*p.scale)
+	}
+}
+
+func (p *pdfPainter) text(x, y, size float64, bold bool, a anchor, fill string, text string) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	p.pdf.SetFont(pdfFont, style, size*p.scale)
+	p.setColor(fill, p.pdf.SetTextColor)
+	px := p.ox + x*p.scale
+	switch a {
+	case anchorMiddle:
+		px -= p.pdf.GetStringWidth(text) / 2
+	case anchorEnd:
+		px -= p.pdf.GetStringWidth(text)
+	}
+	p.pdf.Text(px, p.oy+y*p.scale, text)
+}
+
+func (p *pdfPainter) logo(x, y, size float64, url string) {
+	img, ok := p.logos.load(url)
+	if !ok {
+		return
+	}
+	// EVERY LOGO IS ADDED ONCE AS A PNG, WHATEVER ITS FORMAT, AND REFERENCED BY ITS URL AFTERWARDS
+	if _, done := p.registered[url]; !done {
+		var buf bytes.Buffer
+		p.registered[url] = png.Encode(&buf, img) == nil
+		if p.registered[url] {
+			p.pdf.RegisterImageOptionsReader(url, fpdf.ImageOptions{ImageType: "PNG"}, &buf)
+		}
+	}
+	if !p.registered[url] {
+		return
+	}
+	lx, ly, lw, lh := fitLogo(img, x, y, size)
+	if lw == 0 {
+		return
+	}
+	p.pdf.ImageOptions(url, p.ox+lx*p.scale, p.oy+ly*p.scale, lw*p.scale, lh*p.scale, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+}
@@ -0,0 +1,185 @@
+package bracket
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// LARGEST SIDE OF A PNG, SO A MISTYPED SIZE DOES NOT ALLOCATE GIGABYTES. AN IMAGE OF THAT SIZE STILL TAKES 256MB,
+// SO CALLERS SERVING UNTRUSTED REQUESTS SET A LOWER LIMIT
+const MaxPNGSize = 8192
+
+var ErrInvalidSize = errors.New("invalid image size")
+
+// THE GO FONTS ARE BUNDLED WITH x/image, SO TEXT IS DRAWN THE SAME WAY ON EVERY MACHINE
+var (
+	fontsOnce sync.Once
+	regular   *opentype.Font
+	bold      *opentype.Font
+	fontsErr  error
+)
+
+func loadFonts() error {
+	fontsOnce.Do(func() {
+		regular, fontsErr = opentype.Parse(goregular.TTF)
+		if fontsErr == nil {
+			bold, fontsErr = opentype.Parse(gobold.TTF)
+		}
+	})
+	return fontsErr
+}
+
+type faceKey struct {
+	size float64
+	bold bool
+}
+
+type pngPainter struct {
+	img    *image.RGBA
+	scale  float64
+	ox, oy float64
+	faces  map[faceKey]font.Face
+	logos  *logoCache
+	err    error
+}
+
+// WRITES THE LAYOUT AS A PNG OF width x height PIXELS. THE BRACKET IS SCALED TO FIT AND CENTERED; A HEIGHT OF 0
+// KEEPS THE ASPECT RATIO OF THE LAYOUT AND A WIDTH OF 0 DRAWS IT AT ITS OWN SIZE. LOGOS ARE EMBEDDED WHEN
+// THEIR URLS POINT TO FILES UNDER THE LogoDir OF THE LAYOUT
+func WritePNG(w io.Writer, l Layout, title string, width int, height int) error {
+	if width <= 0 {
+		width = int(math.Ceil(l.Width))
+	}
+	if height <= 0 {
+		height = int(math.Ceil(float64(width) * l.Height / l.Width))
+	}
+	if width > MaxPNGSize || height > MaxPNGSize || height <= 0 {
+		return ErrInvalidSize
+	}
+	if err := loadFonts(); err != nil {
+		return err
+	}
+	scale := min(float64(width)/l.Width, float64(height)/l.Height)
+	p := &pngPainter{
+		img:   image.NewRGBA(image.Rect(0, 0, width, height)),
+		scale: scale,
+		ox:    (float64(width) - l.Width*scale) / 2,
+		oy:    (float64(height) - l.Height*scale) / 2,
+		faces: map[faceKey]font.Face{},
+		logos: newLogoCache(l.Options.LogoDir),
+	}
+	draw.Draw(p.img, p.img.Bounds(), image.NewUniform(parseColor(colorBackground)), image.Point{}, draw.Src)
+	paint(p, l, title)
+	for _, face := range p.faces {
+		face.Close()
+	}
+	if p.err != nil {
+		return p.err
+	}
+	return png.Encode(w, p.img)
+}
+
+// CONVERTS A RECTANGLE OF THE LAYOUT TO PIXELS
+func (p *pngPainter) pixels(x, y, width, height float64) image.Rectangle {
+	return image.Rect(
+		int(math.Round(p.ox+x*p.scale)), int(math.Round(p.oy+y*p.scale)),
+		int(math.Round(p.ox+(x+width)*p.scale)), int(math.Round(p.oy+(y+height)*p.scale)),
+	)
+}
+
+// FILLS r WITH c, LEAVING OUT THE CORNERS OUTSIDE OF THE RADIUS
+func (p *pngPainter) fill(r image.Rectangle, radius int, c color.RGBA) {
+	r = r.Intersect(p.img.Bounds())
+	radius = min(radius, r.Dx()/2, r.Dy()/2)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dx := max(r.Min.X+radius-x, x-(r.Max.X-1-radius), 0)
+			dy := max(r.Min.Y+radius-y, y-(r.Max.Y-1-radius), 0)
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			p.img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func (p *pngPainter) rect(x, y, width, height, radius float64, fill string, stroke string) {
+	r := p.pixels(x, y, width, height)
+	rad := int(math.Round(radius * p.scale))
+	if stroke != "" {
+		p.fill(r, rad, parseColor(stroke))
+		r = r.Inset(1)
+		rad = max(rad-1, 0)
+	}
+	if fill != "" {
+		p.fill(r, rad, parseColor(fill))
+	}
+}
+
+func (p *pngPainter) polyline(points [][2]float64, width float64, stroke string) {
+	c := parseColor(stroke)
+	half := width / 2
+	for i := 1; i < len(points); i++ {
+		x0, y0 := min(points[i-1][0], points[i][0]), min(points[i-1][1], points[i][1])
+		x1, y1 := max(points[i-1][0], points[i][0]), max(points[i-1][1], points[i][1])
+		p.fill(p.pixels(x0-half, y0-half, x1-x0+width, y1-y0+width), 0, c)
+	}
+}
+
+func (p *pngPainter) face(size float64, isBold bool) font.Face {
+	key := faceKey{size: size * p.scale, bold: isBold}
+	if face, ok := p.faces[key]; ok {
+		return face
+	}
+	f := regular
+	if isBold {
+		f = bold
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: key.size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		if p.err == nil {
+			p.err = err
+		}
+		return nil
+	}
+	p.faces[key] = face
+	return face
+}
+
+func (p *pngPainter) text(x, y, size float64, isBold bool, a anchor, fill string, text string) {
+	face := p.face(size, isBold)
+	if face == nil {
+		return
+	}
+	d := &font.Drawer{Dst: p.img, Src: image.NewUniform(parseColor(fill)), Face: face}
+	px := p.ox + x*p.scale
+	switch a {
+	case anchorMiddle:
+		px -= float64(d.MeasureString(text)) / 64 / 2
+	case anchorEnd:
+		px -= float64(d.MeasureString(text)) / 64
+	}
+	d.Dot = fixed.P(int(math.Round(px)), int(math.Round(p.oy+y*p.scale)))
+	d.DrawString(text)
+}
+
+func (p *pngPainter) logo(x, y, size float64, url string) {
+	img, ok := p.logos.load(url)
+	if !ok {
+		return
+	}
+	lx, ly, lw, lh := fitLogo(img, x, y, size)
+	draw.CatmullRom.Scale(p.img, p.pixels(lx, ly, lw, lh), img, img.Bounds(), draw.Over, nil)
+}
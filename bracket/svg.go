@@ -0,0 +1,91 @@
+package bracket
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+)
+
+// THE TITLE DRAWN ON THE BRACKET OF A SEASON
+func Title(season string, competition string) string {
+	if competition == "" || competition == queries.DefaultCompetition {
+		return "Playoffs " + season
+	}
+	return "Playoffs " + season + " · " + competition
+}
+
+type svgWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (s *svgWriter) printf(format string, args ...any) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = fmt.Fprintf(s.w, format, args...)
+}
+
+func escape(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// WRITES THE LAYOUT AS A STANDALONE SVG DOCUMENT. LOGOS ARE LINKED FROM THEIR URLS, NOT EMBEDDED
+func WriteSVG(w io.Writer, l Layout, title string) error {
+	s := &svgWriter{w: bufio.NewWriter(w)}
+	s.printf(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%g" height="%g" viewBox="0 0 %g %g" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		l.Width, l.Height, l.Width, l.Height)
+	paint(s, l, title)
+	s.printf("</svg>\n")
+	if s.err != nil {
+		return s.err
+	}
+	return s.w.Flush()
+}
+
+func (s *svgWriter) rect(x, y, width, height, radius float64, fill string, stroke string) {
+	attrs := ""
+	if radius > 0 {
+		attrs += fmt.Sprintf(` rx="%g"`, radius)
+	}
+	if stroke != "" {
+		attrs += ` stroke="` + stroke + `"`
+	}
+	s.printf(`<rect x="%g" y="%g" width="%g" height="%g"%s fill="%s"/>`+"\n", x, y, width, height, attrs, fill)
+}
+
+func (s *svgWriter) polyline(points [][2]float64, width float64, stroke string) {
+	var d strings.Builder
+	for i, pt := range points {
+		if i == 0 {
+			fmt.Fprintf(&d, "M%g %g", pt[0], pt[1])
+		} else {
+			fmt.Fprintf(&d, "L%g %g", pt[0], pt[1])
+		}
+	}
+	s.printf(`<path d="%s" fill="none" stroke="%s" stroke-width="%g"/>`+"\n", d.String(), stroke, width)
+}
+
+func (s *svgWriter) text(x, y, size float64, bold bool, a anchor, fill string, text string) {
+	weight, attrs := "normal", ""
+	if bold {
+		weight = "bold"
+	}
+	switch a {
+	case anchorMiddle:
+		attrs = ` text-anchor="middle"`
+	case anchorEnd:
+		attrs = ` text-anchor="end"`
+	}
+	s.printf(`<text x="%g" y="%g" font-size="%g" font-weight="%s"%s fill="%s">%s</text>`+"\n", x, y, size, weight, attrs, fill, escape(text))
+}
+
+func (s *svgWriter) logo(x, y, size float64, url string) {
+	s.printf(`<image x="%g" y="%g" width="%g" height="%g" preserveAspectRatio="xMidYMid meet" xlink:href="%s"/>`+"\n", x, y, size, size, escape(url))
+}
@@ -0,0 +1,190 @@
+// PACKAGE calendar WRITES THE SCHEDULED PLAYOFF GAMES AS AN iCalendar (RFC 5545) FEED THAT CALENDAR APPLICATIONS
+// SUBSCRIBE TO. EVERY GAME KEEPS ITS UID AND ITS SEQUENCE FOLLOWS THE VERSION OF THE ROW, SO WHEN A RESULT FILLS A
+// NEXT ROUND SLOT THE SUBSCRIBED CALENDARS REPLACE "TBD vs Lions" WITH "Tigers vs Lions"
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// LENGTH OF THE EVENT OF A GAME, THE ROWS ONLY HOLD WHEN IT STARTS
+const GameDuration = 2 * time.Hour
+
+const (
+	prodId = "-//GO_CODE_SAMPLE//Playoffs//EN"
+	// DOMAIN PART OF THE UID OF THE EVENTS
+	uidDomain = "playoffs"
+	// RFC 5545 LINES ARE FOLDED AFTER 75 OCTETS
+	lineLimit = 75
+	// HOW OFTEN SUBSCRIBED CALENDARS ARE ASKED TO REFRESH
+	refreshInterval = "PT1H"
+)
+
+const tbd = "TBD"
+
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// WRITES ONE CONTENT LINE, FOLDED AND TERMINATED BY CRLF. A CONTINUATION LINE STARTS WITH A SPACE THAT COUNTS
+// IN ITS OCTETS, AND LINES ARE NEVER CUT INSIDE A UTF-8 CHARACTER
+func (w *writer) line(name string, value string) {
+	if w.err != nil {
+		return
+	}
+	l := name + ":" + value
+	var b strings.Builder
+	for limit := lineLimit; len(l) > limit; limit = lineLimit - 1 {
+		cut := limit
+		for !utf8.RuneStart(l[cut]) {
+			cut--
+		}
+		b.WriteString(l[:cut] + "\r\n ")
+		l = l[cut:]
+	}
+	b.WriteString(l + "\r\n")
+	_, w.err = w.w.WriteString(b.String())
+}
+
+// ESCAPES A TEXT VALUE
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+func stamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// WRITES THE SCHEDULED GAMES AS A CALENDAR CALLED name. GAMES WITHOUT A DATE ARE LEFT OUT, AND A GAME A DECIDED SERIES
+// DOES NOT NEED ANYMORE IS KEPT AS CANCELLED SO SUBSCRIBED CALENDARS REMOVE IT. now IS THE DTSTAMP OF THE EVENTS
+func WriteICS(out io.Writer, name string, games []models.PlayoffsModel, now time.Time) error {
+	w := &writer{w: bufio.NewWriter(out)}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", prodId)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.line("X-WR-CALNAME", escape(name))
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", refreshInterval)
+	w.line("X-PUBLISHED-TTL", refreshInterval)
+
+	decided := decidedSeries(games)
+	for _, g := range games {
+		if g.ScheduledAt == nil {
+			continue
+		}
+		status := "CONFIRMED"
+		if g.Winner == nil && decided[seriesKeyOf(g)] {
+			status = "CANCELLED"
+		}
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", g.PlayoffsId.String()+"@"+uidDomain)
+		w.line("SEQUENCE", strconv.Itoa(g.Version))
+		w.line("DTSTAMP", stamp(now))
+		w.line("DTSTART", stamp(*g.ScheduledAt))
+		w.line("DTEND", stamp(g.ScheduledAt.Add(GameDuration)))
+		w.line("SUMMARY", escape(Summary(g)))
+		w.line("DESCRIPTION", escape(description(g)))
+		w.line("STATUS", status)
+		w.line("END", "VEVENT")
+	}
+	w.line("END", "VCALENDAR")
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+// "Home vs Away", WITH TBD FOR A SLOT THE PREVIOUS ROUND HAS NOT FILLED YET
+func Summary(g models.PlayoffsModel) string {
+	return teamName(g.HomeTeamId, g.HomeTeamName) + " vs " + teamName(g.AwayTeamId, g.AwayTeamName)
+}
+
+func teamName(id *uuid.UUID, name *string) string {
+	if id == nil || name == nil || *name == "" {
+		return tbd
+	}
+	return *name
+}
+
+// THE SEASON, ROUND, SERIES AND GAME, AND THE WINNER ONCE THE GAME IS PLAYED
+func description(g models.PlayoffsModel) string {
+	parts := []string{bracket.Title(g.Season, g.Competition)}
+	final := g.GameCount != nil && *g.GameCount == "FINAL"
+	switch {
+	case final:
+		parts = append(parts, "Final")
+	case g.FixtureRound != nil && g.GameCount != nil:
+		parts = append(parts, "Round "+strconv.Itoa(*g.FixtureRound), "Series #"+*g.GameCount)
+	}
+	if g.GameRound != "" {
+		parts = append(parts, "Game "+g.GameRound)
+	}
+	text := strings.Join(parts, " · ")
+	if g.Winner != nil {
+		switch {
+		case g.HomeTeamId != nil && *g.Winner == *g.HomeTeamId:
+			text += "\nWinner: " + teamName(g.HomeTeamId, g.HomeTeamName)
+		case g.AwayTeamId != nil && *g.Winner == *g.AwayTeamId:
+			text += "\nWinner: " + teamName(g.AwayTeamId, g.AwayTeamName)
+		}
+	}
+	return text
+}
+
+// IDENTIFIES THE SERIES OF A GAME ACROSS THE SEASONS AND COMPETITIONS OF A FEED
+type seriesKey struct {
+	season      string
+	competition string
+	round       int
+	count       string
+}
+
+func seriesKeyOf(g models.PlayoffsModel) seriesKey {
+	k := seriesKey{season: g.Season, competition: g.Competition}
+	if g.FixtureRound != nil {
+		k.round = *g.FixtureRound
+	}
+	if g.GameCount != nil {
+		k.count = *g.GameCount
+	}
+	return k
+}
+
+// THE SERIES OF THE FEED A TEAM ALREADY WON, BY WINNING MORE THAN HALF OF THEIR GAMES. EVERY GAME OF A SERIES IS
+// PLAYED BY THE SAME TWO TEAMS, SO A TEAM FEED ALSO HOLDS ALL THE GAMES OF ITS SERIES
+func decidedSeries(games []models.PlayoffsModel) map[seriesKey]bool {
+	counts := map[seriesKey]int{}
+	wins := map[seriesKey]map[uuid.UUID]int{}
+	for _, g := range games {
+		k := seriesKeyOf(g)
+		counts[k]++
+		if g.Winner == nil {
+			continue
+		}
+		if wins[k] == nil {
+			wins[k] = map[uuid.UUID]int{}
+		}
+		wins[k][*g.Winner]++
+	}
+	decided := map[seriesKey]bool{}
+	for k, teams := range wins {
+		for _, w := range teams {
+			if w >= counts[k]/2+1 {
+				decided[k] = true
+			}
+		}
+	}
+	return decided
+}
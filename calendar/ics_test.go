@@ -0,0 +1,74 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/internal/bracketfixture"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2024, 4, 20, 19, 0, 0, 0, time.UTC)
+
+// A FIRST ROUND GAME OF THE MAIN COMPETITION AT ITS FOURTH VERSION, SCHEDULED AT at
+func game(count string, gameRound string, home *bracketfixture.Team, away *bracketfixture.Team, winner *bracketfixture.Team, at *time.Time) models.PlayoffsModel {
+	g := bracketfixture.Game(1, count, gameRound, home, away, winner)
+	g.Competition, g.Version, g.ScheduledAt = "main", 4, at
+	return g
+}
+
+// UNFOLDS THE CONTENT LINES OF A FEED
+func lines(t *testing.T, feed string) []string {
+	for _, l := range strings.Split(feed, "\r\n") {
+		assert.LessOrEqual(t, len(l), 75, l)
+	}
+	return strings.Split(strings.ReplaceAll(strings.TrimSuffix(feed, "\r\n"), "\r\n ", ""), "\r\n")
+}
+
+func TestWriteICS_Events(t *testing.T) {
+	lions, tigers := bracketfixture.NewTeam("Lions", ""), bracketfixture.NewTeam("Tigers", "")
+	second, third := start.Add(48*time.Hour), start.Add(96*time.Hour)
+	games := []models.PlayoffsModel{
+		game("1", "1", &lions, &tigers, &lions, &start),
+		game("1", "2", &lions, &tigers, &lions, &second),
+		game("1", "3", &lions, &tigers, nil, &third),
+		game("FINAL", "1", nil, &tigers, nil, nil),
+		game("FINAL", "1", &lions, nil, nil, &third),
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteICS(&out, "Playoffs 2023-2024", games, start))
+	l := lines(t, out.String())
+
+	assert.Equal(t, "BEGIN:VCALENDAR", l[0])
+	assert.Equal(t, "END:VCALENDAR", l[len(l)-1])
+	assert.Equal(t, 4, strings.Count(out.String(), "BEGIN:VEVENT"), "the unscheduled game is left out")
+	assert.Contains(t, l, "UID:"+games[0].PlayoffsId.String()+"@playoffs")
+	assert.Contains(t, l, "SEQUENCE:4")
+	assert.Contains(t, l, "DTSTART:20240420T190000Z")
+	assert.Contains(t, l, "DTEND:20240420T210000Z")
+	assert.Contains(t, l, "SUMMARY:Lions vs Tigers")
+	assert.Contains(t, l, "SUMMARY:Lions vs TBD")
+	assert.Contains(t, l, `DESCRIPTION:Playoffs 2023-2024 · Round 1 · Series #1 · Game 1\nWinner: Lions`)
+	assert.Contains(t, l, `DESCRIPTION:Playoffs 2023-2024 · Final · Game 1`)
+	assert.Equal(t, 1, strings.Count(out.String(), "STATUS:CANCELLED"), "game 3 is not needed after a 2-0 series")
+}
+
+func TestWriteICS_FoldsAndEscapes(t *testing.T) {
+	long := strings.Repeat("Ünïcode, Team; ", 8)
+	home, away := bracketfixture.NewTeam(long, ""), bracketfixture.NewTeam("Bears", "")
+	g := game("1", "1", &home, &away, nil, &start)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteICS(&out, "Lions, Tigers", []models.PlayoffsModel{g}, start))
+	l := lines(t, out.String())
+
+	assert.Contains(t, l, `X-WR-CALNAME:Lions\, Tigers`)
+	assert.Contains(t, l, "SUMMARY:"+strings.ReplaceAll(strings.ReplaceAll(long, ",", `\,`), ";", `\;`)+" vs Bears")
+	assert.NotContains(t, out.String(), "�")
+}
@@ -0,0 +1,102 @@
+// PACKAGE challonge CONVERTS BRACKETS FROM AND TO THE TOURNAMENT JSON OF THE CHALLONGE API (v1), WHICH MOST
+// BRACKET SITES IMPORT AND EXPORT: A TOURNAMENT WITH ITS PARTICIPANTS AND ITS MATCHES BY ROUND, EACH MATCH WITH
+// THE SCORES OF ITS GAMES. A SERIES OF OURS IS A MATCH AND EVERY GAME OF THE SERIES ONE OF ITS SETS
+package challonge
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// THE ONLY FORMAT OF OUR BRACKETS
+const SingleElimination = "single elimination"
+
+// STATES OF A MATCH
+const (
+	MatchPending  = "pending"
+	MatchOpen     = "open"
+	MatchComplete = "complete"
+)
+
+type Tournament struct {
+	Id             int64                `json:"id,omitempty"`
+	Name           string               `json:"name"`
+	URL            string               `json:"url,omitempty"`
+	Description    string               `json:"description,omitempty"`
+	TournamentType string               `json:"tournament_type"`
+	State          string               `json:"state,omitempty"`
+	Participants   []ParticipantWrapper `json:"participants,omitempty"`
+	Matches        []MatchWrapper       `json:"matches,omitempty"`
+}
+
+type Participant struct {
+	Id   int64  `json:"id,omitempty"`
+	Name string `json:"name"`
+	Seed int    `json:"seed"`
+	// FREE TEXT OF THE PARTICIPANT. OUR EXPORTS KEEP THE ID OF THE TEAM IN IT
+	Misc      string `json:"misc,omitempty"`
+	FinalRank *int   `json:"final_rank,omitempty"`
+}
+
+type Match struct {
+	Id                   int64  `json:"id"`
+	State                string `json:"state"`
+	Round                int    `json:"round"`
+	Identifier           string `json:"identifier,omitempty"`
+	SuggestedPlayOrder   int    `json:"suggested_play_order,omitempty"`
+	Player1Id            *int64 `json:"player1_id"`
+	Player2Id            *int64 `json:"player2_id"`
+	Player1PrereqMatchId *int64 `json:"player1_prereq_match_id"`
+	Player2PrereqMatchId *int64 `json:"player2_prereq_match_id"`
+	WinnerId             *int64 `json:"winner_id"`
+	LoserId              *int64 `json:"loser_id"`
+	// ONE player1-player2 SCORE PER SET, E.G. "1-0,0-1,1-0"
+	ScoresCSV     string     `json:"scores_csv"`
+	ScheduledTime *time.Time `json:"scheduled_time,omitempty"`
+}
+
+// THE API WRAPS EVERY OBJECT IN AN OBJECT NAMED AFTER ITS TYPE, AS IN {"tournament": {...}}
+type TournamentWrapper struct {
+	Tournament Tournament `json:"tournament"`
+}
+
+type ParticipantWrapper struct {
+	Participant Participant `json:"participant"`
+}
+
+type MatchWrapper struct {
+	Match Match `json:"match"`
+}
+
+// READS A TOURNAMENT AS RETURNED BY GET /tournaments/{tournament}.json?include_participants=1&include_matches=1
+func ReadTournament(r io.Reader) (Tournament, error) {
+	var doc TournamentWrapper
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return Tournament{}, err
+	}
+	return doc.Tournament, nil
+}
+
+// WRITES A TOURNAMENT IN THE SHAPE ReadTournament READS
+func WriteTournament(w io.Writer, t Tournament) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(TournamentWrapper{Tournament: t})
+}
+
+func (t Tournament) participants() []Participant {
+	participants := make([]Participant, len(t.Participants))
+	for i, p := range t.Participants {
+		participants[i] = p.Participant
+	}
+	return participants
+}
+
+func (t Tournament) matches() []Match {
+	matches := make([]Match, len(t.Matches))
+	for i, m := range t.Matches {
+		matches[i] = m.Match
+	}
+	return matches
+}
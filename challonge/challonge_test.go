@@ -0,0 +1,263 @@
+package challonge
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/internal/bracketfixture"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandardOrder(t *testing.T) {
+	assert.Equal(t, []int{1, 2}, standardOrder(2))
+	assert.Equal(t, []int{1, 8, 4, 5, 2, 7, 3, 6}, standardOrder(8))
+	assert.Equal(t, []int{1, 16, 8, 9, 4, 13, 5, 12, 2, 15, 7, 10, 3, 14, 6, 11}, standardOrder(16))
+	assert.Equal(t, "A", identifier(0))
+	assert.Equal(t, "Z", identifier(25))
+	assert.Equal(t, "AA", identifier(26))
+}
+
+func TestFromPlayoffs_Fixture(t *testing.T) {
+	tournament, err := FromPlayoffs("Playoffs 2023-2024", bracketfixture.SampleBracket(nil))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteTournament(&out, tournament))
+	expected, err := os.ReadFile("testdata/export.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), out.String())
+}
+
+func TestStandings_FromFixture(t *testing.T) {
+	f, err := os.Open("testdata/tournament.json")
+	require.NoError(t, err)
+	defer f.Close()
+	tournament, err := ReadTournament(f)
+	require.NoError(t, err)
+
+	standings, err := Standings(tournament, "2024", "Spring")
+
+	require.NoError(t, err)
+	require.Len(t, standings, 4)
+	// CreatePlayoffs PLAYS RANK 1 AGAINST RANK 4 AND RANK 2 AGAINST RANK 3, AS THE MATCHES A AND B OF THE FILE
+	var names []string
+	for i, s := range standings {
+		names = append(names, s.TeamName)
+		assert.Equal(t, i+1, s.Position)
+		assert.Equal(t, 4-i, s.Pts)
+		assert.Equal(t, "Spring", s.Conference)
+		assert.Equal(t, "2024", s.Season)
+		require.NotNil(t, s.TeamId)
+	}
+	assert.Equal(t, []string{"Lions", "Tigers", "Wolves", "Bears"}, names)
+	assert.Equal(t, bracketfixture.Lions.Id, *standings[0].TeamId, "the team id in misc is kept")
+}
+
+func TestStandings_RoundTrip(t *testing.T) {
+	// SEEDS ONLY: THE PAIRINGS COME FROM THE STANDARD ORDER OF THE SEEDS
+	tournament, err := FromPlayoffs("Playoffs", bracketfixture.SampleBracket(nil))
+	require.NoError(t, err)
+	tournament.Matches = nil
+
+	standings, err := Standings(tournament, "2024-2025", "East")
+
+	require.NoError(t, err)
+	ids := []uuid.UUID{}
+	for _, s := range standings {
+		ids = append(ids, *s.TeamId)
+	}
+	// RANKS 1 AND 4 MEET AS LIONS AND BEARS DID, RANKS 2 AND 3 AS TIGERS AND WOLVES
+	assert.Equal(t, []uuid.UUID{bracketfixture.Lions.Id, bracketfixture.Tigers.Id, bracketfixture.Wolves.Id, bracketfixture.Bears.Id}, ids)
+}
+
+func TestStandings_Unsupported(t *testing.T) {
+	three := Tournament{TournamentType: SingleElimination}
+	for i, name := range []string{"Lions", "Tigers", "Wolves"} {
+		three.Participants = append(three.Participants, ParticipantWrapper{Participant{Id: int64(i + 1), Name: name, Seed: i + 1}})
+	}
+	_, err := Standings(three, "2024", "East")
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	_, err = Standings(Tournament{TournamentType: "double elimination"}, "2024", "East")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+// AN IN MEMORY SITE ANSWERING THE CALLS OF THE CHALLONGE API THE CLIENT MAKES. STARTING THE TOURNAMENT PAIRS THE
+// SEEDS IN THE STANDARD ORDER AND A REPORTED WINNER MOVES TO THE NEXT MATCH
+type stubSite struct {
+	mu         sync.Mutex
+	tournament *Tournament
+	nextId     int64
+	calls      []string
+}
+
+func (s *stubSite) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+	mux.HandleFunc("GET /v1/tournaments/{url}", func(w http.ResponseWriter, r *http.Request) {
+		if s.tournament == nil || s.tournament.URL != strings.TrimSuffix(r.PathValue("url"), ".json") {
+			writeJSON(w, http.StatusNotFound, map[string][]string{"errors": {"Requested tournament not found"}})
+			return
+		}
+		writeJSON(w, http.StatusOK, TournamentWrapper{*s.tournament})
+	})
+	mux.HandleFunc("POST /v1/tournaments.json", func(w http.ResponseWriter, r *http.Request) {
+		var body TournamentWrapper
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		created := body.Tournament
+		created.Id, created.State = 42, MatchPending
+		s.tournament = &created
+		writeJSON(w, http.StatusOK, TournamentWrapper{created})
+	})
+	mux.HandleFunc("POST /v1/tournaments/{url}/participants/bulk_add.json", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Participants []Participant `json:"participants"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		for _, p := range body.Participants {
+			s.nextId++
+			p.Id = 1000 + s.nextId
+			s.tournament.Participants = append(s.tournament.Participants, ParticipantWrapper{p})
+		}
+		writeJSON(w, http.StatusOK, s.tournament.Participants)
+	})
+	mux.HandleFunc("POST /v1/tournaments/{url}/start.json", func(w http.ResponseWriter, r *http.Request) {
+		bySeed := map[int]int64{}
+		for _, p := range s.tournament.participants() {
+			bySeed[p.Seed] = p.Id
+		}
+		order := standardOrder(len(bySeed))
+		var previous []int64
+		for round := 1; len(previous) != 1; round++ {
+			count := len(previous) / 2
+			if round == 1 {
+				count = len(order) / 2
+			}
+			var current []int64
+			for m := 0; m < count; m++ {
+				s.nextId++
+				match := Match{Id: 2000 + s.nextId, Round: round, State: MatchPending}
+				if round == 1 {
+					p1, p2 := bySeed[order[2*m]], bySeed[order[2*m+1]]
+					match.Player1Id, match.Player2Id, match.State = &p1, &p2, MatchOpen
+				} else {
+					match.Player1PrereqMatchId, match.Player2PrereqMatchId = &previous[2*m], &previous[2*m+1]
+				}
+				s.tournament.Matches = append(s.tournament.Matches, MatchWrapper{match})
+				current = append(current, match.Id)
+			}
+			previous = current
+		}
+		s.tournament.State = "underway"
+		writeJSON(w, http.StatusOK, TournamentWrapper{*s.tournament})
+	})
+	mux.HandleFunc("PUT /v1/tournaments/{url}/matches/{match}", func(w http.ResponseWriter, r *http.Request) {
+		var body matchReport
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		id, err := strconv.ParseInt(strings.TrimSuffix(r.PathValue("match"), ".json"), 10, 64)
+		require.NoError(t, err)
+		for i := range s.tournament.Matches {
+			m := &s.tournament.Matches[i].Match
+			if m.Id == id {
+				winner := body.Match.WinnerId
+				m.State, m.ScoresCSV, m.WinnerId = MatchComplete, body.Match.ScoresCSV, &winner
+			}
+			for side, prereq := range []*int64{m.Player1PrereqMatchId, m.Player2PrereqMatchId} {
+				if prereq != nil && *prereq == id {
+					winner := body.Match.WinnerId
+					if side == 0 {
+						m.Player1Id = &winner
+					} else {
+						m.Player2Id = &winner
+					}
+					if m.Player1Id != nil && m.Player2Id != nil {
+						m.State = MatchOpen
+					}
+				}
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r.URL.Query().Get("api_key") != "secret" {
+			writeJSON(w, http.StatusUnauthorized, map[string][]string{"errors": {"Invalid API key"}})
+			return
+		}
+		s.calls = append(s.calls, r.Method+" "+r.URL.Path)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *stubSite) match(round int) Match {
+	for _, m := range s.tournament.matches() {
+		if m.Round == round {
+			return m
+		}
+	}
+	return Match{}
+}
+
+func TestPublish_StubServer(t *testing.T) {
+	site := &stubSite{}
+	server := httptest.NewServer(site.handler(t))
+	defer server.Close()
+	client := NewClient(server.URL+"/v1", "secret")
+
+	tournament, err := FromPlayoffs("Playoffs 2023-2024", bracketfixture.SampleBracket(nil))
+	require.NoError(t, err)
+	result, err := client.Publish("playoffs_2023_2024", tournament)
+
+	require.NoError(t, err)
+	assert.Equal(t, PublishResult{URL: "playoffs_2023_2024", Created: true, Started: true, Reported: 2}, result)
+	final := site.match(2)
+	assert.Equal(t, MatchOpen, final.State, "both semifinal winners reached the final")
+	assert.Equal(t, "1-0,0-1,1-0", site.match(1).ScoresCSV)
+
+	// THE FINAL IS PLAYED: PUBLISHING AGAIN ONLY REPORTS IT
+	tournament, err = FromPlayoffs("Playoffs 2023-2024", bracketfixture.SampleBracket(&bracketfixture.Wolves))
+	require.NoError(t, err)
+	site.calls = nil
+	result, err = client.Publish("playoffs_2023_2024", tournament)
+
+	require.NoError(t, err)
+	assert.Equal(t, PublishResult{URL: "playoffs_2023_2024", Reported: 1}, result)
+	final = site.match(2)
+	assert.Equal(t, MatchComplete, final.State)
+	assert.Equal(t, *final.Player2Id, *final.WinnerId)
+	assert.Equal(t, "0-1", final.ScoresCSV)
+	assert.Equal(t, []string{
+		"GET /v1/tournaments/playoffs_2023_2024.json",
+		"GET /v1/tournaments/playoffs_2023_2024.json",
+		"PUT /v1/tournaments/playoffs_2023_2024/matches/" + strconv.FormatInt(final.Id, 10) + ".json",
+		"GET /v1/tournaments/playoffs_2023_2024.json",
+	}, site.calls)
+}
+
+func TestPublish_APIError(t *testing.T) {
+	server := httptest.NewServer((&stubSite{}).handler(t))
+	defer server.Close()
+
+	_, err := NewClient(server.URL+"/v1", "wrong").Tournament("playoffs")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+	assert.Equal(t, "challonge api answered 401: Invalid API key", err.Error())
+	assert.Equal(t, "1-2,1-0", flip("2-1,0-1"))
+}
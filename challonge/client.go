@@ -0,0 +1,268 @@
+package challonge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BASE URL OF THE CHALLONGE API. SITES OFFERING THE SAME API ARE USED BY GIVING THEIR OWN
+const DefaultBaseURL = "https://api.challonge.com/v1"
+
+// RETURNED BY Client.Tournament FOR A TOURNAMENT THE SITE DOES NOT HAVE
+var ErrNoTournament = errors.New("the tournament does not exist")
+
+// CALLS A CHALLONGE COMPATIBLE API WITH AN API KEY
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func NewClient(baseURL string, apiKey string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// AN ERROR ANSWERED BY THE SITE, WITH THE MESSAGES OF ITS {"errors": [...]} BODY
+type APIError struct {
+	Status   int
+	Messages []string
+}
+
+func (e *APIError) Error() string {
+	if len(e.Messages) == 0 {
+		return "challonge api answered " + strconv.Itoa(e.Status)
+	}
+	return "challonge api answered " + strconv.Itoa(e.Status) + ": " + strings.Join(e.Messages, "; ")
+}
+
+func (c *Client) do(method string, path string, query url.Values, body any, out any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api_key", c.apiKey)
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path+"?"+query.Encode(), reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		apiErr := &APIError{Status: res.StatusCode}
+		var errorsBody struct {
+			Errors []string `json:"errors"`
+		}
+		if json.NewDecoder(io.LimitReader(res.Body, 1<<16)).Decode(&errorsBody) == nil {
+			apiErr.Messages = errorsBody.Errors
+		}
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// THE TOURNAMENT WITH ITS PARTICIPANTS AND MATCHES. tournament IS ITS ID OR URL
+func (c *Client) Tournament(tournament string) (Tournament, error) {
+	var doc TournamentWrapper
+	query := url.Values{"include_participants": {"1"}, "include_matches": {"1"}}
+	err := c.do(http.MethodGet, "/tournaments/"+url.PathEscape(tournament)+".json", query, nil, &doc)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+		return Tournament{}, ErrNoTournament
+	}
+	return doc.Tournament, err
+}
+
+// CREATES AN EMPTY TOURNAMENT FROM THE NAME, URL, DESCRIPTION AND TYPE OF t
+func (c *Client) CreateTournament(t Tournament) (Tournament, error) {
+	body := TournamentWrapper{Tournament: Tournament{Name: t.Name, URL: t.URL, Description: t.Description, TournamentType: t.TournamentType}}
+	var doc TournamentWrapper
+	err := c.do(http.MethodPost, "/tournaments.json", nil, body, &doc)
+	return doc.Tournament, err
+}
+
+// ADDS THE PARTICIPANTS WITH THEIR NAME, SEED AND misc
+func (c *Client) AddParticipants(tournament string, participants []Participant) error {
+	type participant struct {
+		Name string `json:"name"`
+		Seed int    `json:"seed"`
+		Misc string `json:"misc,omitempty"`
+	}
+	body := struct {
+		Participants []participant `json:"participants"`
+	}{}
+	for _, p := range participants {
+		body.Participants = append(body.Participants, participant{Name: p.Name, Seed: p.Seed, Misc: p.Misc})
+	}
+	return c.do(http.MethodPost, "/tournaments/"+url.PathEscape(tournament)+"/participants/bulk_add.json", nil, body, nil)
+}
+
+// STARTS THE TOURNAMENT, WHICH CREATES ITS MATCHES FROM THE SEEDS
+func (c *Client) Start(tournament string) error {
+	return c.do(http.MethodPost, "/tournaments/"+url.PathEscape(tournament)+"/start.json", nil, nil, nil)
+}
+
+// REPORTS THE RESULT OF A MATCH, WHICH SENDS ITS WINNER TO THE NEXT MATCH
+func (c *Client) ReportMatch(tournament string, matchId int64, scoresCSV string, winnerId int64) error {
+	body := matchReport{}
+	body.Match.ScoresCSV = scoresCSV
+	body.Match.WinnerId = winnerId
+	return c.do(http.MethodPut, "/tournaments/"+url.PathEscape(tournament)+"/matches/"+strconv.FormatInt(matchId, 10)+".json", nil, body, nil)
+}
+
+// THE BODY OF A MATCH UPDATE
+type matchReport struct {
+	Match struct {
+		ScoresCSV string `json:"scores_csv"`
+		WinnerId  int64  `json:"winner_id"`
+	} `json:"match"`
+}
+
+// WHAT Publish CHANGED ON THE SITE
+type PublishResult struct {
+	URL      string `json:"url"`
+	Created  bool   `json:"created"`
+	Started  bool   `json:"started"`
+	Reported int    `json:"reported"`
+}
+
+// MIRRORS A TOURNAMENT CONVERTED BY FromPlayoffs ON THE SITE UNDER tournamentURL. THE TOURNAMENT IS CREATED,
+// SEEDED AND STARTED THE FIRST TIME; EVERY RUN THEN REPORTS THE DECIDED SERIES WHOSE MATCHES ARE OPEN ON THE SITE,
+// ROUND AFTER ROUND, SO IT CAN BE RUN AGAIN AFTER EVERY RESULT. PARTICIPANTS ARE MATCHED BY THE TEAM ID OF THEIR
+// misc, AND MATCHES ALREADY COMPLETE ON THE SITE ARE LEFT AS THEY ARE
+func (c *Client) Publish(tournamentURL string, t Tournament) (PublishResult, error) {
+	result := PublishResult{URL: tournamentURL}
+	remote, err := c.Tournament(tournamentURL)
+	if errors.Is(err, ErrNoTournament) {
+		create := t
+		create.URL = tournamentURL
+		if _, errC := c.CreateTournament(create); errC != nil {
+			return result, errC
+		}
+		result.Created = true
+		remote, err = c.Tournament(tournamentURL)
+	}
+	if err != nil {
+		return result, err
+	}
+	if len(remote.Participants) == 0 {
+		if errA := c.AddParticipants(tournamentURL, t.participants()); errA != nil {
+			return result, errA
+		}
+	}
+	if remote.State == "" || remote.State == MatchPending {
+		if errS := c.Start(tournamentURL); errS != nil {
+			return result, errS
+		}
+		result.Started = true
+	}
+
+	ours := resultsByTeams(t)
+	// A SITE THAT KEEPS A REPORTED MATCH OPEN WOULD OTHERWISE BE ASKED FOREVER
+	done := map[int64]bool{}
+	for {
+		remote, err = c.Tournament(tournamentURL)
+		if err != nil {
+			return result, err
+		}
+		teams := map[int64]string{}
+		for _, p := range remote.participants() {
+			teams[p.Id] = p.Misc
+		}
+		reported := 0
+		for _, m := range remote.matches() {
+			if m.State != MatchOpen || m.Player1Id == nil || m.Player2Id == nil || done[m.Id] {
+				continue
+			}
+			team1, team2 := teams[*m.Player1Id], teams[*m.Player2Id]
+			r, ok := ours[pairOf(team1, team2)]
+			if !ok {
+				continue
+			}
+			winner, scoresCSV := *m.Player1Id, r.scores
+			if r.winner != team1 {
+				winner = *m.Player2Id
+			}
+			if r.team1 != team1 {
+				scoresCSV = flip(scoresCSV)
+			}
+			if errR := c.ReportMatch(tournamentURL, m.Id, scoresCSV, winner); errR != nil {
+				return result, fmt.Errorf("reporting match %d: %w", m.Id, errR)
+			}
+			done[m.Id] = true
+			reported++
+		}
+		result.Reported += reported
+		if reported == 0 {
+			return result, nil
+		}
+	}
+}
+
+// A DECIDED MATCH OF OURS, BY THE TEAM IDS OF ITS PLAYERS
+type matchResult struct {
+	team1  string
+	winner string
+	scores string
+}
+
+func pairOf(team1 string, team2 string) string {
+	if team1 > team2 {
+		team1, team2 = team2, team1
+	}
+	return team1 + " " + team2
+}
+
+func resultsByTeams(t Tournament) map[string]matchResult {
+	teams := map[int64]string{}
+	for _, p := range t.participants() {
+		teams[p.Id] = p.Misc
+	}
+	results := map[string]matchResult{}
+	for _, m := range t.matches() {
+		if m.State != MatchComplete || m.WinnerId == nil {
+			continue
+		}
+		team1, team2 := teams[*m.Player1Id], teams[*m.Player2Id]
+		results[pairOf(team1, team2)] = matchResult{team1: team1, winner: teams[*m.WinnerId], scores: m.ScoresCSV}
+	}
+	return results
+}
+
+// TURNS "2-1,0-1" INTO "1-2,1-0"
+func flip(scoresCSV string) string {
+	sets := strings.Split(scoresCSV, ",")
+	for i, set := range sets {
+		if a, b, ok := strings.Cut(set, "-"); ok {
+			sets[i] = b + "-" + a
+		}
+	}
+	return strings.Join(sets, ",")
+}
@@ -0,0 +1,306 @@
+package challonge
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// RETURNED FOR A TOURNAMENT OR A BRACKET THAT HAS NO EQUIVALENT ON THE OTHER SIDE, SUCH AS A DOUBLE ELIMINATION
+// TOURNAMENT OR A FIELD THAT IS NOT A POWER OF TWO
+var ErrUnsupported = errors.New("unsupported bracket")
+
+func unsupported(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrUnsupported, fmt.Sprintf(format, args...))
+}
+
+// THE SEEDS OF n PARTICIPANTS IN THE ORDER OF THE SLOTS OF THE FIRST ROUND, AS BRACKET SITES PLACE THEM: THE
+// MATCH m OPPOSES THE SEEDS order[2m] AND order[2m+1], AND THE WINNERS OF THE MATCHES 2m AND 2m+1 MEET NEXT.
+// FOR 8 PARTICIPANTS: 1 8 4 5 2 7 3 6
+func standardOrder(n int) []int {
+	order := []int{1}
+	for size := 2; size <= n; size *= 2 {
+		next := make([]int, 0, size)
+		for _, seed := range order {
+			next = append(next, seed, size+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+func powerOfTwo(n int) bool {
+	return n >= 2 && n&(n-1) == 0
+}
+
+// "A", "B", ..., "Z", "AA", "AB", ... AS BRACKET SITES NAME THEIR MATCHES
+func identifier(i int) string {
+	id := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		id = string(rune('A'+(i-1)%26)) + id
+	}
+	return id
+}
+
+// CONVERTS THE BRACKET RETURNED BY ListPlayoffs TO A TOURNAMENT. THE SEEDS OF THE PARTICIPANTS ARE CHOSEN SO THE
+// STANDARD BRACKET OF A SITE SEEDED WITH THEM PAIRS THE TEAMS AS OURS DOES, ROUND AFTER ROUND, AND THE misc OF
+// EVERY PARTICIPANT IS THE ID OF ITS TEAM. IDS ARE NUMBERED FROM 1 IN THE ORDER OF THE BRACKET
+func FromPlayoffs(name string, playoffs [][][]models.PlayoffsModel) (Tournament, error) {
+	t := Tournament{Name: name, TournamentType: SingleElimination, State: "underway"}
+	if len(playoffs) == 0 || len(playoffs[0]) == 0 {
+		return Tournament{}, unsupported("the bracket is empty")
+	}
+	first := playoffs[0]
+	if !powerOfTwo(2 * len(first)) {
+		return Tournament{}, unsupported("%d series in the first round, expected a power of two", len(first))
+	}
+	order := standardOrder(2 * len(first))
+	participants := map[uuid.UUID]int64{}
+	for m, games := range first {
+		s := bracket.Summarize(games)
+		for side, team := range []bracket.Team{s.Home, s.Away} {
+			if team.Id == nil {
+				return Tournament{}, unsupported("series %s of the first round has no team yet", s.Label)
+			}
+			seed := order[2*m+side]
+			participants[*team.Id] = int64(seed)
+			t.Participants = append(t.Participants, ParticipantWrapper{Participant{
+				Id:   int64(seed),
+				Name: team.Name,
+				Seed: seed,
+				Misc: team.Id.String(),
+			}})
+		}
+	}
+	sort.Slice(t.Participants, func(i, j int) bool {
+		return t.Participants[i].Participant.Seed < t.Participants[j].Participant.Seed
+	})
+
+	player := func(team bracket.Team) *int64 {
+		if team.Id == nil {
+			return nil
+		}
+		if id, ok := participants[*team.Id]; ok {
+			return &id
+		}
+		return nil
+	}
+	var previous []int64
+	var id int64
+	for r, round := range playoffs {
+		if r > 0 && 2*len(round) != len(previous) {
+			return Tournament{}, unsupported("round %d has %d series for the %d of the previous round", r+1, len(round), len(previous))
+		}
+		current := make([]int64, len(round))
+		for m, games := range round {
+			id++
+			current[m] = id
+			s := bracket.Summarize(games)
+			match := Match{
+				Id:                 id,
+				Round:              r + 1,
+				Identifier:         identifier(int(id - 1)),
+				SuggestedPlayOrder: int(id),
+				Player1Id:          player(s.Home),
+				Player2Id:          player(s.Away),
+				ScoresCSV:          scores(s),
+				ScheduledTime:      firstScheduled(games),
+			}
+			if r > 0 {
+				match.Player1PrereqMatchId = &previous[2*m]
+				match.Player2PrereqMatchId = &previous[2*m+1]
+			}
+			switch {
+			case s.Decided && match.Player1Id != nil && match.Player2Id != nil:
+				match.State = MatchComplete
+				match.WinnerId, match.LoserId = match.Player1Id, match.Player2Id
+				if s.Away.Winner {
+					match.WinnerId, match.LoserId = match.Player2Id, match.Player1Id
+				}
+			case match.Player1Id != nil && match.Player2Id != nil:
+				match.State = MatchOpen
+			default:
+				match.State = MatchPending
+			}
+			if s.Final && match.State == MatchComplete {
+				t.State = MatchComplete
+				t.rank(*match.WinnerId, 1)
+				t.rank(*match.LoserId, 2)
+			}
+			t.Matches = append(t.Matches, MatchWrapper{match})
+		}
+		previous = current
+	}
+	return t, nil
+}
+
+func (t *Tournament) rank(participantId int64, rank int) {
+	for i := range t.Participants {
+		if t.Participants[i].Participant.Id == participantId {
+			r := rank
+			t.Participants[i].Participant.FinalRank = &r
+		}
+	}
+}
+
+// THE SCORE OF EVERY PLAYED GAME OF THE SERIES, FROM THE POINT OF VIEW OF THE HOME TEAM
+func scores(s bracket.Series) string {
+	var sets []string
+	for _, g := range s.Games {
+		switch {
+		case g.Winner == nil:
+		case s.Home.Id != nil && *g.Winner == *s.Home.Id:
+			sets = append(sets, "1-0")
+		case s.Away.Id != nil && *g.Winner == *s.Away.Id:
+			sets = append(sets, "0-1")
+		}
+	}
+	return strings.Join(sets, ",")
+}
+
+func firstScheduled(games []models.PlayoffsModel) *time.Time {
+	var first *time.Time
+	for _, g := range games {
+		if g.ScheduledAt != nil && (first == nil || g.ScheduledAt.Before(*first)) {
+			at := g.ScheduledAt.UTC()
+			first = &at
+		}
+	}
+	return first
+}
+
+// THE STANDINGS THAT SEED A BRACKET LIKE THE ONE OF THE TOURNAMENT WHEN CreatePlayoffs IS RUN ON THEIR SINGLE
+// conference WITH A LIMIT OF ONE TEAM PER PARTICIPANT. THE FIRST ROUND IS TAKEN FROM THE MATCHES OF THE FILE,
+// OR FROM THE SEEDS OF THE PARTICIPANTS WHEN IT HAS NONE, AND position AND pts ARE SET SO CreatePlayoffs PAIRS
+// THE TEAMS THE SAME WAY. A PARTICIPANT KEEPS THE TEAM ID FOUND IN ITS misc; THE OTHERS GET A NEW ONE
+func Standings(t Tournament, season string, conference string) ([]models.StandingsModel, error) {
+	if t.TournamentType != "" && t.TournamentType != SingleElimination {
+		return nil, unsupported("%s tournaments can not be seeded, only %s", t.TournamentType, SingleElimination)
+	}
+	participants := t.participants()
+	if !powerOfTwo(len(participants)) {
+		return nil, unsupported("%d participants, expected a power of two", len(participants))
+	}
+	slots, err := firstRound(t, participants)
+	if err != nil {
+		return nil, err
+	}
+	n := len(participants)
+	standings := make([]models.StandingsModel, 0, n)
+	for m := 0; m < n/2; m++ {
+		// CreatePlayoffs PLAYS THE RANK m+1 AGAINST THE RANK n-m IN ITS SERIES m
+		for side, rank := range []int{m + 1, n - m} {
+			p := slots[2*m+side]
+			teamId, errP := uuid.Parse(p.Misc)
+			if errP != nil {
+				teamId = uuid.New()
+			}
+			standings = append(standings, models.StandingsModel{
+				TeamId:     &teamId,
+				Position:   rank,
+				TeamName:   p.Name,
+				Pts:        n + 1 - rank,
+				Conference: conference,
+				Season:     season,
+			})
+		}
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		return standings[i].Position < standings[j].Position
+	})
+	return standings, nil
+}
+
+// THE PARTICIPANTS IN THE ORDER OF THE SLOTS OF THE FIRST ROUND
+func firstRound(t Tournament, participants []Participant) ([]Participant, error) {
+	byId := map[int64]Participant{}
+	bySeed := map[int]Participant{}
+	for _, p := range participants {
+		if p.Name == "" {
+			return nil, unsupported("participant %d has no name", p.Id)
+		}
+		byId[p.Id] = p
+		bySeed[p.Seed] = p
+	}
+	matches := t.matches()
+	if len(matches) == 0 {
+		if len(bySeed) != len(participants) {
+			return nil, unsupported("the participants need the seeds 1 to %d", len(participants))
+		}
+		slots := make([]Participant, 0, len(participants))
+		for _, seed := range standardOrder(len(participants)) {
+			p, ok := bySeed[seed]
+			if !ok {
+				return nil, unsupported("the participants need the seeds 1 to %d", len(participants))
+			}
+			slots = append(slots, p)
+		}
+		return slots, nil
+	}
+
+	byMatch := map[int64]Match{}
+	var final *Match
+	for i, m := range matches {
+		if m.Round < 1 {
+			return nil, unsupported("match %d is in the losers bracket", m.Id)
+		}
+		byMatch[m.Id] = m
+		if final == nil || m.Round > final.Round {
+			final = &matches[i]
+		}
+	}
+	var leaves []Match
+	var walk func(m Match, depth int) error
+	walk = func(m Match, depth int) error {
+		if depth > len(matches) {
+			return unsupported("the matches of the tournament form a cycle")
+		}
+		if m.Player1PrereqMatchId == nil && m.Player2PrereqMatchId == nil {
+			leaves = append(leaves, m)
+			return nil
+		}
+		for _, prereq := range []*int64{m.Player1PrereqMatchId, m.Player2PrereqMatchId} {
+			if prereq == nil {
+				return unsupported("match %d waits for a single match", m.Id)
+			}
+			next, ok := byMatch[*prereq]
+			if !ok {
+				return unsupported("match %d waits for match %d which is not in the file", m.Id, *prereq)
+			}
+			if err := walk(next, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(*final, 0); err != nil {
+		return nil, err
+	}
+	if 2*len(leaves) != len(participants) {
+		return nil, unsupported("%d matches in the first round for %d participants", len(leaves), len(participants))
+	}
+	slots := make([]Participant, 0, len(participants))
+	seen := map[int64]bool{}
+	for _, m := range leaves {
+		for _, playerId := range []*int64{m.Player1Id, m.Player2Id} {
+			if playerId == nil {
+				return nil, unsupported("match %d of the first round has an empty slot", m.Id)
+			}
+			p, ok := byId[*playerId]
+			if !ok || seen[*playerId] {
+				return nil, unsupported("match %d of the first round has the unknown or repeated participant %s", m.Id, strconv.FormatInt(*playerId, 10))
+			}
+			seen[*playerId] = true
+			slots = append(slots, p)
+		}
+	}
+	return slots, nil
+}
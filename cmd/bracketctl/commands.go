@@ -0,0 +1,810 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/backup"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/challonge"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/document"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/site"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/spreadsheet"
+
+	"github.com/google/uuid"
+)
+
+// WHAT export WRITES: THE BRACKET OF A SEASON WITH THE STANDINGS IT WAS SEEDED FROM
+type exportDocument struct {
+	League      string                     `json:"league"`
+	Season      string                     `json:"season"`
+	Competition string                     `json:"competition"`
+	Playoffs    [][][]models.PlayoffsModel `json:"playoffs"`
+	Standings   []models.StandingsModel    `json:"standings"`
+}
+
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	return fs
+}
+
+// PARSES THE FLAGS OF A COMMAND AND CHECKS THE REQUIRED ONES WERE GIVEN
+func (c *cli) parse(fs *flag.FlagSet, args []string, required ...string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(c.errOut, "bracketctl:", fs.Name(), "takes no arguments, got", strings.Join(fs.Args(), " "))
+		return errUsage
+	}
+	for _, name := range required {
+		if fs.Lookup(name).Value.String() == "" {
+			fmt.Fprintln(c.errOut, "bracketctl:", fs.Name(), "needs -"+name)
+			return errUsage
+		}
+	}
+	return nil
+}
+
+func createCmd(c *cli, args []string) error {
+	fs := c.flags("create")
+	season := fs.String("season", "", "season of the bracket, e.g. 2023-2024")
+	conferences := fs.String("conferences", "", "comma separated conferences whose standings seed the bracket")
+	limit := fs.Int("limit", 8, "teams qualified from each conference")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	dryRun := fs.Bool("dry-run", false, "print the qualified teams and the bracket without creating it")
+	if err := c.parse(fs, args, "season", "conferences"); err != nil {
+		return err
+	}
+	if *dryRun {
+		preview, err := c.conn.PreviewPlayoffs(strings.Split(*conferences, ","), *season, *competition, *limit)
+		if err != nil {
+			return err
+		}
+		return c.printPreview(preview)
+	}
+	if err := c.conn.CreatePlayoffs(strings.Split(*conferences, ","), *season, *competition, *limit); err != nil {
+		return err
+	}
+	playoffs, err := c.conn.ListPlayoffs(*season, *competition)
+	if err != nil {
+		return err
+	}
+	return c.printBracket(playoffs)
+}
+
+// CREATES THE BRACKET OF A SEASON WITH THE CONFERENCES AND LIMIT OF A PREVIOUS SEASON AND PRINTS HOW THE
+// QUALIFIED TEAMS CHANGED
+func cloneCmd(c *cli, args []string) error {
+	fs := c.flags("clone")
+	from := fs.String("from", "", "season whose format is copied")
+	season := fs.String("season", "", "season of the new bracket")
+	competition := fs.String("competition", "", "competition of both seasons (default "+queries.DefaultCompetition+")")
+	if err := c.parse(fs, args, "from", "season"); err != nil {
+		return err
+	}
+	report, err := c.conn.ClonePlayoffs(*from, *season, *competition)
+	if err != nil {
+		return err
+	}
+	return c.printCloneReport(report)
+}
+
+// CHECKS THE BRACKET OF A SEASON FOR INCONSISTENCIES LEFT BY MANUAL EDITS AND WITH -repair RECOMPUTES THE
+// NEXT ROUND SLOTS FROM THE RECORDED WINNERS. FAILS WHILE VIOLATIONS REMAIN
+func validateCmd(c *cli, args []string) error {
+	fs := c.flags("validate")
+	season := fs.String("season", "", "season of the bracket")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	repair := fs.Bool("repair", false, "fill the slots after the first round with the winners of the series feeding them")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	var report models.BracketIntegrityModel
+	var err error
+	if *repair {
+		report, err = c.conn.RepairPlayoffs(*season, *competition)
+	} else {
+		report, err = c.conn.ValidatePlayoffs(*season, *competition)
+	}
+	if err != nil {
+		return err
+	}
+	return c.printIntegrityReport(report)
+}
+
+// REBUILDS THE ROUNDS AFTER THE FIRST FROM THE FIRST ROUND PAIRINGS AND THE RECORDED WINNERS, E.G. AFTER RESULTS
+// WERE WRITTEN TO THE DATABASE WITHOUT set-winner
+func recomputeCmd(c *cli, args []string) error {
+	fs := c.flags("recompute")
+	season := fs.String("season", "", "season of the bracket")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	report, err := c.conn.RecomputePlayoffs(*season, *competition)
+	if err != nil {
+		return err
+	}
+	return c.printIntegrityReport(report)
+}
+
+func listCmd(c *cli, args []string) error {
+	fs := c.flags("list")
+	season := fs.String("season", "", "season of the bracket")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	playoffs, err := c.conn.ListPlayoffs(*season, *competition)
+	if err != nil {
+		return err
+	}
+	return c.printBracket(playoffs)
+}
+
+// FINDS A GAME OF THE BRACKET BY ID
+func (c *cli) findGame(season string, competition string, gameId string) (models.PlayoffsModel, error) {
+	id, err := uuid.Parse(gameId)
+	if err != nil {
+		return models.PlayoffsModel{}, fmt.Errorf("invalid game id %s: %w", gameId, err)
+	}
+	playoffs, err := c.conn.ListPlayoffs(season, competition)
+	if err != nil {
+		return models.PlayoffsModel{}, err
+	}
+	for _, round := range playoffs {
+		for _, series := range round {
+			for _, game := range series {
+				if game.PlayoffsId == id {
+					return game, nil
+				}
+			}
+		}
+	}
+	return models.PlayoffsModel{}, fmt.Errorf("game %s is not part of the %s bracket of season %s", gameId, competitionName(competition), season)
+}
+
+// TURNS home, away OR A TEAM ID INTO THE ID OF ONE OF THE TEAMS OF THE GAME
+func winnerOf(game models.PlayoffsModel, winner string) (uuid.UUID, error) {
+	switch winner {
+	case "home":
+		if game.HomeTeamId == nil {
+			return uuid.Nil, fmt.Errorf("game %s has no home team yet", game.PlayoffsId)
+		}
+		return *game.HomeTeamId, nil
+	case "away":
+		if game.AwayTeamId == nil {
+			return uuid.Nil, fmt.Errorf("game %s has no away team yet", game.PlayoffsId)
+		}
+		return *game.AwayTeamId, nil
+	}
+	teamId, err := uuid.Parse(winner)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid winner %s, expected home, away or a team id", winner)
+	}
+	if (game.HomeTeamId == nil || *game.HomeTeamId != teamId) && (game.AwayTeamId == nil || *game.AwayTeamId != teamId) {
+		return uuid.Nil, fmt.Errorf("team %s does not play game %s", winner, game.PlayoffsId)
+	}
+	return teamId, nil
+}
+
+func setWinnerCmd(c *cli, args []string) error {
+	fs := c.flags("set-winner")
+	season := fs.String("season", "", "season of the bracket")
+	gameId := fs.String("game", "", "id of the game, as shown by list")
+	winner := fs.String("winner", "", "home, away or the id of the winning team")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	if err := c.parse(fs, args, "season", "game", "winner"); err != nil {
+		return err
+	}
+	game, err := c.findGame(*season, *competition, *gameId)
+	if err != nil {
+		return err
+	}
+	teamId, err := winnerOf(game, *winner)
+	if err != nil {
+		return err
+	}
+	req := queries.PlayoffsReqFromModel(game)
+	req.Winner = teamId
+	if errU := c.conn.UpdatePlayoffs(game.PlayoffsId, req); errU != nil {
+		return errU
+	}
+	updated, err := c.findGame(*season, *competition, *gameId)
+	if err != nil {
+		return err
+	}
+	return c.printGames([]models.PlayoffsModel{updated})
+}
+
+func revertCmd(c *cli, args []string) error {
+	fs := c.flags("revert")
+	season := fs.String("season", "", "season of the bracket")
+	gameId := fs.String("game", "", "id of the game whose winner is cleared")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	if err := c.parse(fs, args, "season", "game"); err != nil {
+		return err
+	}
+	game, err := c.findGame(*season, *competition, *gameId)
+	if err != nil {
+		return err
+	}
+	if game.Winner == nil || game.FixtureRound == nil {
+		return fmt.Errorf("game %s has no winner to revert", *gameId)
+	}
+	if errU := c.conn.UpdatePlayoffsToNull(game.PlayoffsId, *game.FixtureRound, *game.Winner, *season, *competition); errU != nil {
+		return errU
+	}
+	reverted, err := c.findGame(*season, *competition, *gameId)
+	if err != nil {
+		return err
+	}
+	return c.printGames([]models.PlayoffsModel{reverted})
+}
+
+// TURNS THE -at OF schedule INTO A DATE, none CLEARS IT
+func scheduleTime(at string) (*time.Time, error) {
+	if at == "none" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %s, expected RFC 3339 like 2024-04-20T19:00:00Z or none", at)
+	}
+	return &t, nil
+}
+
+func scheduleCmd(c *cli, args []string) error {
+	fs := c.flags("schedule")
+	season := fs.String("season", "", "season of the bracket")
+	gameId := fs.String("game", "", "id of the game, as shown by list")
+	at := fs.String("at", "", "start of the game in RFC 3339, or none to clear it")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	if err := c.parse(fs, args, "season", "game", "at"); err != nil {
+		return err
+	}
+	scheduledAt, err := scheduleTime(*at)
+	if err != nil {
+		return err
+	}
+	game, err := c.findGame(*season, *competition, *gameId)
+	if err != nil {
+		return err
+	}
+	scheduled, err := c.conn.SchedulePlayoffs(game.PlayoffsId, scheduledAt)
+	if err != nil {
+		return err
+	}
+	return c.printGames([]models.PlayoffsModel{scheduled})
+}
+
+func deleteCmd(c *cli, args []string) error {
+	fs := c.flags("delete")
+	season := fs.String("season", "", "season of the bracket")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	backupFile := fs.String("backup", "", "back up the season to this file before deleting")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	if *backupFile != "" {
+		if _, err := c.backup(*season, *backupFile); err != nil {
+			return fmt.Errorf("nothing deleted, the backup failed: %w", err)
+		}
+	}
+	if err := c.conn.DeletePlayoffs(*season, *competition); err != nil {
+		return err
+	}
+	if c.output == outputJSON {
+		return c.printJSON(map[string]any{"season": *season, "competition": competitionName(*competition), "deleted": true})
+	}
+	_, err := fmt.Fprintf(c.out, "deleted the %s bracket of season %s. restore it with RestorePlayoffs\n", competitionName(*competition), *season)
+	return err
+}
+
+// IMPORTS STANDINGS FROM A JSON ARRAY OF RECORDS, IN THE SHAPE RETURNED BY GET /standings, OR FROM A CSV FILE IN
+// THE SHAPE WRITTEN BY standings export. -dry-run CHECKS EVERY RECORD AND REPORTS THE PROBLEMS WITHOUT IMPORTING
+func importStandingsCmd(c *cli, args []string) error {
+	fs := c.flags("standings import")
+	file := fs.String("file", "", "JSON or CSV file of standings records, - reads stdin")
+	format := fs.String("format", "", "json or csv (default from the extension of -file, else json)")
+	season := fs.String("season", "", "season set on every record, overriding the file")
+	dryRun := fs.Bool("dry-run", false, "check the file and report its problems without importing it")
+	if err := c.parse(fs, args, "file"); err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = "json"
+		if strings.EqualFold(filepath.Ext(*file), ".csv") {
+			*format = "csv"
+		}
+	}
+	if *format != "json" && *format != "csv" {
+		fmt.Fprintln(c.errOut, "bracketctl: -format must be json or csv")
+		return errUsage
+	}
+	var r io.Reader = c.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	var standings []models.StandingsModel
+	var problems []string
+	if *format == "csv" {
+		read, report, err := spreadsheet.ReadStandings(r, *season)
+		if err != nil {
+			return fmt.Errorf("invalid standings file %s: %w", *file, err)
+		}
+		standings = read
+		for _, p := range report.Problems {
+			problems = append(problems, p.String())
+		}
+	} else {
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&standings); err != nil {
+			return fmt.Errorf("invalid standings file %s: %w", *file, err)
+		}
+		for i := range standings {
+			if *season != "" {
+				standings[i].Season = *season
+			}
+			if err := queries.ValidateStandings(standings[i]); err != nil {
+				problems = append(problems, "record "+strconv.Itoa(i+1)+": "+err.Error())
+			}
+		}
+	}
+	if *dryRun {
+		return c.printImportReport(standings, problems)
+	}
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Fprintln(c.errOut, p)
+		}
+		return fmt.Errorf("the standings file %s has %d problems, nothing was imported", *file, len(problems))
+	}
+	imported, err := c.conn.ImportStandings(standings)
+	if err != nil {
+		return err
+	}
+	return c.printStandings(imported)
+}
+
+// WRITES THE STANDINGS OF A SEASON AS A CSV FILE, WHATEVER THE OUTPUT MODE
+func exportStandingsCmd(c *cli, args []string) error {
+	fs := c.flags("standings export")
+	season := fs.String("season", "", "season to export")
+	conference := fs.String("conference", "", "only export one conference")
+	file := fs.String("o", "", "file written instead of stdout")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	standings, err := c.conn.ListStandings(*season, *conference)
+	if err != nil {
+		return err
+	}
+	return c.writeFile(*file, func(w io.Writer) error {
+		return spreadsheet.WriteStandings(w, standings)
+	})
+}
+
+// WRITES TO file, OR TO THE OUTPUT WHEN file IS EMPTY
+func (c *cli) writeFile(file string, write func(io.Writer) error) error {
+	if file == "" {
+		return write(c.out)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if errW := write(f); errW != nil {
+		f.Close()
+		return errW
+	}
+	return f.Close()
+}
+
+// WRITES THE BRACKET AND STANDINGS OF A SEASON AS JSON, OR THE BRACKET AS A CSV FILE, WHATEVER THE OUTPUT MODE
+func exportCmd(c *cli, args []string) error {
+	fs := c.flags("export")
+	season := fs.String("season", "", "season to export")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	file := fs.String("o", "", "file written instead of stdout")
+	format := fs.String("format", "", "json or csv, a row per game (default from the extension of -o, else json)")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = "json"
+		if strings.EqualFold(filepath.Ext(*file), ".csv") {
+			*format = "csv"
+		}
+	}
+	if *format != "json" && *format != "csv" {
+		fmt.Fprintln(c.errOut, "bracketctl: -format must be json or csv")
+		return errUsage
+	}
+	playoffs, err := c.conn.ListPlayoffs(*season, *competition)
+	if err != nil {
+		return err
+	}
+	if *format == "csv" {
+		return c.writeFile(*file, func(w io.Writer) error {
+			return spreadsheet.WritePlayoffs(w, playoffs)
+		})
+	}
+	standings, err := c.conn.ListStandings(*season, "")
+	if err != nil {
+		return err
+	}
+	doc := exportDocument{
+		League:      c.conn.League,
+		Season:      *season,
+		Competition: competitionName(*competition),
+		Playoffs:    playoffs,
+		Standings:   standings,
+	}
+	return c.writeFile(*file, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	})
+}
+
+// DRAWS THE BRACKET AS AN SVG IMAGE, WHATEVER THE OUTPUT MODE
+func renderCmd(c *cli, args []string) error {
+	fs := c.flags("render")
+	season := fs.String("season", "", "season to draw")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	file := fs.String("o", "", "file written instead of stdout")
+	format := fs.String("format", "", "svg, png or pdf (default from the extension of -o, else svg)")
+	size := fs.String("size", "1200x675", "WIDTHxHEIGHT of a png in pixels, a height of 0 keeps the aspect ratio")
+	page := fs.String("page", "A4", "page size of a pdf: "+strings.Join(bracket.PDFPageSizes, ", "))
+	logos := fs.String("logos", ".", "directory the logos of local files are read from in a png or pdf, empty to leave them out")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	opts := bracket.DefaultOptions
+	if *logos != "" {
+		dir, err := filepath.Abs(*logos)
+		if err != nil {
+			return err
+		}
+		opts.LogoDir = dir
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+		if *format != "png" && *format != "pdf" {
+			*format = "svg"
+		}
+	}
+	var width, height int
+	if _, err := fmt.Sscanf(*size, "%dx%d", &width, &height); err != nil || width < 1 || height < 0 {
+		fmt.Fprintln(c.errOut, "bracketctl: -size must be WIDTHxHEIGHT, e.g. 1200x675")
+		return errUsage
+	}
+	var write func(io.Writer, bracket.Layout, string) error
+	switch *format {
+	case "svg":
+		write = bracket.WriteSVG
+	case "png":
+		write = func(w io.Writer, l bracket.Layout, title string) error {
+			return bracket.WritePNG(w, l, title, width, height)
+		}
+	case "pdf":
+		write = func(w io.Writer, l bracket.Layout, title string) error {
+			return bracket.WritePDF(w, l, title, *page)
+		}
+	default:
+		fmt.Fprintln(c.errOut, "bracketctl: -format must be svg, png or pdf")
+		return errUsage
+	}
+
+	playoffs, err := c.conn.ListPlayoffs(*season, *competition)
+	if err != nil {
+		return err
+	}
+	if len(playoffs) == 0 {
+		return fmt.Errorf("season %s has no %s bracket", *season, competitionName(*competition))
+	}
+	layout := bracket.NewLayout(playoffs, opts)
+	title := bracket.Title(*season, *competition)
+	if *file == "" {
+		return write(c.out, layout, title)
+	}
+	f, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+	if errW := write(f, layout, title); errW != nil {
+		f.Close()
+		return errW
+	}
+	return f.Close()
+}
+
+func competitionName(competition string) string {
+	if competition == "" {
+		return queries.DefaultCompetition
+	}
+	return competition
+}
+
+// WRITES THE STATIC SITE OF EVERY SEASON OF THE LEAGUE
+func siteCmd(c *cli, args []string) error {
+	fs := c.flags("site")
+	out := fs.String("out", "", "directory the site is written to, replaced as a whole")
+	title := fs.String("title", site.DefaultTitle, "title of the site")
+	if err := c.parse(fs, args, "out"); err != nil {
+		return err
+	}
+	if err := site.Generate(c.conn, *out, *title); err != nil {
+		return err
+	}
+	if c.output == outputJSON {
+		return c.printJSON(map[string]any{"out": *out, "written": true})
+	}
+	_, err := fmt.Fprintf(c.out, "wrote the site to %s\n", *out)
+	return err
+}
+
+// WRITES THE BRACKET AS A CHALLONGE TOURNAMENT, WHATEVER THE OUTPUT MODE
+func challongeExportCmd(c *cli, args []string) error {
+	fs := c.flags("challonge export")
+	season := fs.String("season", "", "season to export")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	file := fs.String("o", "", "file written instead of stdout")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	tournament, err := c.tournament(*season, *competition)
+	if err != nil {
+		return err
+	}
+	return c.writeFile(*file, func(w io.Writer) error {
+		return challonge.WriteTournament(w, tournament)
+	})
+}
+
+func (c *cli) tournament(season string, competition string) (challonge.Tournament, error) {
+	playoffs, err := c.conn.ListPlayoffs(season, competition)
+	if err != nil {
+		return challonge.Tournament{}, err
+	}
+	if len(playoffs) == 0 {
+		return challonge.Tournament{}, fmt.Errorf("season %s has no %s bracket", season, competitionName(competition))
+	}
+	return challonge.FromPlayoffs(bracket.Title(season, competition), playoffs)
+}
+
+// SEEDS A BRACKET FROM A CHALLONGE TOURNAMENT FILE: ITS PARTICIPANTS ARE IMPORTED AS THE STANDINGS OF ONE
+// CONFERENCE, RANKED SO CreatePlayoffs PAIRS THEM AS THE FIRST ROUND OF THE FILE, AND THE BRACKET IS CREATED
+// FROM THEM. RESULTS OF THE FILE ARE NOT IMPORTED
+func challongeImportCmd(c *cli, args []string) error {
+	fs := c.flags("challonge import")
+	file := fs.String("file", "", "tournament JSON file, - reads stdin")
+	season := fs.String("season", "", "season of the bracket")
+	conference := fs.String("conference", "", "conference of the imported standings (default the name of the tournament)")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	dryRun := fs.Bool("dry-run", false, "print the standings that would seed the bracket without importing them")
+	if err := c.parse(fs, args, "file", "season"); err != nil {
+		return err
+	}
+	var r io.Reader = c.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	tournament, err := challonge.ReadTournament(r)
+	if err != nil {
+		return fmt.Errorf("invalid tournament file %s: %w", *file, err)
+	}
+	if *conference == "" {
+		*conference = tournament.Name
+	}
+	standings, err := challonge.Standings(tournament, *season, *conference)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		return c.printStandings(standings)
+	}
+	// THE STANDINGS WOULD BE LEFT BEHIND BY A BRACKET THAT CAN NOT BE CREATED
+	existing, err := c.conn.ListPlayoffs(*season, *competition)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("season %s already has a %s bracket", *season, competitionName(*competition))
+	}
+	if _, errI := c.conn.ImportStandings(standings); errI != nil {
+		return errI
+	}
+	if errC := c.conn.CreatePlayoffs([]string{*conference}, *season, *competition, len(standings)); errC != nil {
+		return errC
+	}
+	playoffs, err := c.conn.ListPlayoffs(*season, *competition)
+	if err != nil {
+		return err
+	}
+	return c.printBracket(playoffs)
+}
+
+// MIRRORS THE BRACKET ON A CHALLONGE COMPATIBLE SITE, CREATING THE TOURNAMENT THE FIRST TIME AND REPORTING THE
+// DECIDED SERIES EVERY TIME. THE API KEY IS READ FROM CHALLONGE_API_KEY
+func challongePublishCmd(c *cli, args []string) error {
+	fs := c.flags("challonge publish")
+	season := fs.String("season", "", "season to publish")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	tournamentURL := fs.String("url", "", "url of the tournament on the site: letters, numbers and underscores")
+	api := fs.String("api", challonge.DefaultBaseURL, "base url of the API")
+	if err := c.parse(fs, args, "season", "url"); err != nil {
+		return err
+	}
+	apiKey := os.Getenv("CHALLONGE_API_KEY")
+	if apiKey == "" {
+		return errors.New("CHALLONGE_API_KEY is not set")
+	}
+	tournament, err := c.tournament(*season, *competition)
+	if err != nil {
+		return err
+	}
+	result, err := challonge.NewClient(*api, apiKey).Publish(*tournamentURL, tournament)
+	if err != nil {
+		return err
+	}
+	if c.output == outputJSON {
+		return c.printJSON(result)
+	}
+	_, err = fmt.Fprintf(c.out, "published %s: created %t, started %t, %d matches reported\n", result.URL, result.Created, result.Started, result.Reported)
+	return err
+}
+
+// WRITES THE BRACKET OF A SEASON AS A VERSIONED JSON DOCUMENT THAT document import RESTORES IN ANOTHER DATABASE
+func documentExportCmd(c *cli, args []string) error {
+	fs := c.flags("document export")
+	season := fs.String("season", "", "season to export")
+	competition := fs.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	file := fs.String("o", "", "file written instead of stdout")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	playoffs, err := c.conn.ListPlayoffs(*season, *competition)
+	if err != nil {
+		return err
+	}
+	if len(playoffs) == 0 {
+		return fmt.Errorf("season %s has no %s bracket", *season, competitionName(*competition))
+	}
+	doc := document.New(c.conn.League, *season, *competition, playoffs)
+	return c.writeFile(*file, func(w io.Writer) error {
+		return document.Encode(w, doc)
+	})
+}
+
+// RESTORES THE BRACKET OF A DOCUMENT WRITTEN BY document export IN THE LEAGUE OF THE COMMAND, WITH ITS GAME IDS,
+// RESULTS AND DATES. THE SEASON MUST NOT HAVE THE BRACKET YET
+func documentImportCmd(c *cli, args []string) error {
+	fs := c.flags("document import")
+	file := fs.String("file", "", "bracket document, - reads stdin")
+	if err := c.parse(fs, args, "file"); err != nil {
+		return err
+	}
+	var r io.Reader = c.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	doc, err := document.Decode(r)
+	if err != nil {
+		return err
+	}
+	if errI := c.conn.ImportPlayoffs(doc.Games()); errI != nil {
+		return errI
+	}
+	playoffs, err := c.conn.ListPlayoffs(doc.Season, doc.Competition)
+	if err != nil {
+		return err
+	}
+	return c.printBracket(playoffs)
+}
+
+// WRITES THE STANDINGS, PLAYOFFS, AUDIT LOG AND CHANGE LOG OF A SEASON TO ONE ARCHIVE FILE THAT restore READS
+func backupCmd(c *cli, args []string) error {
+	fs := c.flags("backup")
+	season := fs.String("season", "", "season to back up")
+	file := fs.String("o", "", "archive written (default LEAGUE-SEASON"+backup.Extension+")")
+	if err := c.parse(fs, args, "season"); err != nil {
+		return err
+	}
+	if *file == "" {
+		*file = backup.FileName(c.conn.League, *season)
+	}
+	b, err := c.backup(*season, *file)
+	if err != nil {
+		return err
+	}
+	if c.output == outputJSON {
+		return c.printJSON(map[string]any{"season": *season, "file": *file, "standings": len(b.Standings), "playoffs": len(b.Playoffs), "audit": len(b.Audit), "changes": len(b.Changes)})
+	}
+	_, err = fmt.Fprintf(c.out, "backed up season %s to %s: %d standings, %d games, %d audit entries, %d changes\n",
+		*season, *file, len(b.Standings), len(b.Playoffs), len(b.Audit), len(b.Changes))
+	return err
+}
+
+// WRITES THE ARCHIVE OF A SEASON. THE FILE IS WRITTEN NEXT TO file AND RENAMED ONCE COMPLETE, SO A FAILED BACKUP
+// NEVER LEAVES A TRUNCATED ARCHIVE BEHIND
+func (c *cli) backup(season string, file string) (models.SeasonBackupModel, error) {
+	b, err := c.conn.BackupSeason(season)
+	if err != nil {
+		return models.SeasonBackupModel{}, err
+	}
+	tmp := file + ".tmp"
+	if errW := c.writeFile(tmp, func(w io.Writer) error {
+		return backup.Write(w, b)
+	}); errW != nil {
+		os.Remove(tmp)
+		return models.SeasonBackupModel{}, errW
+	}
+	if errR := os.Rename(tmp, file); errR != nil {
+		os.Remove(tmp)
+		return models.SeasonBackupModel{}, errR
+	}
+	return b, nil
+}
+
+// RECREATES A SEASON FROM AN ARCHIVE OF backup IN THE LEAGUE OF THE COMMAND, IN ONE TRANSACTION. A SEASON THAT
+// ALREADY HAS RECORDS IS ONLY OVERWRITTEN WITH -replace
+func restoreCmd(c *cli, args []string) error {
+	fs := c.flags("restore")
+	file := fs.String("file", "", "archive written by backup, - reads stdin")
+	replace := fs.Bool("replace", false, "delete the standings and playoffs the season already has")
+	if err := c.parse(fs, args, "file"); err != nil {
+		return err
+	}
+	var r io.Reader = c.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	b, err := backup.Read(r)
+	if err != nil {
+		return err
+	}
+	if errR := c.conn.RestoreSeason(b, *replace, queries.RestoreAuditAsIs); errR != nil {
+		return errR
+	}
+	if c.output == outputJSON {
+		return c.printJSON(map[string]any{"season": b.Season, "standings": len(b.Standings), "playoffs": len(b.Playoffs), "restored": true})
+	}
+	_, err = fmt.Fprintf(c.out, "restored season %s backed up on %s from league %s: %d standings, %d games\n",
+		b.Season, b.CreatedAt.Format(time.RFC3339), b.League, len(b.Standings), len(b.Playoffs))
+	return err
+}
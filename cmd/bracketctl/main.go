@@ -0,0 +1,158 @@
+// COMMAND bracketctl RUNS THE PLAYOFFS AND STANDINGS QUERIES FROM A TERMINAL. THE DATABASE IS CONFIGURED WITH
+// THE SAME .env VARIABLES READ BY NewDBConnection.
+//
+//	bracketctl [-league L] [-actor A] [-output table|json] <command> [flags]
+//
+// RUN bracketctl help FOR THE LIST OF COMMANDS
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	dbconnection "AmHughesAbsalom/GO_CODE_SAMPLE.git/db_connection"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+)
+
+// OUTPUT MODES
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// RETURNED FOR MALFORMED COMMAND LINES, WHICH EXIT WITH STATUS 2 INSTEAD OF 1
+var errUsage = errors.New("usage error")
+
+type cli struct {
+	conn   *queries.PlayoffsDBConnection
+	out    io.Writer
+	errOut io.Writer
+	stdin  io.Reader
+	output string
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(c *cli, args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{"create", "create -season S -conferences East,West [-limit 8] [-competition C] [-dry-run]", createCmd},
+		{"clone", "clone -from S -season S [-competition C]", cloneCmd},
+		{"list", "list -season S [-competition C]", listCmd},
+		{"validate", "validate -season S [-competition C] [-repair]", validateCmd},
+		{"recompute", "recompute -season S [-competition C]", recomputeCmd},
+		{"set-winner", "set-winner -season S -game ID -winner home|away|TEAM_ID [-competition C]", setWinnerCmd},
+		{"revert", "revert -season S -game ID [-competition C]", revertCmd},
+		{"schedule", "schedule -season S -game ID -at 2024-04-20T19:00:00Z|none [-competition C]", scheduleCmd},
+		{"delete", "delete -season S [-competition C] [-backup FILE]", deleteCmd},
+		{"backup", "backup -season S [-o FILE]", backupCmd},
+		{"restore", "restore -file FILE|- [-replace]", restoreCmd},
+		{"standings import", "standings import -file standings.json|standings.csv|- [-format json|csv] [-season S] [-dry-run]", importStandingsCmd},
+		{"standings export", "standings export -season S [-conference C] [-o standings.csv]", exportStandingsCmd},
+		{"export", "export -season S [-competition C] [-o FILE] [-format json|csv]", exportCmd},
+		{"site", "site -out DIR [-title T]", siteCmd},
+		{"challonge export", "challonge export -season S [-competition C] [-o FILE]", challongeExportCmd},
+		{"challonge import", "challonge import -file tournament.json|- -season S [-conference C] [-competition C] [-dry-run]", challongeImportCmd},
+		{"challonge publish", "challonge publish -season S -url SLUG [-competition C] [-api URL]", challongePublishCmd},
+		{"document export", "document export -season S [-competition C] [-o bracket.json]", documentExportCmd},
+		{"document import", "document import -file bracket.json|-", documentImportCmd},
+		{"render", "render -season S [-competition C] [-o FILE] [-format svg|png|pdf] [-size WxH] [-page A4]", renderCmd},
+	}
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, connect)
+	if err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "bracketctl:", err)
+		os.Exit(1)
+	}
+}
+
+func connect() (*queries.PlayoffsDBConnection, func(), error) {
+	conn, db, err := dbconnection.NewDBConnection()
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn.PlayoffsDBConnection, func() { db.Close() }, nil
+}
+
+// PARSES THE GLOBAL FLAGS, PICKS THE COMMAND AND ONLY THEN CONNECTS TO THE DATABASE, SO USAGE ERRORS AND help
+// WORK WITHOUT ONE
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer, connect func() (*queries.PlayoffsDBConnection, func(), error)) error {
+	global := flag.NewFlagSet("bracketctl", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	league := global.String("league", queries.DefaultLeague, "league the command applies to")
+	actor := global.String("actor", os.Getenv("USER"), "name recorded in the audit log")
+	output := global.String("output", outputTable, "output mode: table or json")
+	global.Usage = func() {
+		printUsage(stderr, global)
+	}
+	if err := global.Parse(args); err != nil {
+		return errUsage
+	}
+	if *output != outputTable && *output != outputJSON {
+		fmt.Fprintln(stderr, "bracketctl: -output must be table or json")
+		return errUsage
+	}
+	rest := global.Args()
+	if len(rest) == 0 || rest[0] == "help" {
+		printUsage(stderr, global)
+		if len(rest) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	cmd, cmdArgs, ok := findCommand(rest)
+	if !ok {
+		fmt.Fprintln(stderr, "bracketctl: unknown command", rest[0])
+		printUsage(stderr, global)
+		return errUsage
+	}
+	conn, closeDB, err := connect()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+	c := &cli{
+		conn:   conn.ForLeague(*league).WithActor(*actor),
+		out:    stdout,
+		errOut: stderr,
+		stdin:  stdin,
+		output: *output,
+	}
+	return cmd.run(c, cmdArgs)
+}
+
+// MATCHES ONE AND TWO WORD COMMANDS SUCH AS list AND standings import
+func findCommand(args []string) (command, []string, bool) {
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd, args[1:], true
+		}
+		if len(args) > 1 && cmd.name == args[0]+" "+args[1] {
+			return cmd, args[2:], true
+		}
+	}
+	return command{}, nil, false
+}
+
+func printUsage(w io.Writer, global *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: bracketctl [-league L] [-actor A] [-output table|json] <command> [flags]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, cmd := range commands() {
+		fmt.Fprintln(w, "  "+cmd.usage)
+	}
+	fmt.Fprintln(w, "\nglobal flags:")
+	global.SetOutput(w)
+	global.PrintDefaults()
+}
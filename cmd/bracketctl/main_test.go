@@ -0,0 +1,483 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/challonge"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A CONNECTION TO A MOCKED DATABASE, IN THE SHAPE run EXPECTS
+func mockConnect(t *testing.T) (sqlmock.Sqlmock, func() (*queries.PlayoffsDBConnection, func(), error)) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	t.Cleanup(func() { db.Close() })
+	return mock, func() (*queries.PlayoffsDBConnection, func(), error) {
+		return &queries.PlayoffsDBConnection{DB: db}, func() {}, nil
+	}
+}
+
+// EXPECTS ListPlayoffs OF A BRACKET WITH A SINGLE GAME
+func expectOneGameBracket(mock sqlmock.Sqlmock, league string, game models.PlayoffsModel) {
+	mock.ExpectQuery(`SELECT fixture_round FROM playoffs`).
+		WithArgs("2023-2024", league, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}).AddRow(1))
+	mock.ExpectQuery(`SELECT fixture_round, game_count FROM playoffs`).
+		WithArgs("2023-2024", 1, league, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round", "game_count"}).AddRow(1, "1"))
+	mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round = \$2 AND game_count = \$3`).
+		WithArgs("2023-2024", 1, "1", league, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "game_round", "home_team_id", "home_team_name", "away_team_id", "away_team_name", "winner", "version"}).
+			AddRow(game.PlayoffsId, 1, "1", "1", game.HomeTeamId, game.HomeTeamName, game.AwayTeamId, game.AwayTeamName, game.Winner, 2))
+}
+
+func sampleGame() models.PlayoffsModel {
+	lions, tigers := uuid.New(), uuid.New()
+	lionsName, tigersName := "Lions", "Tigers"
+	return models.PlayoffsModel{
+		PlayoffsId:   uuid.New(),
+		HomeTeamId:   &lions,
+		HomeTeamName: &lionsName,
+		AwayTeamId:   &tigers,
+		AwayTeamName: &tigersName,
+		Winner:       &tigers,
+	}
+}
+
+func TestRun_UsageErrorsDoNotConnect(t *testing.T) {
+	connected := false
+	connect := func() (*queries.PlayoffsDBConnection, func(), error) {
+		connected = true
+		return nil, nil, errors.New("no database")
+	}
+	var stderr bytes.Buffer
+
+	assert.ErrorIs(t, run([]string{"promote"}, nil, &bytes.Buffer{}, &stderr, connect), errUsage)
+	assert.ErrorIs(t, run([]string{"-output", "yaml", "list"}, nil, &bytes.Buffer{}, &stderr, connect), errUsage)
+	assert.NoError(t, run([]string{"help"}, nil, &bytes.Buffer{}, &stderr, connect))
+	assert.False(t, connected)
+	assert.Contains(t, stderr.String(), "unknown command promote")
+	assert.Contains(t, stderr.String(), "standings import -file")
+}
+
+func TestList_MissingSeason(t *testing.T) {
+	mock, connect := mockConnect(t)
+	var stderr bytes.Buffer
+
+	err := run([]string{"list"}, nil, &bytes.Buffer{}, &stderr, connect)
+
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr.String(), "list needs -season")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestList_Table(t *testing.T) {
+	mock, connect := mockConnect(t)
+	game := sampleGame()
+	expectOneGameBracket(mock, "north", game)
+
+	var stdout bytes.Buffer
+	err := run([]string{"-league", "north", "list", "-season", "2023-2024"}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"ROUND", "SERIES", "GAME", "HOME", "AWAY", "WINNER", "VERSION", "ID"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"1", "1", "1", "Lions", "Tigers", "Tigers", "2", game.PlayoffsId.String()}, strings.Fields(lines[1]))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestList_JSON(t *testing.T) {
+	mock, connect := mockConnect(t)
+	game := sampleGame()
+	expectOneGameBracket(mock, queries.DefaultLeague, game)
+
+	var stdout bytes.Buffer
+	err := run([]string{"-output", "json", "list", "-season", "2023-2024"}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	var playoffs [][][]models.PlayoffsModel
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &playoffs))
+	require.Len(t, playoffs, 1)
+	assert.Equal(t, game.PlayoffsId, playoffs[0][0][0].PlayoffsId)
+}
+
+func TestWinnerOf(t *testing.T) {
+	game := sampleGame()
+
+	home, err := winnerOf(game, "home")
+	assert.NoError(t, err)
+	assert.Equal(t, *game.HomeTeamId, home)
+
+	away, err := winnerOf(game, game.AwayTeamId.String())
+	assert.NoError(t, err)
+	assert.Equal(t, *game.AwayTeamId, away)
+
+	_, err = winnerOf(game, uuid.NewString())
+	assert.ErrorContains(t, err, "does not play")
+
+	game.AwayTeamId = nil
+	_, err = winnerOf(game, "away")
+	assert.ErrorContains(t, err, "no away team yet")
+}
+
+func TestSchedule_InvalidDate(t *testing.T) {
+	mock, connect := mockConnect(t)
+
+	err := run([]string{"schedule", "-season", "2023-2024", "-game", uuid.NewString(), "-at", "tomorrow"}, nil, &bytes.Buffer{}, &bytes.Buffer{}, connect)
+
+	assert.ErrorContains(t, err, "invalid date tomorrow")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScheduleTime(t *testing.T) {
+	at, err := scheduleTime("2024-04-20T21:00:00+02:00")
+	require.NoError(t, err)
+	assert.True(t, at.Equal(time.Date(2024, 4, 20, 19, 0, 0, 0, time.UTC)))
+
+	at, err = scheduleTime("none")
+	require.NoError(t, err)
+	assert.Nil(t, at)
+}
+
+func TestStandingsImport_FromStdin(t *testing.T) {
+	mock, connect := mockConnect(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO standings`).
+		WithArgs(sqlmock.AnyArg(), nil, 1, "Lions", "LIO", nil, 10, 7, 3, 0.0, 0, 21, "East", "2024-2025", queries.DefaultLeague).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	var stdout bytes.Buffer
+	stdin := `[{"teamName":"Lions","acronym":"LIO","position":1,"gp":10,"w":7,"l":3,"pts":21,"conference":"East","season":"2023-2024"}]`
+	err := run([]string{"standings", "import", "-file", "-", "-season", "2024-2025"}, strings.NewReader(stdin), &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "2024-2025")
+	assert.Contains(t, stdout.String(), "Lions")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStandingsImport_DryRunCSV(t *testing.T) {
+	mock, connect := mockConnect(t)
+
+	var stdout bytes.Buffer
+	stdin := "team_name,conference,season,w\nLions,East,2023-2024,7\nTigers,East,2023-2024,seven\n"
+	err := run([]string{"standings", "import", "-file", "-", "-format", "csv", "-dry-run"}, strings.NewReader(stdin), &stdout, &bytes.Buffer{}, connect)
+
+	assert.ErrorContains(t, err, "has 1 problems")
+	assert.Contains(t, stdout.String(), "Lions")
+	assert.Contains(t, stdout.String(), "2 records, 1 problems")
+	assert.Contains(t, stdout.String(), `line 3, column w: "seven" is not a whole number`)
+	assert.NoError(t, mock.ExpectationsWereMet(), "a dry run does not write")
+}
+
+func TestExport_CSV(t *testing.T) {
+	mock, connect := mockConnect(t)
+	game := sampleGame()
+	expectOneGameBracket(mock, queries.DefaultLeague, game)
+	file := filepath.Join(t.TempDir(), "bracket.csv")
+
+	err := run([]string{"export", "-season", "2023-2024", "-o", file}, nil, &bytes.Buffer{}, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\r\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "season,competition,round,series,game,home_team")
+	assert.Contains(t, lines[1], ",Lions,"+game.HomeTeamId.String()+",Tigers,")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChallongeExport(t *testing.T) {
+	mock, connect := mockConnect(t)
+	game := sampleGame()
+	expectOneGameBracket(mock, queries.DefaultLeague, game)
+
+	var stdout bytes.Buffer
+	err := run([]string{"challonge", "export", "-season", "2023-2024"}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	tournament, err := challonge.ReadTournament(&stdout)
+	require.NoError(t, err)
+	require.Len(t, tournament.Participants, 2)
+	assert.Equal(t, "Lions", tournament.Participants[0].Participant.Name)
+	assert.Equal(t, game.HomeTeamId.String(), tournament.Participants[0].Participant.Misc)
+	require.Len(t, tournament.Matches, 1)
+	assert.Equal(t, challonge.MatchComplete, tournament.Matches[0].Match.State)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChallongeImport_DryRun(t *testing.T) {
+	mock, connect := mockConnect(t)
+
+	var stdout bytes.Buffer
+	err := run([]string{"challonge", "import", "-file", "../../challonge/testdata/tournament.json", "-season", "2024", "-dry-run"}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, []string{"2024", "Spring", "Invitational", "1", "Lions"}, strings.Fields(lines[1])[:5])
+	assert.Contains(t, lines[4], "Bears")
+	assert.NoError(t, mock.ExpectationsWereMet(), "a dry run does not write")
+}
+
+func TestDocument_ExportThenImport(t *testing.T) {
+	mock, connect := mockConnect(t)
+	game := sampleGame()
+	expectOneGameBracket(mock, queries.DefaultLeague, game)
+	file := filepath.Join(t.TempDir(), "bracket.json")
+
+	err := run([]string{"document", "export", "-season", "2023-2024", "-o", file}, nil, &bytes.Buffer{}, &bytes.Buffer{}, connect)
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE playoffs_id = ANY`).
+		WithArgs(pq.StringArray{game.PlayoffsId.String()}).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`INSERT INTO playoffs`).
+		WithArgs(game.PlayoffsId, 1, "1", "1", game.HomeTeamId, "Lions", nil, uuid.Nil, game.AwayTeamId, "Tigers", nil, uuid.Nil,
+			"2023-2024", queries.DefaultLeague, queries.DefaultCompetition, game.Winner, 2, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO playoffs_audit`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO outbox`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	expectOneGameBracket(mock, queries.DefaultLeague, game)
+
+	var stdout bytes.Buffer
+	err = run([]string{"document", "import", "-file", file}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), game.PlayoffsId.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDocumentImport_Invalid(t *testing.T) {
+	mock, connect := mockConnect(t)
+
+	err := run([]string{"document", "import", "-file", "-"}, strings.NewReader(`{"version":7}`), &bytes.Buffer{}, &bytes.Buffer{}, connect)
+
+	assert.ErrorContains(t, err, "version 7 is not supported")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// EXPECTS BackupSeason OF A SEASON WITH ONE STANDINGS RECORD AND ONE GAME
+func expectBackup(mock sqlmock.Sqlmock, league string, standingsId uuid.UUID, game models.PlayoffsModel) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM standings`).
+		WithArgs("2023-2024", league).
+		WillReturnRows(sqlmock.NewRows([]string{"standings_id", "team_name", "conference", "season"}).AddRow(standingsId, "Lions", "East", "2023-2024"))
+	mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND league = \$2`).
+		WithArgs("2023-2024", league).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "game_round", "season", "competition", "version"}).
+			AddRow(game.PlayoffsId, 0, "FINAL", "1", "2023-2024", queries.DefaultCompetition, 1))
+	mock.ExpectQuery(`SELECT \* FROM playoffs_audit`).WillReturnRows(sqlmock.NewRows([]string{"audit_id"}))
+	mock.ExpectQuery(`SELECT \* FROM playoffs_changes`).WillReturnRows(sqlmock.NewRows([]string{"change_seq"}))
+	mock.ExpectQuery(`SELECT NOW\(\)`).WillReturnRows(sqlmock.NewRows([]string{"now"}).AddRow(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)))
+	mock.ExpectRollback()
+}
+
+func TestBackup_ThenRestoreInAnotherLeague(t *testing.T) {
+	mock, connect := mockConnect(t)
+	standingsId, game := uuid.New(), sampleGame()
+	expectBackup(mock, queries.DefaultLeague, standingsId, game)
+	file := filepath.Join(t.TempDir(), "season.json.gz")
+
+	var stdout bytes.Buffer
+	err := run([]string{"backup", "-season", "2023-2024", "-o", file}, nil, &stdout, &bytes.Buffer{}, connect)
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "1 standings, 1 games")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings WHERE season`).
+		WithArgs("2023-2024", "north").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings WHERE standings_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`INSERT INTO standings`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO playoffs`).
+		WithArgs(game.PlayoffsId, 0, "FINAL", "1", nil, nil, nil, uuid.Nil, nil, nil, nil, uuid.Nil, "2023-2024", "north", queries.DefaultCompetition, nil, 1, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO playoffs_audit`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO outbox`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	stdout.Reset()
+	err = run([]string{"-league", "north", "restore", "-file", file}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "restored season 2023-2024 backed up on 2024-07-01T00:00:00Z from league default")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_BackupFailsNothingDeleted(t *testing.T) {
+	mock, connect := mockConnect(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM standings`).WillReturnRows(sqlmock.NewRows([]string{"standings_id"}))
+	mock.ExpectQuery(`SELECT \* FROM playoffs`).WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	mock.ExpectRollback()
+	file := filepath.Join(t.TempDir(), "season.json.gz")
+
+	err := run([]string{"delete", "-season", "2023-2024", "-backup", file}, nil, &bytes.Buffer{}, &bytes.Buffer{}, connect)
+
+	assert.ErrorContains(t, err, "nothing deleted")
+	assert.NoFileExists(t, file)
+	assert.NoError(t, mock.ExpectationsWereMet(), "DeletePlayoffs is not called")
+}
+
+func TestCreate_DryRun(t *testing.T) {
+	mock, connect := mockConnect(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT \*, RANK\(\)`).
+		WithArgs("East", "2023-2024", 2, queries.DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "team_name"}).AddRow(uuid.New(), "Lions").AddRow(uuid.New(), "Tigers"))
+	mock.ExpectRollback()
+	var stdout bytes.Buffer
+
+	err := run([]string{"create", "-season", "2023-2024", "-conferences", "East", "-limit", "2", "-dry-run"}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	assert.Regexp(t, `2\s+Tigers`, stdout.String())
+	assert.Contains(t, stdout.String(), "FINAL")
+	assert.Contains(t, stdout.String(), "2 games would be created, nothing was written")
+	assert.NoError(t, mock.ExpectationsWereMet(), "nothing is inserted")
+}
+
+func TestClone_UnknownFormat(t *testing.T) {
+	mock, connect := mockConnect(t)
+	mock.ExpectQuery(`SELECT after_value FROM playoffs_audit`).
+		WithArgs("2022-2023", queries.DefaultLeague, queries.DefaultCompetition, queries.AuditCreatePlayoffs).
+		WillReturnRows(sqlmock.NewRows([]string{"after_value"}))
+
+	err := run([]string{"clone", "-from", "2022-2023", "-season", "2023-2024"}, nil, &bytes.Buffer{}, &bytes.Buffer{}, connect)
+
+	assert.ErrorIs(t, err, queries.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPrintCloneReport(t *testing.T) {
+	var stdout bytes.Buffer
+	previous := 3
+	c := &cli{out: &stdout}
+
+	err := c.printCloneReport(models.CloneReportModel{
+		FromSeason:  "2022-2023",
+		Season:      "2023-2024",
+		Conferences: []string{"East", "West"},
+		Limit:       2,
+		Kept:        []models.QualifiedTeamModel{{TeamName: "Lions", Seed: 1, PreviousSeed: &previous}},
+		Added:       []models.QualifiedTeamModel{{TeamName: "Tigers", Seed: 4}},
+		Dropped:     []models.QualifiedTeamModel{{TeamName: "Bears", Seed: 2}},
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "created 2023-2024 from the format of 2022-2023: conferences East,West, limit 2")
+	assert.Regexp(t, `kept\s+1\s+3\s+Lions`, stdout.String())
+	assert.Regexp(t, `added\s+4\s+-\s+Tigers`, stdout.String())
+	assert.Regexp(t, `dropped\s+-\s+2\s+Bears`, stdout.String())
+}
+
+func TestValidate_FailsWithViolations(t *testing.T) {
+	mock, connect := mockConnect(t)
+	game := sampleGame()
+	mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "game_round", "home_team_id", "home_team_name", "away_team_id", "away_team_name", "winner", "version"}).
+			AddRow(game.PlayoffsId, 1, "1", "1", game.HomeTeamId, game.HomeTeamName, game.AwayTeamId, game.AwayTeamName, game.Winner, 2))
+	var stdout bytes.Buffer
+
+	err := run([]string{"validate", "-season", "2023-2024"}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.EqualError(t, err, "the bracket of 2023-2024 has 1 violations")
+	assert.Regexp(t, `1\s+1\s+`+queries.ViolationMissingFinal+`\s+false`, stdout.String())
+	assert.Contains(t, stdout.String(), "1 violations, 0 games repaired, 1 remaining")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecompute_RefusesBracketWithoutFinal(t *testing.T) {
+	mock, connect := mockConnect(t)
+	game := sampleGame()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT playoffs_id FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	mock.ExpectQuery(`SELECT \* FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "game_round", "home_team_id", "away_team_id", "winner"}).
+			AddRow(game.PlayoffsId, 1, "1", "1", game.HomeTeamId, game.AwayTeamId, game.Winner))
+	mock.ExpectRollback()
+
+	err := run([]string{"recompute", "-season", "2023-2024"}, nil, &bytes.Buffer{}, &bytes.Buffer{}, connect)
+
+	require.ErrorIs(t, err, queries.ErrInvalidInput)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRender_FormatFromExtension(t *testing.T) {
+	mock, connect := mockConnect(t)
+	expectOneGameBracket(mock, queries.DefaultLeague, sampleGame())
+	file := filepath.Join(t.TempDir(), "bracket.png")
+
+	err := run([]string{"render", "-season", "2023-2024", "-o", file, "-size", "400x0"}, nil, &bytes.Buffer{}, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	f, errO := os.Open(file)
+	require.NoError(t, errO)
+	defer f.Close()
+	cfg, errD := png.DecodeConfig(f)
+	require.NoError(t, errD)
+	assert.Equal(t, 400, cfg.Width)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRender_InvalidSize(t *testing.T) {
+	mock, connect := mockConnect(t)
+	var stderr bytes.Buffer
+
+	err := run([]string{"render", "-season", "2023-2024", "-format", "png", "-size", "big"}, nil, &bytes.Buffer{}, &stderr, connect)
+
+	assert.ErrorIs(t, err, errUsage)
+	assert.Contains(t, stderr.String(), "-size must be WIDTHxHEIGHT")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSite_WritesEverySeason(t *testing.T) {
+	mock, connect := mockConnect(t)
+	mock.ExpectQuery(`SELECT season, competition, COUNT\(\*\) AS games FROM playoffs`).
+		WithArgs(queries.DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"season", "competition", "games"}).AddRow("2023-2024", queries.DefaultCompetition, 1))
+	expectOneGameBracket(mock, queries.DefaultLeague, sampleGame())
+	out := filepath.Join(t.TempDir(), "public")
+
+	var stdout bytes.Buffer
+	err := run([]string{"site", "-out", out, "-title", "North"}, nil, &stdout, &bytes.Buffer{}, connect)
+
+	require.NoError(t, err)
+	assert.Equal(t, "wrote the site to "+out+"\n", stdout.String())
+	assert.FileExists(t, filepath.Join(out, "seasons", "2023-2024", "index.html"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
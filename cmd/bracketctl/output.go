@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+)
+
+func (c *cli) printJSON(v any) error {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// PRINTS THE BRACKET AS RETURNED BY ListPlayoffs, ONE ROW PER GAME IN TABLE MODE
+func (c *cli) printBracket(playoffs [][][]models.PlayoffsModel) error {
+	if c.output == outputJSON {
+		return c.printJSON(playoffs)
+	}
+	var games []models.PlayoffsModel
+	for _, round := range playoffs {
+		for _, series := range round {
+			games = append(games, series...)
+		}
+	}
+	if len(games) == 0 {
+		_, err := fmt.Fprintln(c.out, "no games")
+		return err
+	}
+	return c.printGames(games)
+}
+
+func (c *cli) printGames(games []models.PlayoffsModel) error {
+	if c.output == outputJSON {
+		return c.printJSON(games)
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROUND\tSERIES\tGAME\tHOME\tAWAY\tWINNER\tVERSION\tID")
+	for _, g := range games {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			intOrDash(g.FixtureRound),
+			stringOrDash(g.GameCount),
+			g.GameRound,
+			stringOrDash(g.HomeTeamName),
+			stringOrDash(g.AwayTeamName),
+			winnerName(g),
+			g.Version,
+			g.PlayoffsId,
+		)
+	}
+	return w.Flush()
+}
+
+func (c *cli) printStandings(standings []models.StandingsModel) error {
+	if c.output == outputJSON {
+		return c.printJSON(standings)
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEASON\tCONFERENCE\tPOS\tTEAM\tGP\tW\tL\tPTS\tID")
+	for _, s := range standings {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%d\t%d\t%d\t%s\n", s.Season, s.Conference, s.Position, s.TeamName, s.Gp, s.W, s.L, s.Pts, s.StandingsId)
+	}
+	return w.Flush()
+}
+
+// PRINTS WHAT standings import -dry-run FOUND: THE RECORDS OF THE FILE AND ITS PROBLEMS. A FILE WITH PROBLEMS FAILS
+// THE COMMAND SO SCRIPTS CAN CHECK A FILE BEFORE IMPORTING IT
+func (c *cli) printImportReport(standings []models.StandingsModel, problems []string) error {
+	if c.output == outputJSON {
+		if problems == nil {
+			problems = []string{}
+		}
+		if err := c.printJSON(map[string]any{"dryRun": true, "rows": len(standings), "problems": problems, "standings": standings}); err != nil {
+			return err
+		}
+	} else {
+		if err := c.printStandings(standings); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "\n%d records, %d problems\n", len(standings), len(problems))
+		for _, p := range problems {
+			fmt.Fprintln(c.out, p)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("the standings file has %d problems", len(problems))
+	}
+	return nil
+}
+
+// PRINTS THE QUALIFIED TEAMS OF A CLONED BRACKET, THOSE OF THE PREVIOUS SEASON FIRST, THEN THE NEW AND THE DROPPED
+func (c *cli) printCloneReport(report models.CloneReportModel) error {
+	if c.output == outputJSON {
+		return c.printJSON(report)
+	}
+	fmt.Fprintf(c.out, "created %s from the format of %s: conferences %s, limit %d\n\n",
+		report.Season, report.FromSeason, strings.Join(report.Conferences, ","), report.Limit)
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tSEED\tPREVIOUS\tTEAM")
+	for _, t := range report.Kept {
+		fmt.Fprintf(w, "kept\t%d\t%s\t%s\n", t.Seed, intOrDash(t.PreviousSeed), t.TeamName)
+	}
+	for _, t := range report.Added {
+		fmt.Fprintf(w, "added\t%d\t-\t%s\n", t.Seed, t.TeamName)
+	}
+	for _, t := range report.Dropped {
+		fmt.Fprintf(w, "dropped\t-\t%d\t%s\n", t.Seed, t.TeamName)
+	}
+	return w.Flush()
+}
+
+// PRINTS THE VIOLATIONS OF A BRACKET AND THE GAMES A REPAIR OR RECOMPUTE CHANGED. AN ERROR IS RETURNED WHILE VIOLATIONS REMAIN
+func (c *cli) printIntegrityReport(report models.BracketIntegrityModel) error {
+	if c.output == outputJSON {
+		if err := c.printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ROUND\tSERIES\tCODE\tREPAIRABLE\tMESSAGE")
+		for _, v := range report.Violations {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%t\t%s\n", v.Round, v.Series, v.Code, v.Repairable, v.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "\n%d violations, %d games repaired, %d remaining\n", len(report.Violations), len(report.Repaired), len(report.Remaining))
+	}
+	if !report.Valid {
+		return fmt.Errorf("the bracket of %s has %d violations", report.Season, len(report.Remaining))
+	}
+	return nil
+}
+
+// PRINTS WHAT create -dry-run WOULD CREATE: THE QUALIFIED TEAMS BY SEED, THEN EVERY GAME
+func (c *cli) printPreview(preview models.PlayoffsPreviewModel) error {
+	if c.output == outputJSON {
+		return c.printJSON(preview)
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEED\tTEAM")
+	for _, t := range preview.Qualified {
+		fmt.Fprintf(w, "%d\t%s\n", t.Seed, t.TeamName)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out)
+	if err := c.printBracket(preview.Bracket); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(c.out, "\n%d games would be created, nothing was written\n", preview.Rows)
+	return err
+}
+
+// THE NAME OF THE TEAM THAT WON THE GAME, ITS ID WHEN IT IS IN NEITHER SLOT
+func winnerName(g models.PlayoffsModel) string {
+	switch {
+	case g.Winner == nil:
+		return "-"
+	case g.HomeTeamId != nil && *g.HomeTeamId == *g.Winner:
+		return stringOrDash(g.HomeTeamName)
+	case g.AwayTeamId != nil && *g.AwayTeamId == *g.Winner:
+		return stringOrDash(g.AwayTeamName)
+	}
+	return g.Winner.String()
+}
+
+func stringOrDash(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+	return *s
+}
+
+func intOrDash(i *int) string {
+	if i == nil {
+		return "-"
+	}
+	return strconv.Itoa(*i)
+}
@@ -0,0 +1,107 @@
+// COMMAND bracketd SERVES THE PLAYOFFS AND STANDINGS HTTP API. THE DATABASE IS CONFIGURED WITH THE SAME
+// .env VARIABLES READ BY NewDBConnection, AND HTTP_ADDR SETS THE LISTEN ADDRESS (DEFAULT :8080).
+// COMMITTED BRACKET CHANGES ARE ALSO SENT TO THE SUBSCRIBED WEBHOOKS THROUGH THE OUTBOX, AND WHEN SITE_DIR IS SET
+// THE STATIC SITE OF THE LEAGUE OF EVERY CHANGE IS REGENERATED UNDER SITE_DIR/<league> (TITLED SITE_TITLE).
+// API_TOKENS_FILE IS REQUIRED AND NAMES THE JSON FILE OF API TOKENS (SEE server.ReadCredentials)
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	dbconnection "AmHughesAbsalom/GO_CODE_SAMPLE.git/db_connection"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/outbox"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/server"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/site"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/webhooks"
+)
+
+func main() {
+	credentials, err := readCredentials(os.Getenv("API_TOKENS_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn, db, err := dbconnection.NewDBConnection()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	// ON SIGINT/SIGTERM STOP ACCEPTING REQUESTS, END THE EVENT STREAMS AND STOP DRAINING THE OUTBOX.
+	// CHANGES LEFT IN THE OUTBOX ARE PUBLISHED ON THE NEXT START
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dispatcher := webhooks.NewDispatcher(webhooks.DBStore(conn.PlayoffsDBConnection), 0)
+	handlers := []outbox.Handler{dispatcher.Deliver}
+	if dir := os.Getenv("SITE_DIR"); dir != "" {
+		handlers = append(handlers, site.NewPublisher(conn.PlayoffsDBConnection, dir, os.Getenv("SITE_TITLE")).Handle)
+	}
+	relay := outbox.NewRelay(conn.PlayoffsDBConnection, handlers...)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(ctx)
+	}()
+
+	addr := os.Getenv("HTTP_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           server.New(conn.PlayoffsDBConnection, credentials),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if errS := srv.Shutdown(shutdownCtx); errS != nil {
+			log.Println("failed to shut down the http server: ", errS.Error())
+		}
+	}()
+
+	log.Println("bracketd listening on ", addr)
+	if errL := srv.ListenAndServe(); !errors.Is(errL, http.ErrServerClosed) {
+		log.Println("http server failed: ", errL.Error())
+		return
+	}
+	// WAITING FOR THE IN-FLIGHT REQUESTS AND THE CURRENT OUTBOX BATCH BEFORE CLOSING THE DATABASE
+	<-shutdown
+	<-relayDone
+}
+
+// THE SERVER FAILS CLOSED: WITHOUT A TOKENS FILE IT DOES NOT START RATHER THAN SERVE EVERY LEAGUE UNAUTHENTICATED
+func readCredentials(path string) (map[string]server.Credential, error) {
+	if path == "" {
+		return nil, errors.New("API_TOKENS_FILE is not set")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	credentials, err := server.ReadCredentials(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("%s has no API tokens", path)
+	}
+	return credentials, nil
+}
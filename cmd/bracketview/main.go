@@ -0,0 +1,169 @@
+// COMMAND bracketview SHOWS THE BRACKET OF A SEASON IN THE TERMINAL AND LETS AN OPERATOR RECORD RESULTS, FOR
+// COURTSIDE USE. THE DATABASE IS CONFIGURED WITH THE SAME .env VARIABLES READ BY NewDBConnection.
+//
+//	bracketview -season 2023-2024 [-competition C] [-league L] [-actor A] [-refresh 15s]
+//
+// MOVE BETWEEN SERIES WITH THE ARROWS OR hjkl, PICK A GAME OF THE SERIES WITH TAB, PRESS 1 OR 2 FOR THE HOME
+// OR AWAY WIN AND u TO CLEAR A WINNER. EVERY RESULT IS CONFIRMED WITH y BEFORE IT IS SAVED
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	dbconnection "AmHughesAbsalom/GO_CODE_SAMPLE.git/db_connection"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"golang.org/x/term"
+)
+
+const (
+	ansiAltScreen   = "\x1b[?1049h"
+	ansiMainScreen  = "\x1b[?1049l"
+	ansiHideCursor  = "\x1b[?25l"
+	ansiShowCursor  = "\x1b[?25h"
+	ansiHome        = "\x1b[H"
+	ansiClearToEnd  = "\x1b[K"
+	ansiClearScreen = "\x1b[2J"
+)
+
+func main() {
+	season := flag.String("season", "", "season of the bracket")
+	competition := flag.String("competition", "", "competition of the season (default "+queries.DefaultCompetition+")")
+	league := flag.String("league", queries.DefaultLeague, "league of the bracket")
+	actor := flag.String("actor", os.Getenv("USER"), "name recorded in the audit log")
+	refresh := flag.Duration("refresh", 15*time.Second, "how often the bracket is reloaded to show results entered elsewhere, 0 disables it")
+	flag.Parse()
+	if *season == "" {
+		fmt.Fprintln(os.Stderr, "bracketview: -season is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Fprintln(os.Stderr, "bracketview: stdin is not a terminal, use bracketctl in scripts")
+		os.Exit(2)
+	}
+
+	conn, db, err := dbconnection.NewDBConnection()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bracketview:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	v := newView(conn.ForLeague(*league).WithActor(*actor), *season, *competition)
+	if err := runTerminal(fd, os.Stdin, os.Stdout, v, *refresh); err != nil {
+		fmt.Fprintln(os.Stderr, "bracketview:", err)
+		os.Exit(1)
+	}
+}
+
+// SWITCHES THE TERMINAL TO RAW MODE ON THE ALTERNATE SCREEN AND REDRAWS THE VIEW AFTER EVERY KEY AND RELOAD
+// UNTIL THE OPERATOR QUITS. THE TERMINAL IS RESTORED ON RETURN
+func runTerminal(fd int, in io.Reader, out io.Writer, v *view, refresh time.Duration) error {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+	fmt.Fprint(out, ansiAltScreen+ansiHideCursor+ansiClearScreen)
+	defer fmt.Fprint(out, ansiShowCursor+ansiMainScreen)
+
+	keys := make(chan string)
+	go readKeys(in, keys)
+	var tick <-chan time.Time
+	if refresh > 0 {
+		ticker := time.NewTicker(refresh)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		width, height, errS := term.GetSize(fd)
+		if errS != nil {
+			width, height = 80, 24
+		}
+		draw(out, render(v, width, height))
+		select {
+		case k, ok := <-keys:
+			if !ok || v.handleKey(k) {
+				return nil
+			}
+		case <-tick:
+			// A RELOAD WOULD DROP THE RESULT THE OPERATOR IS CONFIRMING
+			if v.pending == nil {
+				v.reload()
+			}
+		}
+	}
+}
+
+func draw(out io.Writer, lines []string) {
+	var b strings.Builder
+	b.WriteString(ansiHome)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString(ansiClearToEnd)
+	}
+	fmt.Fprint(out, b.String())
+}
+
+// SENDS THE KEYS READ FROM THE TERMINAL UNTIL IT IS CLOSED
+func readKeys(in io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+// SPLITS RAW TERMINAL INPUT INTO KEYS. ARROWS ARRIVE AS ESC [ A..D
+func parseKeys(b []byte) []string {
+	var keys []string
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == 0x1b && i+2 < len(b) && (b[i+1] == '[' || b[i+1] == 'O'):
+			switch b[i+2] {
+			case 'A':
+				keys = append(keys, keyUp)
+			case 'B':
+				keys = append(keys, keyDown)
+			case 'C':
+				keys = append(keys, keyRight)
+			case 'D':
+				keys = append(keys, keyLeft)
+			}
+			i += 2
+		case b[i] == 0x1b:
+			keys = append(keys, keyEsc)
+		case b[i] == 0x03:
+			keys = append(keys, keyCtrlC)
+		case b[i] == '\t':
+			keys = append(keys, keyTab)
+		case b[i] >= 0x20 && b[i] < 0x7f:
+			keys = append(keys, string(b[i]))
+		}
+	}
+	return keys
+}
+
+func competitionName(competition string) string {
+	if competition == "" {
+		return queries.DefaultCompetition
+	}
+	return competition
+}
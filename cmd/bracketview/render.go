@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+)
+
+const (
+	// WIDTH OF A ROUND, SERIES BOX AND GAP INCLUDED
+	columnWidth = 26
+	// LINES TAKEN BY A SERIES OF THE FIRST ROUND. A SERIES OF THE NEXT ROUND IS CENTERED ON THE TWO IT IS FED BY
+	seriesLines = 4
+	// LINES ABOVE AND BELOW THE BRACKET: TITLE, THEN SELECTED GAME, STATUS AND KEYS
+	headerLines = 1
+	footerLines = 3
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiReverse = "\x1b[7m"
+)
+
+type style uint8
+
+const (
+	stylePlain style = iota
+	styleBold
+	styleReverse
+)
+
+type cell struct {
+	r     rune
+	style style
+}
+
+// A GRID OF STYLED CHARACTERS, SO THE BRACKET CAN BE DRAWN FIRST AND CROPPED TO THE TERMINAL AFTERWARDS
+type canvas struct {
+	cells [][]cell
+}
+
+func newCanvas(width int, height int) *canvas {
+	c := &canvas{cells: make([][]cell, height)}
+	for y := range c.cells {
+		c.cells[y] = make([]cell, width)
+		for x := range c.cells[y] {
+			c.cells[y][x] = cell{r: ' '}
+		}
+	}
+	return c
+}
+
+func (c *canvas) text(x int, y int, s string, st style) {
+	if y < 0 || y >= len(c.cells) {
+		return
+	}
+	for _, r := range s {
+		if x >= 0 && x < len(c.cells[y]) {
+			c.cells[y][x] = cell{r: r, style: st}
+		}
+		x++
+	}
+}
+
+// SERIALIZES THE width x height WINDOW STARTING AT (x0, y0), ONE STRING PER LINE WITH ANSI STYLES
+func (c *canvas) window(x0 int, y0 int, width int, height int) []string {
+	lines := make([]string, 0, height)
+	for y := y0; y < y0+height; y++ {
+		var b strings.Builder
+		current := stylePlain
+		if y >= 0 && y < len(c.cells) {
+			for x := x0; x < x0+width && x < len(c.cells[y]); x++ {
+				cl := c.cells[y][x]
+				if cl.style != current {
+					b.WriteString(ansiReset)
+					switch cl.style {
+					case styleBold:
+						b.WriteString(ansiBold)
+					case styleReverse:
+						b.WriteString(ansiReverse)
+					}
+					current = cl.style
+				}
+				b.WriteRune(cl.r)
+			}
+		}
+		if current != stylePlain {
+			b.WriteString(ansiReset)
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// FIRST LINE OF THE SERIES AT position OF THE GIVEN ROUND
+func seriesTop(round int, position int) int {
+	span := 1 << round
+	return seriesLines*span*position + (seriesLines/2)*(span-1)
+}
+
+func fit(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// DRAWS ONE SERIES: A HEADER WITH THE RESULT OF EVERY GAME, THEN THE HOME AND AWAY TEAMS WITH THEIR WINS
+func drawSeries(c *canvas, x int, y int, series []models.PlayoffsModel, selected bool, selectedGame int) {
+	if len(series) == 0 {
+		return
+	}
+	first := series[0]
+	label := "R" + intOrDash(first.FixtureRound) + " #" + stringOrDash(first.GameCount)
+	if first.GameCount != nil && *first.GameCount == "FINAL" {
+		label = "FINAL"
+	}
+	marker := "  "
+	if selected {
+		marker = "> "
+	}
+	c.text(x, y, marker+label+" ", styleBold)
+	gx := x + len(marker) + len(label) + 1
+	for i, g := range series {
+		mark := "·"
+		switch {
+		case g.Winner == nil:
+		case g.HomeTeamId != nil && *g.Winner == *g.HomeTeamId:
+			mark = "H"
+		case g.AwayTeamId != nil && *g.Winner == *g.AwayTeamId:
+			mark = "A"
+		}
+		st := stylePlain
+		if selected && i == selectedGame {
+			st = styleReverse
+		}
+		c.text(gx, y, mark, st)
+		gx += 2
+	}
+
+	summary := bracket.Summarize(series)
+	homeWins, awayWins := summary.Home.Wins, summary.Away.Wins
+	homeStyle, awayStyle := stylePlain, stylePlain
+	if summary.Home.Winner {
+		homeStyle = styleBold
+	}
+	if summary.Away.Winner {
+		awayStyle = styleBold
+	}
+	nameWidth := columnWidth - 8
+	c.text(x+2, y+1, fit(stringOrDash(first.HomeTeamName), nameWidth)+fmt.Sprintf("%2d", homeWins), homeStyle)
+	c.text(x+2, y+2, fit(stringOrDash(first.AwayTeamName), nameWidth)+fmt.Sprintf("%2d", awayWins), awayStyle)
+}
+
+// DESCRIBES THE SELECTED GAME ON ONE LINE
+func describeGame(g *models.PlayoffsModel) string {
+	if g == nil {
+		return "no games in this bracket"
+	}
+	winner := "no winner yet"
+	if g.Winner != nil {
+		winner = "winner " + winnerName(*g)
+	}
+	return fmt.Sprintf("game %s: %s vs %s, %s (version %d)", g.GameRound, stringOrDash(g.HomeTeamName), stringOrDash(g.AwayTeamName), winner, g.Version)
+}
+
+func winnerName(g models.PlayoffsModel) string {
+	switch {
+	case g.Winner == nil:
+		return "-"
+	case g.HomeTeamId != nil && *g.HomeTeamId == *g.Winner:
+		return stringOrDash(g.HomeTeamName)
+	case g.AwayTeamId != nil && *g.AwayTeamId == *g.Winner:
+		return stringOrDash(g.AwayTeamName)
+	}
+	return g.Winner.String()
+}
+
+// THE FRAME SHOWN FOR A width x height TERMINAL, SCROLLED SO THE SELECTED SERIES IS VISIBLE
+func render(v *view, width int, height int) []string {
+	bodyHeight := height - headerLines - footerLines
+	if bodyHeight < seriesLines {
+		bodyHeight = seriesLines
+	}
+
+	canvasHeight := 0
+	for r, round := range v.bracket {
+		if bottom := seriesTop(r, len(round)); bottom > canvasHeight {
+			canvasHeight = bottom
+		}
+	}
+	c := newCanvas(columnWidth*len(v.bracket), canvasHeight)
+	for r, round := range v.bracket {
+		for s, series := range round {
+			selected := r == v.round && s == v.series
+			drawSeries(c, r*columnWidth, seriesTop(r, s), series, selected, v.game)
+		}
+	}
+
+	// KEEPING THE SELECTED SERIES INSIDE THE WINDOW
+	x0, y0 := 0, 0
+	if right := (v.round + 1) * columnWidth; right > width {
+		x0 = right - width
+	}
+	if bottom := seriesTop(v.round, v.series) + seriesLines; bottom > bodyHeight {
+		y0 = bottom - bodyHeight
+	}
+
+	title := fmt.Sprintf("%s · season %s", competitionName(v.competition), v.season)
+	lines := []string{ansiBold + fit(title, width) + ansiReset}
+	lines = append(lines, c.window(x0, y0, width, bodyHeight)...)
+	lines = append(lines, fit(describeGame(v.selected()), width))
+	switch {
+	case v.pending != nil:
+		lines = append(lines, ansiReverse+fit(v.pending.prompt, width)+ansiReset)
+	default:
+		lines = append(lines, fit(v.status, width))
+	}
+	lines = append(lines, fit("arrows/hjkl move  tab game  1 home wins  2 away wins  u clear  r reload  q quit", width))
+	return lines
+}
+
+func stringOrDash(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+	return *s
+}
+
+func intOrDash(i *int) string {
+	if i == nil {
+		return "-"
+	}
+	return fmt.Sprint(*i)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+)
+
+// KEYS THE VIEW REACTS TO. PRINTABLE KEYS ARE THEIR OWN CHARACTER
+const (
+	keyUp    = "up"
+	keyDown  = "down"
+	keyLeft  = "left"
+	keyRight = "right"
+	keyTab   = "tab"
+	keyEsc   = "esc"
+	keyCtrlC = "ctrl+c"
+)
+
+// THE QUERIES THE VIEWER RUNS, IMPLEMENTED BY queries.PlayoffsDBConnection
+type store interface {
+	ListPlayoffs(season string, competition string) ([][][]models.PlayoffsModel, error)
+	UpdatePlayoffs(playoffsId uuid.UUID, playoffs queries.PlayoffsModelReqQuery) error
+	UpdatePlayoffsToNull(playoffsId uuid.UUID, round int, teamId uuid.UUID, season string, competition string) error
+}
+
+// A RESULT WAITING FOR THE OPERATOR TO CONFIRM IT WITH y
+type pendingAction struct {
+	prompt string
+	run    func() error
+}
+
+type view struct {
+	store       store
+	season      string
+	competition string
+	bracket     [][][]models.PlayoffsModel
+
+	// THE SELECTED SERIES, AND THE SELECTED GAME INSIDE IT
+	round, series, game int
+
+	pending *pendingAction
+	status  string
+}
+
+func newView(s store, season string, competition string) *view {
+	v := &view{store: s, season: season, competition: competition}
+	v.reload()
+	return v
+}
+
+// READS THE BRACKET AGAIN, KEEPING THE SELECTION WHEN IT STILL EXISTS
+func (v *view) reload() {
+	bracket, err := v.store.ListPlayoffs(v.season, v.competition)
+	if err != nil {
+		v.status = "could not load the bracket: " + err.Error()
+		return
+	}
+	v.bracket = bracket
+	v.clampSelection()
+}
+
+func (v *view) clampSelection() {
+	v.round = clamp(v.round, len(v.bracket))
+	if len(v.bracket) == 0 {
+		v.series, v.game = 0, 0
+		return
+	}
+	v.series = clamp(v.series, len(v.bracket[v.round]))
+	if len(v.bracket[v.round]) == 0 {
+		v.game = 0
+		return
+	}
+	v.game = clamp(v.game, len(v.bracket[v.round][v.series]))
+}
+
+func clamp(i int, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+// THE SELECTED GAME, NIL WHEN THE BRACKET IS EMPTY
+func (v *view) selected() *models.PlayoffsModel {
+	if len(v.bracket) == 0 || len(v.bracket[v.round]) == 0 || len(v.bracket[v.round][v.series]) == 0 {
+		return nil
+	}
+	return &v.bracket[v.round][v.series][v.game]
+}
+
+// HANDLES ONE KEY AND REPORTS WHETHER THE VIEWER SHOULD EXIT
+func (v *view) handleKey(k string) bool {
+	if k == keyCtrlC {
+		return true
+	}
+	if v.pending != nil {
+		action := v.pending
+		v.pending = nil
+		if k != "y" {
+			v.status = "cancelled"
+			return false
+		}
+		v.status = ""
+		if err := action.run(); err != nil {
+			v.status = describeError(err)
+		}
+		v.reload()
+		return false
+	}
+
+	v.status = ""
+	switch k {
+	case "q":
+		return true
+	case keyLeft, "h":
+		v.moveRound(-1)
+	case keyRight, "l":
+		v.moveRound(1)
+	case keyUp, "k":
+		v.series--
+		v.game = 0
+	case keyDown, "j":
+		v.series++
+		v.game = 0
+	case keyTab, "]":
+		v.cycleGame(1)
+	case "[":
+		v.cycleGame(-1)
+	case "1":
+		v.confirmWinner(true)
+	case "2":
+		v.confirmWinner(false)
+	case "u":
+		v.confirmRevert()
+	case "r":
+		v.reload()
+		if v.status == "" {
+			v.status = "reloaded"
+		}
+	}
+	v.clampSelection()
+	return false
+}
+
+// MOVES TO THE NEIGHBOUR ROUND, TO THE SERIES THE SELECTED ONE FEEDS OR IS FED BY
+func (v *view) moveRound(delta int) {
+	next := v.round + delta
+	if next < 0 || next >= len(v.bracket) {
+		return
+	}
+	if delta > 0 {
+		v.series /= 2
+	} else {
+		v.series *= 2
+	}
+	v.round = next
+	v.game = 0
+}
+
+func (v *view) cycleGame(delta int) {
+	if len(v.bracket) == 0 || len(v.bracket[v.round]) == 0 {
+		return
+	}
+	n := len(v.bracket[v.round][v.series])
+	if n == 0 {
+		return
+	}
+	v.game = (v.game + delta + n) % n
+}
+
+func (v *view) confirmWinner(home bool) {
+	game := v.selected()
+	if game == nil {
+		return
+	}
+	teamId, teamName := game.AwayTeamId, game.AwayTeamName
+	if home {
+		teamId, teamName = game.HomeTeamId, game.HomeTeamName
+	}
+	if teamId == nil {
+		v.status = "this game has no team in that slot yet"
+		return
+	}
+	selected := *game
+	winner := *teamId
+	v.pending = &pendingAction{
+		prompt: fmt.Sprintf("%s wins game %s? y/n", stringOrDash(teamName), selected.GameRound),
+		run: func() error {
+			req := queries.PlayoffsReqFromModel(selected)
+			req.Winner = winner
+			return v.store.UpdatePlayoffs(selected.PlayoffsId, req)
+		},
+	}
+}
+
+func (v *view) confirmRevert() {
+	game := v.selected()
+	if game == nil {
+		return
+	}
+	if game.Winner == nil || game.FixtureRound == nil {
+		v.status = "this game has no winner to clear"
+		return
+	}
+	selected := *game
+	v.pending = &pendingAction{
+		prompt: fmt.Sprintf("clear the winner of game %s? y/n", selected.GameRound),
+		run: func() error {
+			return v.store.UpdatePlayoffsToNull(selected.PlayoffsId, *selected.FixtureRound, *selected.Winner, v.season, v.competition)
+		},
+	}
+}
+
+func describeError(err error) string {
+	if errors.Is(err, queries.ErrPlayoffsConflict) {
+		return "the game was changed by someone else, the bracket was reloaded"
+	}
+	return "error: " + err.Error()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/internal/bracketfixture"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	bracket  [][][]models.PlayoffsModel
+	updated  []queries.PlayoffsModelReqQuery
+	reverted []uuid.UUID
+	err      error
+}
+
+func (s *fakeStore) ListPlayoffs(season string, competition string) ([][][]models.PlayoffsModel, error) {
+	return s.bracket, nil
+}
+
+func (s *fakeStore) UpdatePlayoffs(playoffsId uuid.UUID, playoffs queries.PlayoffsModelReqQuery) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.updated = append(s.updated, playoffs)
+	return nil
+}
+
+func (s *fakeStore) UpdatePlayoffsToNull(playoffsId uuid.UUID, round int, teamId uuid.UUID, season string, competition string) error {
+	s.reverted = append(s.reverted, playoffsId)
+	return nil
+}
+
+// A SERIES OF THREE GAMES BETWEEN TWO NEW TEAMS, THE HOME TEAM WINNING THE FIRST homeWins GAMES
+func makeSeries(round int, position int, home string, away string, homeWins int) []models.PlayoffsModel {
+	homeTeam, awayTeam := bracketfixture.NewTeam(home, ""), bracketfixture.NewTeam(away, "")
+	winners := make([]*bracketfixture.Team, homeWins)
+	for i := range winners {
+		winners[i] = &homeTeam
+	}
+	return bracketfixture.Series(round, strconv.Itoa(position+1), &homeTeam, &awayTeam, 3, winners...)
+}
+
+// FOUR TEAMS: TWO SEMI FINALS AND A FINAL WITHOUT TEAMS YET
+func fourTeamBracket() [][][]models.PlayoffsModel {
+	return [][][]models.PlayoffsModel{
+		{makeSeries(1, 0, "Lions", "Tigers", 2), makeSeries(1, 1, "Bears", "Wolves", 1)},
+		{{bracketfixture.Game(2, "FINAL", "1", nil, nil, nil)}},
+	}
+}
+
+func TestView_MovesAlongTheTree(t *testing.T) {
+	v := newView(&fakeStore{bracket: fourTeamBracket()}, "2023-2024", "")
+
+	v.handleKey(keyDown)
+	assert.Equal(t, 1, v.series)
+	v.handleKey(keyRight)
+	assert.Equal(t, 1, v.round)
+	assert.Equal(t, 0, v.series)
+	v.handleKey(keyRight)
+	assert.Equal(t, 1, v.round)
+	v.handleKey("h")
+	assert.Equal(t, 0, v.round)
+	assert.Equal(t, 0, v.series)
+
+	v.handleKey(keyTab)
+	v.handleKey(keyTab)
+	v.handleKey(keyTab)
+	assert.Equal(t, 0, v.game)
+	v.handleKey("[")
+	assert.Equal(t, 2, v.game)
+}
+
+func TestView_RecordsWinnerAfterConfirmation(t *testing.T) {
+	s := &fakeStore{bracket: fourTeamBracket()}
+	v := newView(s, "2023-2024", "")
+	v.handleKey(keyDown)
+	v.handleKey(keyTab)
+
+	v.handleKey("2")
+	require.NotNil(t, v.pending)
+	assert.Equal(t, "Wolves wins game 2? y/n", v.pending.prompt)
+	assert.Empty(t, s.updated)
+
+	v.handleKey("y")
+	require.Len(t, s.updated, 1)
+	game := s.bracket[0][1][1]
+	assert.Equal(t, *game.AwayTeamId, s.updated[0].Winner)
+	assert.Equal(t, game.PlayoffsId, s.updated[0].PlayoffsId)
+	assert.Equal(t, 1, s.updated[0].Version)
+	assert.Nil(t, v.pending)
+}
+
+func TestView_CancelAndConflict(t *testing.T) {
+	s := &fakeStore{bracket: fourTeamBracket(), err: queries.ErrPlayoffsConflict}
+	v := newView(s, "2023-2024", "")
+
+	v.handleKey("1")
+	v.handleKey("n")
+	assert.Equal(t, "cancelled", v.status)
+
+	v.handleKey("1")
+	v.handleKey("y")
+	assert.Contains(t, v.status, "changed by someone else")
+
+	v.handleKey(keyRight)
+	v.handleKey("1")
+	assert.Nil(t, v.pending)
+	assert.Contains(t, v.status, "no team in that slot")
+}
+
+func TestView_RevertNeedsAWinner(t *testing.T) {
+	s := &fakeStore{bracket: fourTeamBracket()}
+	v := newView(s, "2023-2024", "")
+
+	v.handleKey("u")
+	v.handleKey("y")
+	require.Len(t, s.reverted, 1)
+	assert.Equal(t, s.bracket[0][0][0].PlayoffsId, s.reverted[0])
+
+	v.handleKey(keyTab)
+	v.handleKey(keyTab)
+	v.handleKey("u")
+	assert.Nil(t, v.pending)
+	assert.Contains(t, v.status, "no winner to clear")
+}
+
+func TestRender_ShowsSeriesScoresAndSelection(t *testing.T) {
+	v := newView(&fakeStore{bracket: fourTeamBracket()}, "2023-2024", "")
+
+	lines := render(v, 80, 16)
+
+	require.Len(t, lines, 16)
+	frame := strings.Join(lines, "\n")
+	assert.Contains(t, frame, "main · season 2023-2024")
+	assert.Contains(t, frame, "> R1 #1")
+	assert.Contains(t, frame, ansiReverse+"H")
+	assert.Contains(t, frame, ansiBold+"Lions")
+	assert.Contains(t, frame, "FINAL")
+	assert.Contains(t, frame, "game 1: Lions vs Tigers, winner Lions (version 1)")
+	assert.Regexp(t, `Bears\s+1`, frame)
+	assert.Regexp(t, `Wolves\s+0`, frame)
+}
+
+func TestRender_ScrollsToTheSelection(t *testing.T) {
+	var round [][]models.PlayoffsModel
+	for i := 0; i < 8; i++ {
+		round = append(round, makeSeries(1, i, "Home", "Away", 0))
+	}
+	v := newView(&fakeStore{bracket: [][][]models.PlayoffsModel{round}}, "2023-2024", "")
+	for i := 0; i < 7; i++ {
+		v.handleKey(keyDown)
+	}
+
+	frame := strings.Join(render(v, 40, 12), "\n")
+
+	assert.Contains(t, frame, "> R1 #8")
+	assert.NotContains(t, frame, "R1 #1 ")
+}
+
+func TestParseKeys(t *testing.T) {
+	assert.Equal(t, []string{keyUp, "j", keyTab, keyLeft, keyCtrlC}, parseKeys([]byte("\x1b[Aj\t\x1b[D\x03")))
+	assert.Equal(t, []string{keyEsc}, parseKeys([]byte{0x1b}))
+}
@@ -0,0 +1,347 @@
+// PACKAGE document DEFINES THE VERSIONED JSON DOCUMENT OF A BRACKET: THE TEAMS, THE ROUNDS, THEIR SERIES AND
+// THE GAMES OF EVERY SERIES, WITH THE SEEDS AND STATUS CLIENTS OTHERWISE DERIVE FROM THE ROWS OF ListPlayoffs
+// THEMSELVES. THE DOCUMENT HOLDS EVERY COLUMN OF THE ROWS, SO Decode AND Games RESTORE THE BRACKET IN A FRESH
+// DATABASE AS IT WAS. schema.json DESCRIBES IT AS A JSON SCHEMA
+package document
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// VERSION OF THE DOCUMENTS THIS PACKAGE WRITES AND READS. IT IS INCREASED WHEN A FIELD CHANGES MEANING OR IS
+// REMOVED; NEW OPTIONAL FIELDS KEEP THE VERSION
+const Version = 1
+
+// ID OF THE JSON SCHEMA, WRITTEN AS THE $schema OF EVERY DOCUMENT
+const SchemaId = "https://github.com/AmHughesAbsalom/GO_CODE_SAMPLE/schemas/bracket-v1.json"
+
+// THE JSON SCHEMA OF THE DOCUMENT
+//
+//go:embed schema.json
+var Schema []byte
+
+// STATUS OF A BRACKET
+const (
+	StatusNotStarted = "not_started"
+	StatusInProgress = "in_progress"
+	StatusComplete   = "complete"
+)
+
+// STATUS OF A SERIES: pending UNTIL BOTH TEAMS ARE KNOWN, open UNTIL A TEAM WON MORE THAN HALF OF ITS GAMES
+const (
+	SeriesPending = "pending"
+	SeriesOpen    = "open"
+	SeriesDecided = "decided"
+)
+
+// RETURNED BY Decode FOR A DOCUMENT THAT CAN NOT BE RESTORED
+var ErrInvalidDocument = errors.New("invalid bracket document")
+
+type Document struct {
+	Schema      string     `json:"$schema"`
+	Version     int        `json:"version"`
+	League      string     `json:"league"`
+	Season      string     `json:"season"`
+	Competition string     `json:"competition"`
+	Status      string     `json:"status"`
+	Champion    *uuid.UUID `json:"champion"`
+	Teams       []Team     `json:"teams"`
+	Rounds      []Round    `json:"rounds"`
+}
+
+// A TEAM OF THE BRACKET. Seed IS ITS LINE IN THE FIRST ROUND: THE SERIES s OF n PLAYS THE SEED s+1 AT HOME
+// AGAINST THE SEED 2n-s, AND TEAMS THAT ONLY APPEAR IN LATER ROUNDS HAVE NONE
+type Team struct {
+	Id      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	LogoURL *string   `json:"logoUrl"`
+	Seed    *int      `json:"seed"`
+}
+
+type Round struct {
+	// THE fixture_round OF THE ROWS. A BRACKET OF TWO TEAMS PLAYS ITS FINAL IN ROUND 0
+	Number int      `json:"number"`
+	Name   string   `json:"name"`
+	Series []Series `json:"series"`
+}
+
+type Series struct {
+	// THE game_count OF THE ROWS: THE NUMBER OF THE SERIES, OR FINAL
+	Label  string     `json:"label"`
+	Final  bool       `json:"final"`
+	BestOf int        `json:"bestOf"`
+	Status string     `json:"status"`
+	Home   Slot       `json:"home"`
+	Away   Slot       `json:"away"`
+	Winner *uuid.UUID `json:"winner"`
+	Games  []Game     `json:"games"`
+}
+
+// A SIDE OF A SERIES. TeamId IS NULL UNTIL THE PREVIOUS ROUND SENDS A TEAM
+type Slot struct {
+	TeamId *uuid.UUID `json:"teamId"`
+	Wins   int        `json:"wins"`
+}
+
+type Game struct {
+	Id     uuid.UUID `json:"id"`
+	Number string    `json:"number"`
+	// THE TEAMS OF THE GAME, THE SAME AS THE SLOTS OF ITS SERIES
+	HomeTeamId      *uuid.UUID `json:"homeTeamId"`
+	AwayTeamId      *uuid.UUID `json:"awayTeamId"`
+	PlayersInHomeId uuid.UUID  `json:"playersInHomeId"`
+	PlayersInAwayId uuid.UUID  `json:"playersInAwayId"`
+	Winner          *uuid.UUID `json:"winner"`
+	Version         int        `json:"version"`
+	ScheduledAt     *time.Time `json:"scheduledAt"`
+}
+
+// BUILDS THE DOCUMENT OF THE BRACKET RETURNED BY ListPlayoffs. AN EMPTY competition IS TAKEN FROM THE GAMES
+func New(league string, season string, competition string, playoffs [][][]models.PlayoffsModel) Document {
+	d := Document{
+		Schema:      SchemaId,
+		Version:     Version,
+		League:      league,
+		Season:      season,
+		Competition: competition,
+		Status:      StatusNotStarted,
+		Teams:       []Team{},
+		Rounds:      []Round{},
+	}
+	teams := map[uuid.UUID]int{}
+	addTeam := func(id *uuid.UUID, name *string, url *string) {
+		if id == nil {
+			return
+		}
+		if _, ok := teams[*id]; ok {
+			return
+		}
+		t := Team{Id: *id, LogoURL: url}
+		if name != nil {
+			t.Name = *name
+		}
+		teams[*id] = len(d.Teams)
+		d.Teams = append(d.Teams, t)
+	}
+	for r, round := range playoffs {
+		if len(round) == 0 {
+			continue
+		}
+		if d.Competition == "" && len(round[0]) > 0 {
+			d.Competition = round[0][0].Competition
+		}
+		rd := Round{Name: "Round " + strconv.Itoa(r+1), Series: []Series{}}
+		if len(round[0]) > 0 && round[0][0].FixtureRound != nil {
+			rd.Number = *round[0][0].FixtureRound
+		}
+		for _, games := range round {
+			s := bracket.Summarize(games)
+			if s.Final {
+				rd.Name = "Final"
+			}
+			series := Series{
+				Final:  s.Final,
+				BestOf: len(games),
+				Status: SeriesPending,
+				Home:   Slot{TeamId: s.Home.Id, Wins: s.Home.Wins},
+				Away:   Slot{TeamId: s.Away.Id, Wins: s.Away.Wins},
+				Games:  []Game{},
+			}
+			if len(games) > 0 && games[0].GameCount != nil {
+				series.Label = *games[0].GameCount
+			}
+			switch {
+			case s.Home.Winner:
+				series.Status, series.Winner = SeriesDecided, s.Home.Id
+			case s.Away.Winner:
+				series.Status, series.Winner = SeriesDecided, s.Away.Id
+			case s.Home.Id != nil && s.Away.Id != nil:
+				series.Status = SeriesOpen
+			}
+			if s.Final && series.Winner != nil {
+				d.Champion = series.Winner
+			}
+			for _, g := range games {
+				addTeam(g.HomeTeamId, g.HomeTeamName, g.HomeTeamURL)
+				addTeam(g.AwayTeamId, g.AwayTeamName, g.AwayTeamURL)
+				if g.Winner != nil {
+					d.Status = StatusInProgress
+				}
+				series.Games = append(series.Games, Game{
+					Id:              g.PlayoffsId,
+					Number:          g.GameRound,
+					HomeTeamId:      g.HomeTeamId,
+					AwayTeamId:      g.AwayTeamId,
+					PlayersInHomeId: g.PlayersInHomeId,
+					PlayersInAwayId: g.PlayersInAwayId,
+					Winner:          g.Winner,
+					Version:         g.Version,
+					ScheduledAt:     utc(g.ScheduledAt),
+				})
+			}
+			rd.Series = append(rd.Series, series)
+		}
+		d.Rounds = append(d.Rounds, rd)
+	}
+	if d.Champion != nil {
+		d.Status = StatusComplete
+	}
+	if len(d.Rounds) > 0 {
+		first := d.Rounds[0].Series
+		for s, series := range first {
+			seed(d.Teams, teams, series.Home.TeamId, s+1)
+			seed(d.Teams, teams, series.Away.TeamId, 2*len(first)-s)
+		}
+	}
+	return d
+}
+
+func seed(teams []Team, index map[uuid.UUID]int, id *uuid.UUID, value int) {
+	if id == nil {
+		return
+	}
+	if i, ok := index[*id]; ok && teams[i].Seed == nil {
+		teams[i].Seed = &value
+	}
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// WRITES THE DOCUMENT AS INDENTED JSON
+func Encode(w io.Writer, d Document) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// READS A DOCUMENT AND CHECKS IT CAN BE RESTORED: A VERSION THIS PACKAGE KNOWS, NO UNKNOWN FIELDS, A SEASON,
+// GAMES WITH UNIQUE IDS WHOSE TEAMS ARE LISTED AND WHOSE WINNER PLAYED THEM. THE DERIVED FIELDS (status, champion,
+// seeds AND THE WINS AND WINNER OF THE SERIES) ARE NOT CHECKED, RESTORING ONLY USES THE GAMES
+func Decode(r io.Reader) (Document, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return Document{}, err
+	}
+	var head struct {
+		Version int `json:"version"`
+	}
+	if errH := json.Unmarshal(raw, &head); errH != nil {
+		return Document{}, fmt.Errorf("%w: %s", ErrInvalidDocument, errH.Error())
+	}
+	if head.Version != Version {
+		return Document{}, fmt.Errorf("%w: version %d is not supported, expected %d", ErrInvalidDocument, head.Version, Version)
+	}
+	var d Document
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if errD := decoder.Decode(&d); errD != nil {
+		return Document{}, fmt.Errorf("%w: %s", ErrInvalidDocument, errD.Error())
+	}
+	if errV := d.validate(); errV != nil {
+		return Document{}, fmt.Errorf("%w: %s", ErrInvalidDocument, errV.Error())
+	}
+	return d, nil
+}
+
+func (d Document) validate() error {
+	if d.Season == "" {
+		return errors.New("the season is missing")
+	}
+	if len(d.Rounds) == 0 {
+		return errors.New("the bracket has no rounds")
+	}
+	teams := map[uuid.UUID]bool{}
+	for _, t := range d.Teams {
+		if teams[t.Id] {
+			return fmt.Errorf("team %s is listed twice", t.Id)
+		}
+		teams[t.Id] = true
+	}
+	games := map[uuid.UUID]bool{}
+	for _, r := range d.Rounds {
+		for _, s := range r.Series {
+			if s.Label == "" {
+				return fmt.Errorf("a series of round %d has no label", r.Number)
+			}
+			if len(s.Games) == 0 {
+				return fmt.Errorf("series %s of round %d has no games", s.Label, r.Number)
+			}
+			for _, g := range s.Games {
+				if g.Id == uuid.Nil || games[g.Id] {
+					return fmt.Errorf("game %s of series %s has no id or a repeated one", g.Number, s.Label)
+				}
+				games[g.Id] = true
+				for _, team := range []*uuid.UUID{g.HomeTeamId, g.AwayTeamId} {
+					if team != nil && !teams[*team] {
+						return fmt.Errorf("team %s of game %s is not listed in teams", team, g.Id)
+					}
+				}
+				if g.Winner != nil && (g.HomeTeamId == nil || *g.Winner != *g.HomeTeamId) && (g.AwayTeamId == nil || *g.Winner != *g.AwayTeamId) {
+					return fmt.Errorf("the winner of game %s does not play it", g.Id)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// THE playoffs ROWS OF THE DOCUMENT, READY FOR ImportPlayoffs. THE LEAGUE IS LEFT TO THE CONNECTION
+func (d Document) Games() []models.PlayoffsModel {
+	teams := map[uuid.UUID]Team{}
+	for _, t := range d.Teams {
+		teams[t.Id] = t
+	}
+	team := func(id *uuid.UUID) (*string, *string) {
+		if id == nil {
+			return nil, nil
+		}
+		t := teams[*id]
+		name := t.Name
+		return &name, t.LogoURL
+	}
+	var rows []models.PlayoffsModel
+	for _, r := range d.Rounds {
+		for _, s := range r.Series {
+			for _, g := range s.Games {
+				round, label := r.Number, s.Label
+				row := models.PlayoffsModel{
+					PlayoffsId:      g.Id,
+					FixtureRound:    &round,
+					GameCount:       &label,
+					GameRound:       g.Number,
+					HomeTeamId:      g.HomeTeamId,
+					PlayersInHomeId: g.PlayersInHomeId,
+					AwayTeamId:      g.AwayTeamId,
+					PlayersInAwayId: g.PlayersInAwayId,
+					Season:          d.Season,
+					Competition:     d.Competition,
+					Winner:          g.Winner,
+					Version:         g.Version,
+					ScheduledAt:     g.ScheduledAt,
+				}
+				row.HomeTeamName, row.HomeTeamURL = team(g.HomeTeamId)
+				row.AwayTeamName, row.AwayTeamURL = team(g.AwayTeamId)
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
@@ -0,0 +1,158 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/internal/bracketfixture"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flatten(playoffs [][][]models.PlayoffsModel) []models.PlayoffsModel {
+	var games []models.PlayoffsModel
+	for _, round := range playoffs {
+		for _, series := range round {
+			games = append(games, series...)
+		}
+	}
+	return games
+}
+
+func TestNew_Fixture(t *testing.T) {
+	d := New("default", "2023-2024", "Playoffs", bracketfixture.SampleBracket(nil))
+
+	var out bytes.Buffer
+	require.NoError(t, Encode(&out, d))
+	expected, err := os.ReadFile("testdata/bracket.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), out.String())
+}
+
+func TestNew_StatusAndSeeds(t *testing.T) {
+	d := New("default", "2023-2024", "Playoffs", bracketfixture.SampleBracket(nil))
+
+	assert.Equal(t, StatusInProgress, d.Status)
+	assert.Nil(t, d.Champion)
+	seeds := map[string]int{}
+	for _, team := range d.Teams {
+		require.NotNil(t, team.Seed)
+		seeds[team.Name] = *team.Seed
+	}
+	assert.Equal(t, map[string]int{"Lions": 1, "Tigers": 2, "Wolves": 3, "Bears": 4}, seeds)
+	require.Len(t, d.Rounds, 2)
+	assert.Equal(t, "Round 1", d.Rounds[0].Name)
+	assert.Equal(t, "Final", d.Rounds[1].Name)
+	assert.Equal(t, SeriesDecided, d.Rounds[0].Series[0].Status)
+	assert.Equal(t, bracketfixture.Lions.Id, *d.Rounds[0].Series[0].Winner)
+	assert.Equal(t, SeriesDecided, d.Rounds[0].Series[1].Status)
+	assert.Equal(t, SeriesOpen, d.Rounds[1].Series[0].Status)
+
+	playoffs := bracketfixture.SampleBracket(nil)
+	playoffs[1][0][0].AwayTeamId, playoffs[1][0][0].AwayTeamName = nil, nil
+	d = New("default", "2023-2024", "Playoffs", playoffs)
+	assert.Equal(t, SeriesPending, d.Rounds[1].Series[0].Status)
+
+	d = New("default", "2023-2024", "Playoffs", bracketfixture.SampleBracket(&bracketfixture.Wolves))
+	assert.Equal(t, StatusComplete, d.Status)
+	assert.Equal(t, bracketfixture.Wolves.Id, *d.Champion)
+
+	d = New("default", "2023-2024", "Playoffs", nil)
+	assert.Equal(t, StatusNotStarted, d.Status)
+	assert.Empty(t, d.Rounds)
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	playoffs := bracketfixture.SampleBracket(nil)
+	var out bytes.Buffer
+	require.NoError(t, Encode(&out, New("default", "2023-2024", "Playoffs", playoffs)))
+
+	d, err := Decode(&out)
+	require.NoError(t, err)
+
+	assert.Equal(t, flatten(playoffs), d.Games())
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	valid, err := os.ReadFile("testdata/bracket.json")
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		replace [2]string
+		message string
+	}{
+		{"version", [2]string{`"version": 1`, `"version": 2`}, "version 2 is not supported"},
+		{"unknown field", [2]string{`"status": "in_progress"`, `"status": "in_progress", "stage": 1`}, "unknown field"},
+		{"no season", [2]string{`"season": "2023-2024"`, `"season": ""`}, "season is missing"},
+		{"unknown team", [2]string{`"name": "Bears"`, `"name": "Bears", "id": "` + uuid.Nil.String() + `"`}, "not listed in teams"},
+		{"repeated game", [2]string{`"` + bracketfixture.Game(1, "1", "2", nil, nil, nil).PlayoffsId.String() + `"`, `"` + bracketfixture.Game(1, "1", "1", nil, nil, nil).PlayoffsId.String() + `"`}, "repeated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := strings.Replace(string(valid), tt.replace[0], tt.replace[1], 1)
+			require.NotEqual(t, string(valid), doc)
+
+			_, err := Decode(strings.NewReader(doc))
+
+			assert.ErrorIs(t, err, ErrInvalidDocument)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+
+	d := New("default", "2023-2024", "Playoffs", bracketfixture.SampleBracket(nil))
+	d.Rounds[0].Series[0].Games[0].Winner = &bracketfixture.Tigers.Id
+	var out bytes.Buffer
+	require.NoError(t, Encode(&out, d))
+	_, err = Decode(&out)
+	assert.ErrorContains(t, err, "does not play it")
+}
+
+// EVERY OBJECT OF THE SCHEMA LISTS THE FIELDS OF ITS STRUCT, SO THE SCHEMA CAN NOT DRIFT FROM THE ENCODER
+func TestSchema_MatchesTypes(t *testing.T) {
+	var schema struct {
+		Id         string         `json:"$id"`
+		Required   []string       `json:"required"`
+		Properties map[string]any `json:"properties"`
+		Defs       map[string]struct {
+			Required   []string       `json:"required"`
+			Properties map[string]any `json:"properties"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(Schema, &schema))
+	assert.Equal(t, SchemaId, schema.Id)
+
+	fields := func(v any) []string {
+		var names []string
+		typ := reflect.TypeOf(v)
+		for i := 0; i < typ.NumField(); i++ {
+			names = append(names, strings.Split(typ.Field(i).Tag.Get("json"), ",")[0])
+		}
+		sort.Strings(names)
+		return names
+	}
+	keys := func(m map[string]any) []string {
+		var names []string
+		for k := range m {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		return names
+	}
+	assert.Equal(t, fields(Document{}), keys(schema.Properties))
+	for name, v := range map[string]any{"team": Team{}, "round": Round{}, "series": Series{}, "slot": Slot{}, "game": Game{}} {
+		def, ok := schema.Defs[name]
+		require.True(t, ok, name)
+		assert.Equal(t, fields(v), keys(def.Properties), name)
+		required := append([]string{}, def.Required...)
+		sort.Strings(required)
+		assert.Equal(t, fields(v), required, name)
+	}
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// KINDS OF BRACKET DELTA
+const (
+	DeltaWinnerSet     = "winner_set"
+	DeltaWinnerCleared = "winner_cleared"
+	DeltaTeamAdvanced  = "team_advanced"
+	DeltaSlotCleared   = "slot_cleared"
+)
+
+// SIDES OF A GAME A TEAM CAN BE ADVANCED TO OR CLEARED FROM
+const (
+	SlotHome = "home"
+	SlotAway = "away"
+)
+
+// ONE VISIBLE CHANGE OF A GAME, SMALL ENOUGH FOR A CLIENT TO PATCH ITS BRACKET WITHOUT RELOADING IT
+type Delta struct {
+	Kind         string     `json:"kind"`
+	PlayoffsId   uuid.UUID  `json:"playoffsId"`
+	FixtureRound *int       `json:"fixtureRound"`
+	GameCount    *string    `json:"gameCount"`
+	GameRound    string     `json:"gameRound"`
+	Slot         string     `json:"slot,omitempty"`
+	TeamId       *uuid.UUID `json:"teamId"`
+	TeamName     *string    `json:"teamName"`
+	TeamURL      *string    `json:"teamURL"`
+	Version      int        `json:"version"`
+}
+
+func equalId(a *uuid.UUID, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// TURNS THE GAMES OF A RESULT ENTRY INTO DELTAS: THE WINNER SET OR CLEARED AND THE TEAMS ADVANCED TO
+// OR CLEARED FROM EACH SLOT. A SLOT WHOSE TEAM IS REPLACED BY ANOTHER ONE IS REPORTED AS ADVANCED
+func Deltas(change models.BracketChangeModel) []Delta {
+	var deltas []Delta
+	for _, g := range change.Games {
+		game := Delta{
+			PlayoffsId:   g.After.PlayoffsId,
+			FixtureRound: g.After.FixtureRound,
+			GameCount:    g.After.GameCount,
+			GameRound:    g.After.GameRound,
+			Version:      g.After.Version,
+		}
+		if !equalId(g.Before.Winner, g.After.Winner) {
+			d := game
+			d.TeamId = g.After.Winner
+			d.Kind = DeltaWinnerSet
+			if g.After.Winner == nil {
+				d.Kind = DeltaWinnerCleared
+				d.TeamId = g.Before.Winner
+			}
+			deltas = append(deltas, d)
+		}
+		if !equalId(g.Before.HomeTeamId, g.After.HomeTeamId) {
+			deltas = append(deltas, slotDelta(game, SlotHome, g.Before.HomeTeamId, g.After.HomeTeamId, g.After.HomeTeamName, g.After.HomeTeamURL))
+		}
+		if !equalId(g.Before.AwayTeamId, g.After.AwayTeamId) {
+			deltas = append(deltas, slotDelta(game, SlotAway, g.Before.AwayTeamId, g.After.AwayTeamId, g.After.AwayTeamName, g.After.AwayTeamURL))
+		}
+	}
+	return deltas
+}
+
+func slotDelta(game Delta, slot string, before *uuid.UUID, after *uuid.UUID, name *string, url *string) Delta {
+	d := game
+	d.Slot = slot
+	if after == nil {
+		d.Kind = DeltaSlotCleared
+		d.TeamId = before
+		return d
+	}
+	d.Kind = DeltaTeamAdvanced
+	d.TeamId = after
+	d.TeamName = name
+	d.TeamURL = url
+	return d
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"testing"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A FIRST ROUND WIN THAT ADVANCES THE WINNER TO THE HOME SLOT OF THE NEXT ROUND
+func advanceChange(league string, season string) models.BracketChangeModel {
+	teamID := uuid.New()
+	teamName := "Lions"
+	game := models.PlayoffsModel{PlayoffsId: uuid.New(), HomeTeamId: &teamID, HomeTeamName: &teamName}
+	gameWon := game
+	gameWon.Winner = &teamID
+	gameWon.Version = 1
+	next := models.PlayoffsModel{PlayoffsId: uuid.New()}
+	nextFilled := next
+	nextFilled.HomeTeamId = &teamID
+	nextFilled.HomeTeamName = &teamName
+	return models.BracketChangeModel{
+		League:      league,
+		Season:      season,
+		Competition: "main",
+		Operation:   "UPDATE_PLAYOFFS",
+		PlayoffsId:  &game.PlayoffsId,
+		Games:       []models.GameChangeModel{{Before: game, After: gameWon}, {Before: next, After: nextFilled}},
+	}
+}
+
+func TestDeltas_WinnerSetAndTeamAdvanced(t *testing.T) {
+	change := advanceChange("default", "2023-2024")
+
+	deltas := Deltas(change)
+
+	require.Len(t, deltas, 2)
+	assert.Equal(t, DeltaWinnerSet, deltas[0].Kind)
+	assert.Equal(t, 1, deltas[0].Version)
+	assert.Equal(t, DeltaTeamAdvanced, deltas[1].Kind)
+	assert.Equal(t, SlotHome, deltas[1].Slot)
+	assert.Equal(t, "Lions", *deltas[1].TeamName)
+}
+
+func TestDeltas_RevertClearsWinnerAndSlot(t *testing.T) {
+	change := advanceChange("default", "2023-2024")
+	for i, g := range change.Games {
+		change.Games[i] = models.GameChangeModel{Before: g.After, After: g.Before}
+	}
+
+	deltas := Deltas(change)
+
+	require.Len(t, deltas, 2)
+	assert.Equal(t, DeltaWinnerCleared, deltas[0].Kind)
+	assert.Equal(t, DeltaSlotCleared, deltas[1].Kind)
+	assert.Equal(t, change.Games[1].Before.HomeTeamId, deltas[1].TeamId)
+}
+
+func TestHub_PublishesToMatchingSubscribers(t *testing.T) {
+	hub := NewHub(16)
+	season := hub.Subscribe(Filter{League: "default", Season: "2023-2024"}, 0)
+	other := hub.Subscribe(Filter{League: "north"}, 0)
+	defer season.Close()
+	defer other.Close()
+
+	hub.Publish(advanceChange("default", "2023-2024"))
+
+	e := <-season.C
+	assert.Len(t, e.Deltas, 2)
+	assert.Empty(t, other.C)
+}
+
+func TestHub_ResumesFromLastEventId(t *testing.T) {
+	hub := NewHub(16)
+	first := hub.Subscribe(Filter{League: "default"}, 0)
+	hub.Publish(advanceChange("default", "2023-2024"))
+	hub.Publish(advanceChange("default", "2024-2025"))
+	seen := <-first.C
+	first.Close()
+
+	resumed := hub.Subscribe(Filter{League: "default"}, seen.Id)
+	defer resumed.Close()
+
+	assert.False(t, resumed.Reload)
+	require.Len(t, resumed.Replay, 1)
+	assert.Equal(t, seen.Id+1, resumed.Replay[0].Id)
+	assert.Equal(t, "2024-2025", resumed.Replay[0].Season)
+}
+
+func TestHub_ReloadWhenHistoryWasDropped(t *testing.T) {
+	hub := NewHub(1)
+	first := hub.Subscribe(Filter{League: "default"}, 0)
+	hub.Publish(advanceChange("default", "2023-2024"))
+	seen := <-first.C
+	first.Close()
+	hub.Publish(advanceChange("default", "2023-2024"))
+	hub.Publish(advanceChange("default", "2023-2024"))
+
+	resumed := hub.Subscribe(Filter{League: "default"}, seen.Id)
+	defer resumed.Close()
+	fromPreviousProcess := hub.Subscribe(Filter{League: "default"}, 1)
+	defer fromPreviousProcess.Close()
+
+	assert.True(t, resumed.Reload)
+	assert.True(t, fromPreviousProcess.Reload)
+}
+
+func TestHub_DisconnectsSlowSubscriber(t *testing.T) {
+	hub := NewHub(16)
+	slow := hub.Subscribe(Filter{League: "default"}, 0)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(advanceChange("default", "2023-2024"))
+	}
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+	slow.Close()
+}
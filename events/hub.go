@@ -0,0 +1,147 @@
+// PACKAGE events FANS OUT COMMITTED BRACKET CHANGES TO IN-PROCESS SUBSCRIBERS (THE SSE ENDPOINT OF THE SERVER).
+// EVERY EVENT HAS AN INCREASING ID AND THE HUB KEEPS THE LAST EVENTS SO A CLIENT THAT RECONNECTS WITH THE
+// ID OF THE LAST EVENT IT SAW RECEIVES WHAT IT MISSED
+package events
+
+import (
+	"sync"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// NUMBER OF EVENTS A SUBSCRIBER CAN FALL BEHIND BEFORE IT IS DISCONNECTED. IT CAN RECONNECT AND RESUME
+// FROM THE HISTORY
+const subscriberBuffer = 64
+
+type Event struct {
+	Id          uint64     `json:"id"`
+	Operation   string     `json:"operation"`
+	League      string     `json:"league"`
+	Season      string     `json:"season"`
+	Competition string     `json:"competition"`
+	Actor       string     `json:"actor"`
+	PlayoffsId  *uuid.UUID `json:"playoffsId"`
+	Deltas      []Delta    `json:"deltas"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// SELECTS THE EVENTS OF A SUBSCRIPTION. EMPTY Season OR Competition MATCH EVERY SEASON OR COMPETITION OF THE LEAGUE
+type Filter struct {
+	League      string
+	Season      string
+	Competition string
+}
+
+func (f Filter) match(e Event) bool {
+	return e.League == f.League &&
+		(f.Season == "" || e.Season == f.Season) &&
+		(f.Competition == "" || e.Competition == f.Competition)
+}
+
+type Subscription struct {
+	// EVENTS PUBLISHED BEFORE THE SUBSCRIPTION THAT CAME AFTER THE REQUESTED ID, TO BE SENT BEFORE C
+	Replay []Event
+	// TRUE WHEN THE REQUESTED ID IS NO LONGER IN THE HISTORY (TOO OLD OR FROM A PREVIOUS PROCESS),
+	// SO THE CLIENT MISSED EVENTS AND HAS TO RELOAD THE WHOLE BRACKET
+	Reload bool
+	// CLOSED WHEN THE SUBSCRIBER FALLS TOO FAR BEHIND OR THE SUBSCRIPTION IS CLOSED
+	C <-chan Event
+
+	hub *Hub
+	ch  chan Event
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subscribers[s]; ok {
+		delete(s.hub.subscribers, s)
+		close(s.ch)
+	}
+}
+
+type Hub struct {
+	mu          sync.Mutex
+	nextId      uint64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]Filter
+}
+
+// CREATES A HUB KEEPING THE LAST historySize EVENTS. IDS START FROM THE CREATION TIME SO THE IDS OF A
+// RESTARTED PROCESS ARE ALWAYS GREATER THAN THE ONES CLIENTS KEPT FROM THE PREVIOUS ONE
+func NewHub(historySize int) *Hub {
+	return &Hub{
+		nextId:      uint64(time.Now().UnixMicro()),
+		historySize: historySize,
+		subscribers: map[*Subscription]Filter{},
+	}
+}
+
+// PUBLISHES A COMMITTED RESULT ENTRY. IT HAS THE SIGNATURE OF PlayoffsDBConnection.OnChange
+// AND NEVER BLOCKS ON SLOW SUBSCRIBERS
+func (h *Hub) Publish(change models.BracketChangeModel) {
+	deltas := Deltas(change)
+	if len(deltas) == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	e := Event{
+		Id:          h.nextId,
+		Operation:   change.Operation,
+		League:      change.League,
+		Season:      change.Season,
+		Competition: change.Competition,
+		Actor:       change.Actor,
+		PlayoffsId:  change.PlayoffsId,
+		Deltas:      deltas,
+		CreatedAt:   time.Now(),
+	}
+	h.nextId++
+	h.history = append(h.history, e)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+	for s, f := range h.subscribers {
+		if !f.match(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			delete(h.subscribers, s)
+			close(s.ch)
+		}
+	}
+}
+
+// SUBSCRIBES TO THE EVENTS MATCHING THE FILTER. lastEventId IS THE ID OF THE LAST EVENT THE CLIENT RECEIVED,
+// OR 0 FOR A NEW CLIENT WHICH ONLY RECEIVES THE EVENTS PUBLISHED FROM NOW ON
+func (h *Hub) Subscribe(filter Filter, lastEventId uint64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	s := &Subscription{C: ch, hub: h, ch: ch}
+	if lastEventId != 0 {
+		oldest := h.nextId
+		if len(h.history) > 0 {
+			oldest = h.history[0].Id
+		}
+		if lastEventId+1 < oldest || lastEventId >= h.nextId {
+			s.Reload = true
+		}
+		for _, e := range h.history {
+			if e.Id > lastEventId && filter.match(e) {
+				s.Replay = append(s.Replay, e)
+			}
+		}
+	}
+	h.subscribers[s] = filter
+	return s
+}
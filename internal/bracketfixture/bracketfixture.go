@@ -0,0 +1,94 @@
+// PACKAGE bracketfixture BUILDS THE PLAYOFFS BRACKETS THE TESTS OF THE PACKAGES READING ListPlayoffs RESULTS RUN ON.
+// GAME IDS ARE DERIVED FROM THE ROUND, SERIES AND GAME SO THE EXPORTED FIXTURES ARE STABLE ACROSS RUNS
+package bracketfixture
+
+import (
+	"strconv"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// THE SEASON OF EVERY GAME BUILT HERE
+const Season = "2023-2024"
+
+type Team struct {
+	Id   uuid.UUID
+	Name string
+	Logo string
+}
+
+// THE TEAMS OF SampleBracket, WITH FIXED IDS
+var (
+	Lions  = Team{Id: uuid.MustParse("8c1d6f0e-5d1c-4a57-9a0e-3f2c1b7d9e01"), Name: "Lions", Logo: "https://example.com/lions.png"}
+	Bears  = Team{Id: uuid.MustParse("8c1d6f0e-5d1c-4a57-9a0e-3f2c1b7d9e02"), Name: "Bears"}
+	Tigers = Team{Id: uuid.MustParse("8c1d6f0e-5d1c-4a57-9a0e-3f2c1b7d9e03"), Name: "Tigers"}
+	Wolves = Team{Id: uuid.MustParse("8c1d6f0e-5d1c-4a57-9a0e-3f2c1b7d9e04"), Name: "Wolves"}
+)
+
+// A TEAM WITH A RANDOM ID, logo MAY BE EMPTY
+func NewTeam(name string, logo string) Team {
+	return Team{Id: uuid.New(), Name: name, Logo: logo}
+}
+
+// GAME gameRound OF SERIES count OF round. A nil TEAM IS A SLOT NOT FILLED YET AND A nil WINNER A GAME NOT PLAYED YET
+func Game(round int, count string, gameRound string, home *Team, away *Team, winner *Team) models.PlayoffsModel {
+	key := strconv.Itoa(round) + count + gameRound
+	g := models.PlayoffsModel{
+		PlayoffsId:      uuid.NewSHA1(uuid.NameSpaceOID, []byte(key)),
+		FixtureRound:    &round,
+		GameCount:       &count,
+		GameRound:       gameRound,
+		Season:          Season,
+		Competition:     "Playoffs",
+		PlayersInHomeId: uuid.NewSHA1(uuid.NameSpaceOID, []byte(key+"home")),
+		PlayersInAwayId: uuid.NewSHA1(uuid.NameSpaceOID, []byte(key+"away")),
+		Version:         1,
+	}
+	if home != nil {
+		g.HomeTeamId, g.HomeTeamName = &home.Id, &home.Name
+		if home.Logo != "" {
+			g.HomeTeamURL = &home.Logo
+		}
+	}
+	if away != nil {
+		g.AwayTeamId, g.AwayTeamName = &away.Id, &away.Name
+		if away.Logo != "" {
+			g.AwayTeamURL = &away.Logo
+		}
+	}
+	if winner != nil {
+		g.Winner = &winner.Id
+	}
+	return g
+}
+
+// A SERIES OF games GAMES WHERE winners[i] WON GAME i, nil FOR A GAME NOT PLAYED YET
+func Series(round int, count string, home *Team, away *Team, games int, winners ...*Team) []models.PlayoffsModel {
+	var s []models.PlayoffsModel
+	for i := 0; i < games; i++ {
+		var winner *Team
+		if i < len(winners) {
+			winner = winners[i]
+		}
+		s = append(s, Game(round, count, strconv.Itoa(i+1), home, away, winner))
+	}
+	return s
+}
+
+// FOUR TEAMS: LIONS BEAT BEARS 2-1, WOLVES BEAT TIGERS 2-0 AND finalWinner, WHEN NOT nil, WON THE FINAL
+// SCHEDULED ON 2024-05-02
+func SampleBracket(finalWinner *Team) [][][]models.PlayoffsModel {
+	final := Game(2, "FINAL", "1", &Lions, &Wolves, finalWinner)
+	at := time.Date(2024, 5, 2, 23, 0, 0, 0, time.UTC)
+	final.ScheduledAt = &at
+	return [][][]models.PlayoffsModel{
+		{
+			Series(1, "1", &Lions, &Bears, 3, &Lions, &Bears, &Lions),
+			Series(1, "2", &Tigers, &Wolves, 3, &Wolves, &Wolves),
+		},
+		{{final}},
+	}
+}
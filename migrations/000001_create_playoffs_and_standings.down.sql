@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS playoffs;
+DROP TABLE IF EXISTS standings;
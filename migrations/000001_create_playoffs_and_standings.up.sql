@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS standings (
+    standings_id UUID PRIMARY KEY,
+    team_id UUID,
+    position INT NOT NULL DEFAULT 0,
+    team_name TEXT NOT NULL,
+    acronym TEXT NOT NULL DEFAULT '',
+    team_pic_url TEXT,
+    gp INT NOT NULL DEFAULT 0,
+    w INT NOT NULL DEFAULT 0,
+    l INT NOT NULL DEFAULT 0,
+    win_percentage DOUBLE PRECISION NOT NULL DEFAULT 0,
+    gf INT NOT NULL DEFAULT 0,
+    pts INT NOT NULL DEFAULT 0,
+    conference TEXT NOT NULL,
+    season TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS playoffs (
+    playoffs_id UUID PRIMARY KEY,
+    fixture_round INT,
+    game_count TEXT,
+    game_round TEXT NOT NULL,
+    home_team_id UUID,
+    home_team_name TEXT,
+    home_team_url TEXT,
+    players_in_home_id UUID NOT NULL,
+    away_team_id UUID,
+    away_team_name TEXT,
+    away_team_url TEXT,
+    players_in_away_id UUID NOT NULL,
+    season TEXT NOT NULL,
+    winner UUID
+);
+
+CREATE INDEX IF NOT EXISTS playoffs_season_round_idx ON playoffs (season, fixture_round, game_count);
+CREATE INDEX IF NOT EXISTS standings_season_conference_idx ON standings (season, conference);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS playoffs_audit_no_update ON playoffs_audit;
+DROP FUNCTION IF EXISTS playoffs_audit_append_only();
+DROP TABLE IF EXISTS playoffs_audit;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS playoffs_audit (
+    audit_id UUID PRIMARY KEY,
+    season TEXT NOT NULL,
+    playoffs_id UUID,
+    operation TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    before_value JSONB NOT NULL DEFAULT 'null',
+    after_value JSONB NOT NULL DEFAULT 'null',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS playoffs_audit_season_idx ON playoffs_audit (season, created_at);
+CREATE INDEX IF NOT EXISTS playoffs_audit_playoffs_id_idx ON playoffs_audit (playoffs_id, created_at);
+
+-- THE AUDIT LOG IS APPEND ONLY
+CREATE OR REPLACE FUNCTION playoffs_audit_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'playoffs_audit is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER playoffs_audit_no_update
+BEFORE UPDATE OR DELETE ON playoffs_audit
+FOR EACH ROW EXECUTE FUNCTION playoffs_audit_append_only();
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS playoffs_changes (
+    change_seq BIGSERIAL PRIMARY KEY,
+    batch_id UUID NOT NULL,
+    season TEXT NOT NULL,
+    playoffs_id UUID NOT NULL,
+    operation TEXT NOT NULL,
+    before_value JSONB NOT NULL,
+    after_value JSONB NOT NULL,
+    undone BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS playoffs_changes_season_idx ON playoffs_changes (season, undone, change_seq);
+CREATE INDEX IF NOT EXISTS playoffs_changes_batch_idx ON playoffs_changes (batch_id);
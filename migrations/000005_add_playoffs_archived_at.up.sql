@@ -0,0 +1 @@
+ALTER TABLE playoffs ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
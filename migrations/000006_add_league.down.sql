@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS playoffs_changes_league_season_idx;
+DROP INDEX IF EXISTS playoffs_audit_league_season_idx;
+DROP INDEX IF EXISTS standings_league_season_conference_idx;
+DROP INDEX IF EXISTS playoffs_league_season_round_idx;
+CREATE INDEX IF NOT EXISTS playoffs_season_round_idx ON playoffs (season, fixture_round, game_count);
+CREATE INDEX IF NOT EXISTS standings_season_conference_idx ON standings (season, conference);
+CREATE INDEX IF NOT EXISTS playoffs_audit_season_idx ON playoffs_audit (season, created_at);
+CREATE INDEX IF NOT EXISTS playoffs_changes_season_idx ON playoffs_changes (season, undone, change_seq);
+
+ALTER TABLE playoffs_changes DROP COLUMN IF EXISTS league;
+ALTER TABLE playoffs_audit DROP COLUMN IF EXISTS league;
+ALTER TABLE playoffs DROP COLUMN IF EXISTS league;
+ALTER TABLE standings DROP COLUMN IF EXISTS league;
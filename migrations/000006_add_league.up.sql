@@ -0,0 +1,13 @@
+ALTER TABLE standings ADD COLUMN IF NOT EXISTS league TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE playoffs ADD COLUMN IF NOT EXISTS league TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE playoffs_audit ADD COLUMN IF NOT EXISTS league TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE playoffs_changes ADD COLUMN IF NOT EXISTS league TEXT NOT NULL DEFAULT 'default';
+
+DROP INDEX IF EXISTS playoffs_season_round_idx;
+DROP INDEX IF EXISTS standings_season_conference_idx;
+DROP INDEX IF EXISTS playoffs_audit_season_idx;
+DROP INDEX IF EXISTS playoffs_changes_season_idx;
+CREATE INDEX IF NOT EXISTS playoffs_league_season_round_idx ON playoffs (league, season, fixture_round, game_count);
+CREATE INDEX IF NOT EXISTS standings_league_season_conference_idx ON standings (league, season, conference);
+CREATE INDEX IF NOT EXISTS playoffs_audit_league_season_idx ON playoffs_audit (league, season, created_at);
+CREATE INDEX IF NOT EXISTS playoffs_changes_league_season_idx ON playoffs_changes (league, season, undone, change_seq);
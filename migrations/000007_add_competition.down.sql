@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS playoffs_changes_bracket_idx;
+DROP INDEX IF EXISTS playoffs_bracket_round_idx;
+CREATE INDEX IF NOT EXISTS playoffs_league_season_round_idx ON playoffs (league, season, fixture_round, game_count);
+CREATE INDEX IF NOT EXISTS playoffs_changes_league_season_idx ON playoffs_changes (league, season, undone, change_seq);
+
+ALTER TABLE playoffs_changes DROP COLUMN IF EXISTS competition;
+ALTER TABLE playoffs_audit DROP COLUMN IF EXISTS competition;
+ALTER TABLE playoffs DROP COLUMN IF EXISTS competition;
@@ -0,0 +1,8 @@
+ALTER TABLE playoffs ADD COLUMN IF NOT EXISTS competition TEXT NOT NULL DEFAULT 'main';
+ALTER TABLE playoffs_audit ADD COLUMN IF NOT EXISTS competition TEXT NOT NULL DEFAULT 'main';
+ALTER TABLE playoffs_changes ADD COLUMN IF NOT EXISTS competition TEXT NOT NULL DEFAULT 'main';
+
+DROP INDEX IF EXISTS playoffs_league_season_round_idx;
+DROP INDEX IF EXISTS playoffs_changes_league_season_idx;
+CREATE INDEX IF NOT EXISTS playoffs_bracket_round_idx ON playoffs (league, season, competition, fixture_round, game_count);
+CREATE INDEX IF NOT EXISTS playoffs_changes_bracket_idx ON playoffs_changes (league, season, competition, undone, change_seq);
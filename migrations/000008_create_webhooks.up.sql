@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+    webhook_id UUID PRIMARY KEY,
+    league TEXT NOT NULL DEFAULT 'default',
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_league_idx ON webhooks (league);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    delivery_id UUID PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES webhooks (webhook_id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempt INT NOT NULL,
+    status_code INT,
+    error TEXT,
+    delivered BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at);
@@ -0,0 +1,17 @@
+-- EVERY COMMITTED CHANGE OF A BRACKET, WRITTEN IN THE SAME TRANSACTION AS THE CHANGE AND DRAINED BY outbox.Relay
+CREATE TABLE IF NOT EXISTS outbox (
+    outbox_id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE,
+    league TEXT NOT NULL,
+    season TEXT NOT NULL,
+    competition TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    published_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (available_at, outbox_id) WHERE published_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE playoffs DROP COLUMN IF EXISTS scheduled_at;
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+type AuditModel struct {
+	AuditId     uuid.UUID      `db:"audit_id" json:"auditId"`
+	Season      string         `db:"season" json:"season"`
+	League      string         `db:"league" json:"league"`
+	Competition string         `db:"competition" json:"competition"`
+	PlayoffsId  *uuid.UUID     `db:"playoffs_id" json:"playoffsId"`
+	Operation   string         `db:"operation" json:"operation"`
+	Actor       string         `db:"actor" json:"actor"`
+	Before      types.JSONText `db:"before_value" json:"before"`
+	After       types.JSONText `db:"after_value" json:"after"`
+	CreatedAt   time.Time      `db:"created_at" json:"createdAt"`
+}
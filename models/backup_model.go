@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// EVERY ROW OF A SEASON IN ONE LEAGUE: ITS STANDINGS, THE GAMES OF ALL ITS COMPETITIONS (ARCHIVED ONES TOO),
+// ITS AUDIT LOG AND THE CHANGE LOG UNDO AND REDO REPLAY
+type SeasonBackupModel struct {
+	League    string           `json:"league"`
+	Season    string           `json:"season"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Standings []StandingsModel `json:"standings"`
+	Playoffs  []PlayoffsModel  `json:"playoffs"`
+	Audit     []AuditModel     `json:"audit"`
+	Changes   []ChangeModel    `json:"changes"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "github.com/google/uuid"
+
+// ONE GAME TOUCHED BY A RESULT ENTRY, AS IT WAS BEFORE AND AFTER THE ENTRY
+type GameChangeModel struct {
+	Before PlayoffsModel `json:"before"`
+	After  PlayoffsModel `json:"after"`
+}
+
+// A COMMITTED CHANGE OF A BRACKET WITH EVERY GAME IT CHANGED, INCLUDING THE NEXT ROUND SLOTS A RESULT ENTRY
+// FILLED OR CLEARED. EventId IDENTIFIES THE CHANGE IN THE OUTBOX SO CONSUMERS CAN DROP REDELIVERIES
+type BracketChangeModel struct {
+	EventId     uuid.UUID         `json:"eventId"`
+	League      string            `json:"league"`
+	Season      string            `json:"season"`
+	Competition string            `json:"competition"`
+	Operation   string            `json:"operation"`
+	Actor       string            `json:"actor"`
+	PlayoffsId  *uuid.UUID        `json:"playoffsId"`
+	Games       []GameChangeModel `json:"games"`
+}
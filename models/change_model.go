@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+type ChangeModel struct {
+	ChangeSeq   int64          `db:"change_seq" json:"changeSeq"`
+	BatchId     uuid.UUID      `db:"batch_id" json:"batchId"`
+	Season      string         `db:"season" json:"season"`
+	League      string         `db:"league" json:"league"`
+	Competition string         `db:"competition" json:"competition"`
+	PlayoffsId  uuid.UUID      `db:"playoffs_id" json:"playoffsId"`
+	Operation   string         `db:"operation" json:"operation"`
+	Before      types.JSONText `db:"before_value" json:"before"`
+	After       types.JSONText `db:"after_value" json:"after"`
+	Undone      bool           `db:"undone" json:"undone"`
+	CreatedAt   time.Time      `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "github.com/google/uuid"
+
+// HOW THE BRACKET OF A COMPETITION WAS GENERATED: THE ARGUMENTS GIVEN TO CreatePlayoffs AND THE SERIES AND
+// SEEDING IT PRODUCED
+type PlayoffsFormatModel struct {
+	Season      string   `json:"season"`
+	Competition string   `json:"competition"`
+	Conferences []string `json:"conferences"`
+	Limit       int      `json:"limit"`
+	// GAMES OF A SERIES BEFORE THE FINAL, AND OF THE FINAL
+	SeriesGames int    `json:"seriesGames"`
+	FinalGames  int    `json:"finalGames"`
+	Seeding     string `json:"seeding"`
+}
+
+// A TEAM OF THE FIRST ROUND OF A BRACKET. Seed IS ITS LINE IN THAT ROUND: SERIES s OF n PLAYS SEED s+1 AT HOME
+// AGAINST SEED 2n-s
+type QualifiedTeamModel struct {
+	TeamId   *uuid.UUID `json:"teamId"`
+	TeamName string     `json:"teamName"`
+	Seed     int        `json:"seed"`
+	// SEED OF THE TEAM IN THE BRACKET IT IS COMPARED WITH, NIL WHEN IT DID NOT QUALIFY THERE
+	PreviousSeed *int `json:"previousSeed"`
+}
+
+// THE BRACKET CLONED FROM THE FORMAT OF ANOTHER SEASON AND HOW ITS QUALIFIED TEAMS DIFFER
+type CloneReportModel struct {
+	FromSeason  string   `json:"fromSeason"`
+	Season      string   `json:"season"`
+	Competition string   `json:"competition"`
+	Conferences []string `json:"conferences"`
+	Limit       int      `json:"limit"`
+	// TEAMS QUALIFIED IN BOTH SEASONS, ONLY IN THE NEW ONE AND ONLY IN THE PREVIOUS ONE
+	Kept    []QualifiedTeamModel `json:"kept"`
+	Added   []QualifiedTeamModel `json:"added"`
+	Dropped []QualifiedTeamModel `json:"dropped"`
+}
+
+// THE BRACKET CreatePlayoffs WOULD CREATE FROM THE CURRENT STANDINGS, NOTHING OF IT IS WRITTEN
+type PlayoffsPreviewModel struct {
+	Season      string   `json:"season"`
+	Competition string   `json:"competition"`
+	Conferences []string `json:"conferences"`
+	Limit       int      `json:"limit"`
+	// THE TEAMS OF THE FIRST ROUND BY SEED
+	Qualified []QualifiedTeamModel `json:"qualified"`
+	// ROWS THAT WOULD BE INSERTED INTO playoffs
+	Rows int `json:"rows"`
+	// THE GAMES IN THE SHAPE OF ListPlayoffs, WITH THE IDS THEY WOULD HAVE HAD
+	Bracket [][][]PlayoffsModel `json:"bracket"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "github.com/google/uuid"
+
+// A RULE OF THE BRACKET A SERIES BREAKS. Round AND Series ARE THE fixture_round AND game_count OF THE SERIES
+type BracketViolationModel struct {
+	Code       string     `json:"code"`
+	Round      int        `json:"round"`
+	Series     string     `json:"series"`
+	PlayoffsId *uuid.UUID `json:"playoffsId"`
+	Message    string     `json:"message"`
+	// SET WHEN RepairPlayoffs FIXES IT BY RECOMPUTING THE NEXT ROUND SLOTS
+	Repairable bool `json:"repairable"`
+}
+
+// THE VIOLATIONS FOUND IN THE BRACKET OF A COMPETITION, AND WHAT A REPAIR CHANGED
+type BracketIntegrityModel struct {
+	Season      string                  `json:"season"`
+	Competition string                  `json:"competition"`
+	Violations  []BracketViolationModel `json:"violations"`
+	// GAMES WHOSE SLOTS THE REPAIR CHANGED, EMPTY WHEN THE BRACKET WAS ONLY VALIDATED
+	Repaired []GameChangeModel `json:"repaired"`
+	// VIOLATIONS LEFT IN THE BRACKET, THE SAME AS Violations WHEN NOTHING WAS REPAIRED
+	Remaining []BracketViolationModel `json:"remaining"`
+	Valid     bool                    `json:"valid"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// A COMMITTED BRACKET CHANGE WAITING TO BE PUBLISHED. Payload IS THE BracketChangeModel AS JSON AND EventId
+// IS ITS IDEMPOTENCY KEY, THE SAME FOR EVERY ATTEMPT
+type OutboxModel struct {
+	OutboxId    int64          `db:"outbox_id" json:"outboxId"`
+	EventId     uuid.UUID      `db:"event_id" json:"eventId"`
+	League      string         `db:"league" json:"league"`
+	Season      string         `db:"season" json:"season"`
+	Competition string         `db:"competition" json:"competition"`
+	Operation   string         `db:"operation" json:"operation"`
+	Payload     types.JSONText `db:"payload" json:"payload"`
+	Attempts    int            `db:"attempts" json:"attempts"`
+	AvailableAt time.Time      `db:"available_at" json:"availableAt"`
+	LastError   *string        `db:"last_error" json:"lastError"`
+	PublishedAt *time.Time     `db:"published_at" json:"publishedAt"`
+	CreatedAt   time.Time      `db:"created_at" json:"createdAt"`
+}
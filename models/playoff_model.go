@@ -1,6 +1,10 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type PlayoffsModel struct {
 	Operation       string     `db:"operation" json:"operation"`
@@ -15,9 +19,14 @@ type PlayoffsModel struct {
 	AwayTeamName    *string    `db:"away_team_name" json:"awayTeamName"`
 	PlayersInAwayId uuid.UUID  `db:"players_in_away_id" json:"playersInAwayId"`
 	Season          string     `db:"season" json:"season"`
+	League          string     `db:"league" json:"league"`
+	Competition     string     `db:"competition" json:"competition"`
 	Winner          *uuid.UUID `db:"winner" json:"winner"`
 	HomeTeamURL     *string    `db:"home_team_url" json:"homeTeamURL"`
 	AwayTeamURL     *string    `db:"away_team_url" json:"awayTeamURL"`
+	Version         int        `db:"version" json:"version"`
+	ArchivedAt      *time.Time `db:"archived_at" json:"archivedAt"`
+	ScheduledAt     *time.Time `db:"scheduled_at" json:"scheduledAt"`
 }
 type PlayoffsModelRes struct {
 	Operation       string    `db:"operation" json:"operation"`
@@ -32,7 +41,25 @@ type PlayoffsModelRes struct {
 	AwayTeamName    string    `db:"away_team_name" json:"awayTeamName"`
 	PlayersInAwayId uuid.UUID `db:"players_in_away_id" json:"playersInAwayId"`
 	Season          string    `db:"season" json:"season"`
+	League          string    `db:"league" json:"league"`
+	Competition     string    `db:"competition" json:"competition"`
 	Winner          uuid.UUID `db:"winner" json:"winner"`
 	HomeTeamURL     string    `db:"home_team_url" json:"homeTeamURL"`
 	AwayTeamURL     string    `db:"away_team_url" json:"awayTeamURL"`
+	Version         int       `db:"version" json:"version"`
+	ArchivedAt      time.Time `db:"archived_at" json:"archivedAt"`
+	ScheduledAt     time.Time `db:"scheduled_at" json:"scheduledAt"`
+}
+
+type SeasonModel struct {
+	Season      string `db:"season" json:"season"`
+	Competition string `db:"competition" json:"competition"`
+	Games       int    `db:"games" json:"games"`
+}
+
+type ArchivedSeasonModel struct {
+	Season      string    `db:"season" json:"season"`
+	Competition string    `db:"competition" json:"competition"`
+	ArchivedAt  time.Time `db:"archived_at" json:"archivedAt"`
+	Games       int       `db:"games" json:"games"`
 }
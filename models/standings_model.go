@@ -18,4 +18,5 @@ type StandingsModel struct {
 	Pts           int        `db:"pts" json:"pts"`
 	Conference    string     `db:"conference" json:"conference"`
 	Season        string     `db:"season" json:"season"`
+	League        string     `db:"league" json:"league"`
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/lib/pq"
+)
+
+type WebhookModel struct {
+	WebhookId uuid.UUID `db:"webhook_id" json:"webhookId"`
+	League    string    `db:"league" json:"league"`
+	URL       string    `db:"url" json:"url"`
+	// ONLY RETURNED WHEN THE WEBHOOK IS CREATED
+	Secret     string         `db:"secret" json:"secret,omitempty"`
+	EventTypes pq.StringArray `db:"event_types" json:"eventTypes"`
+	CreatedAt  time.Time      `db:"created_at" json:"createdAt"`
+}
+
+// ONE ATTEMPT TO DELIVER AN EVENT TO A WEBHOOK
+type WebhookDeliveryModel struct {
+	DeliveryId uuid.UUID      `db:"delivery_id" json:"deliveryId"`
+	WebhookId  uuid.UUID      `db:"webhook_id" json:"webhookId"`
+	EventId    uuid.UUID      `db:"event_id" json:"eventId"`
+	EventType  string         `db:"event_type" json:"eventType"`
+	Payload    types.JSONText `db:"payload" json:"payload"`
+	Attempt    int            `db:"attempt" json:"attempt"`
+	StatusCode *int           `db:"status_code" json:"statusCode"`
+	Error      *string        `db:"error" json:"error"`
+	Delivered  bool           `db:"delivered" json:"delivered"`
+	CreatedAt  time.Time      `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,128 @@
+// PACKAGE outbox PUBLISHES THE BRACKET CHANGES WRITTEN TO THE OUTBOX TABLE BY THE MUTATIONS OF queries.
+// A CHANGE IS MARKED PUBLISHED ONLY AFTER EVERY HANDLER ACCEPTED IT, SO A CHANGE IS HANDED TO THE HANDLERS
+// AT LEAST ONCE EVEN WHEN THE PROCESS STOPS RIGHT AFTER THE COMMIT, AND SOMETIMES MORE THAN ONCE.
+// HANDLERS DROP THE REDELIVERIES BY THE EventId OF THE CHANGE
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+)
+
+// RECEIVES A CLAIMED CHANGE. attempt STARTS AT 1. RETURNING AN ERROR KEEPS THE CHANGE IN THE OUTBOX
+// AND HANDS IT AGAIN, TO EVERY HANDLER, AFTER THE BACKOFF
+type Handler func(change models.BracketChangeModel, attempt int) error
+
+// THE OUTBOX QUERIES OF queries.PlayoffsDBConnection
+type Store interface {
+	ClaimOutbox(limit int, maxAttempts int, lease time.Duration) ([]models.OutboxModel, error)
+	MarkOutboxPublished(outboxId int64) error
+	FailOutbox(outboxId int64, message string, retryAfter time.Duration) error
+	PruneOutbox(olderThan time.Duration) (int64, error)
+}
+
+// WAIT BEFORE THE NEXT ATTEMPT: 1s, 2s, 4s... UP TO TEN MINUTES
+func DefaultBackoff(attempt int) time.Duration {
+	wait := time.Second << (attempt - 1)
+	if wait > 10*time.Minute || wait <= 0 {
+		return 10 * time.Minute
+	}
+	return wait
+}
+
+type Relay struct {
+	// CHANGES CLAIMED AT ONCE
+	BatchSize int
+	// HOW OFTEN THE OUTBOX IS CHECKED FOR NEW CHANGES
+	PollInterval time.Duration
+	// HOW LONG A CLAIMED CHANGE IS HIDDEN FROM OTHER RELAYS. IT MUST COVER THE HANDLING OF A WHOLE BATCH
+	Lease time.Duration
+	// CHANGES STILL FAILING AFTER MaxAttempts STAY IN THE OUTBOX WITH THEIR last_error
+	MaxAttempts int
+	Backoff     func(attempt int) time.Duration
+	// PUBLISHED CHANGES ARE DELETED AFTER Retention
+	Retention time.Duration
+
+	store    Store
+	handlers []Handler
+}
+
+func NewRelay(store Store, handlers ...Handler) *Relay {
+	return &Relay{
+		BatchSize:    100,
+		PollInterval: time.Second,
+		Lease:        5 * time.Minute,
+		MaxAttempts:  10,
+		Backoff:      DefaultBackoff,
+		Retention:    7 * 24 * time.Hour,
+		store:        store,
+		handlers:     handlers,
+	}
+}
+
+// DRAINS THE OUTBOX EVERY PollInterval AND PRUNES THE PUBLISHED CHANGES EVERY HOUR UNTIL ctx IS DONE
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+	var pruned time.Time
+	for {
+		for {
+			claimed, err := r.Drain()
+			if err != nil || claimed < r.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+		if time.Since(pruned) > time.Hour {
+			if _, err := r.store.PruneOutbox(r.Retention); err != nil {
+				log.Println("failed to prune the outbox: ", err.Error())
+			}
+			pruned = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CLAIMS ONE BATCH AND HANDS EVERY CHANGE OF IT TO THE HANDLERS. IT RETURNS HOW MANY CHANGES WERE CLAIMED
+func (r *Relay) Drain() (int, error) {
+	claimed, err := r.store.ClaimOutbox(r.BatchSize, r.MaxAttempts, r.Lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, o := range claimed {
+		if errH := r.handle(o); errH != nil {
+			log.Println("failed to publish outbox change ", o.EventId, " attempt ", o.Attempts, ": ", errH.Error())
+			if errF := r.store.FailOutbox(o.OutboxId, errH.Error(), r.Backoff(o.Attempts)); errF != nil {
+				log.Println("failed to record the outbox failure: ", errF.Error())
+			}
+			continue
+		}
+		// WHEN THIS FAILS THE LEASE EXPIRES AND THE CHANGE IS HANDED AGAIN
+		if errM := r.store.MarkOutboxPublished(o.OutboxId); errM != nil {
+			log.Println("failed to mark outbox change ", o.EventId, " as published: ", errM.Error())
+		}
+	}
+	return len(claimed), nil
+}
+
+func (r *Relay) handle(o models.OutboxModel) error {
+	var change models.BracketChangeModel
+	if err := o.Payload.Unmarshal(&change); err != nil {
+		return err
+	}
+	var errs []error
+	for i, h := range r.handlers {
+		if err := h(change, o.Attempts); err != nil {
+			errs = append(errs, errors.New("handler "+strconv.Itoa(i)+": "+err.Error()))
+		}
+	}
+	return errors.Join(errs...)
+}
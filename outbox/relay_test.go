@@ -0,0 +1,143 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AN OUTBOX THAT MAKES FAILED CHANGES AVAILABLE AGAIN RIGHT AWAY
+type memoryStore struct {
+	rows      []models.OutboxModel
+	available map[int64]bool
+	retries   map[int64]time.Duration
+}
+
+func newMemoryStore(changes ...models.BracketChangeModel) *memoryStore {
+	s := &memoryStore{available: map[int64]bool{}, retries: map[int64]time.Duration{}}
+	for i, c := range changes {
+		payload, _ := json.Marshal(c)
+		id := int64(i + 1)
+		s.rows = append(s.rows, models.OutboxModel{OutboxId: id, EventId: c.EventId, Payload: types.JSONText(payload)})
+		s.available[id] = true
+	}
+	return s
+}
+
+func (s *memoryStore) ClaimOutbox(limit int, maxAttempts int, lease time.Duration) ([]models.OutboxModel, error) {
+	claimed := []models.OutboxModel{}
+	for i := range s.rows {
+		o := &s.rows[i]
+		if len(claimed) == limit || o.PublishedAt != nil || !s.available[o.OutboxId] || o.Attempts >= maxAttempts {
+			continue
+		}
+		o.Attempts++
+		s.available[o.OutboxId] = false
+		claimed = append(claimed, *o)
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) MarkOutboxPublished(outboxId int64) error {
+	now := time.Now()
+	s.rows[outboxId-1].PublishedAt = &now
+	return nil
+}
+
+func (s *memoryStore) FailOutbox(outboxId int64, message string, retryAfter time.Duration) error {
+	s.rows[outboxId-1].LastError = &message
+	s.retries[outboxId] = retryAfter
+	s.available[outboxId] = true
+	return nil
+}
+
+func (s *memoryStore) PruneOutbox(olderThan time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func change(operation string) models.BracketChangeModel {
+	return models.BracketChangeModel{EventId: uuid.New(), League: queries.DefaultLeague, Season: "2023-2024", Operation: operation}
+}
+
+func TestRelay_DrainPublishesInOrder(t *testing.T) {
+	store := newMemoryStore(change(queries.AuditCreatePlayoffs), change(queries.AuditUpdatePlayoffs))
+	var handled []models.BracketChangeModel
+	relay := NewRelay(store, func(c models.BracketChangeModel, attempt int) error {
+		handled = append(handled, c)
+		return nil
+	})
+
+	claimed, err := relay.Drain()
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, claimed)
+	require.Len(t, handled, 2)
+	assert.Equal(t, queries.AuditCreatePlayoffs, handled[0].Operation)
+	assert.Equal(t, store.rows[1].EventId, handled[1].EventId)
+	assert.NotNil(t, store.rows[0].PublishedAt)
+	assert.NotNil(t, store.rows[1].PublishedAt)
+}
+
+func TestRelay_FailedChangeIsHandedAgainToEveryHandler(t *testing.T) {
+	store := newMemoryStore(change(queries.AuditUpdatePlayoffs))
+	var first, second []int
+	relay := NewRelay(store,
+		func(c models.BracketChangeModel, attempt int) error {
+			first = append(first, attempt)
+			return nil
+		},
+		func(c models.BracketChangeModel, attempt int) error {
+			second = append(second, attempt)
+			if attempt == 1 {
+				return errors.New("receiver is down")
+			}
+			return nil
+		},
+	)
+
+	_, err := relay.Drain()
+	require.NoError(t, err)
+	assert.Nil(t, store.rows[0].PublishedAt)
+	assert.Contains(t, *store.rows[0].LastError, "receiver is down")
+	assert.Equal(t, time.Second, store.retries[1])
+
+	_, err = relay.Drain()
+	require.NoError(t, err)
+	assert.NotNil(t, store.rows[0].PublishedAt)
+	assert.Equal(t, []int{1, 2}, first)
+	assert.Equal(t, []int{1, 2}, second)
+}
+
+func TestRelay_StopsAfterMaxAttempts(t *testing.T) {
+	store := newMemoryStore(change(queries.AuditUpdatePlayoffs))
+	calls := 0
+	relay := NewRelay(store, func(c models.BracketChangeModel, attempt int) error {
+		calls++
+		return errors.New("receiver is down")
+	})
+	relay.MaxAttempts = 3
+
+	for i := 0; i < 5; i++ {
+		_, err := relay.Drain()
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, calls)
+	assert.Nil(t, store.rows[0].PublishedAt)
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, DefaultBackoff(1))
+	assert.Equal(t, 8*time.Second, DefaultBackoff(4))
+	assert.Equal(t, 10*time.Minute, DefaultBackoff(20))
+	assert.Equal(t, 10*time.Minute, DefaultBackoff(80))
+}
@@ -0,0 +1,174 @@
+package queries
+
+import (
+	"log"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+)
+
+// OPERATIONS RECORDED IN THE AUDIT LOG WHEN A SEASON IS ARCHIVED, RESTORED OR PURGED
+const (
+	AuditArchivePlayoffs = "ARCHIVE_PLAYOFFS"
+	AuditRestorePlayoffs = "RESTORE_PLAYOFFS"
+	AuditPurgePlayoffs   = "PURGE_PLAYOFFS"
+)
+
+// HIDES EVERY GAME OF THE BRACKET FROM ListPlayoffs AND BLOCKS RESULT ENTRIES WHILE KEEPING THE ROWS
+func (p *PlayoffsDBConnection) ArchivePlayoffs(season string, competition string) error {
+	archived, err := p.archivePlayoffs(season, competition, AuditArchivePlayoffs)
+	if err != nil {
+		return err
+	}
+	if archived == 0 {
+		return newError(ErrNotFound, "could not archive the requested records. Records of season "+season+" do not exists or are already archived")
+	}
+	return nil
+}
+
+func (p *PlayoffsDBConnection) archivePlayoffs(season string, competition string, operation string) (int64, error) {
+	query :=
+		`
+	UPDATE playoffs SET archived_at = NOW() WHERE season = $1 AND league = $2 AND competition = $3 AND archived_at IS NULL
+	`
+	competition = competitionOrDefault(competition)
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return 0, errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	sqlRow, err := tx.Exec(query, season, p.LeagueOrDefault(), competition)
+	if err != nil {
+		log.Println("failed to archive playoffs records: ", err.Error())
+		return 0, err
+	}
+	row, errR := sqlRow.RowsAffected()
+	if errR != nil {
+		return 0, errR
+	}
+	if row == 0 {
+		return 0, nil
+	}
+	if errAudit := p.writeAudit(tx, season, competition, nil, operation, nil, map[string]any{"archivedGames": row}); errAudit != nil {
+		return 0, errAudit
+	}
+	change := models.BracketChangeModel{Season: season, Competition: competition, Operation: operation}
+	if errO := p.writeOutbox(tx, &change); errO != nil {
+		return 0, errO
+	}
+	if errC := tx.Commit(); errC != nil {
+		return 0, errC
+	}
+	p.notify(change)
+	return row, nil
+}
+
+// BRINGS BACK A SEASON HIDDEN BY ArchivePlayoffs OR DeletePlayoffs
+func (p *PlayoffsDBConnection) RestorePlayoffs(season string, competition string) error {
+	query :=
+		`
+	UPDATE playoffs SET archived_at = NULL WHERE season = $1 AND league = $2 AND competition = $3 AND archived_at IS NOT NULL
+	`
+	competition = competitionOrDefault(competition)
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	sqlRow, err := tx.Exec(query, season, p.LeagueOrDefault(), competition)
+	if err != nil {
+		log.Println("failed to restore playoffs records: ", err.Error())
+		return err
+	}
+	row, errR := sqlRow.RowsAffected()
+	if errR != nil {
+		return errR
+	}
+	if row == 0 {
+		return newError(ErrNotFound, "could not restore the requested records. Archived records of season "+season+" do not exists")
+	}
+	if errAudit := p.writeAudit(tx, season, competition, nil, AuditRestorePlayoffs, nil, map[string]any{"restoredGames": row}); errAudit != nil {
+		return errAudit
+	}
+	change := models.BracketChangeModel{Season: season, Competition: competition, Operation: AuditRestorePlayoffs}
+	if errO := p.writeOutbox(tx, &change); errO != nil {
+		return errO
+	}
+	if errC := tx.Commit(); errC != nil {
+		return errC
+	}
+	p.notify(change)
+	return nil
+}
+
+// PERMANENTLY REMOVES AN ARCHIVED BRACKET AND ITS CHANGE LOG. confirmSeason MUST REPEAT THE SEASON NAME,
+// AND ONLY ARCHIVED SEASONS CAN BE PURGED SO A LIVE BRACKET ALWAYS GOES THROUGH DeletePlayoffs FIRST.
+// THE PURGED ROWS ARE KEPT IN THE AUDIT LOG
+func (p *PlayoffsDBConnection) PurgePlayoffs(season string, competition string, confirmSeason string) error {
+	var purged []models.PlayoffsModel
+	query :=
+		`
+	DELETE FROM playoffs WHERE season = $1 AND league = $2 AND competition = $3 AND archived_at IS NOT NULL RETURNING *
+	`
+	queryChanges :=
+		`
+	DELETE FROM playoffs_changes WHERE season = $1 AND league = $2 AND competition = $3
+	`
+	competition = competitionOrDefault(competition)
+	if season == "" || confirmSeason != season {
+		return newError(ErrInvalidInput, "could not purge the requested records. The confirmation must repeat the season "+season)
+	}
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	err := tx.Select(&purged, query, season, p.LeagueOrDefault(), competition)
+	if err != nil {
+		log.Println("failed to purge playoffs records: ", err.Error())
+		return err
+	}
+	if len(purged) == 0 {
+		return newError(ErrNotFound, "could not purge the requested records. Archived records of season "+season+" do not exists")
+	}
+	_, errCh := tx.Exec(queryChanges, season, p.LeagueOrDefault(), competition)
+	if errCh != nil {
+		return errCh
+	}
+	if errAudit := p.writeAudit(tx, season, competition, nil, AuditPurgePlayoffs, purged, nil); errAudit != nil {
+		return errAudit
+	}
+	change := models.BracketChangeModel{Season: season, Competition: competition, Operation: AuditPurgePlayoffs}
+	if errO := p.writeOutbox(tx, &change); errO != nil {
+		return errO
+	}
+	if errC := tx.Commit(); errC != nil {
+		return errC
+	}
+	p.notify(change)
+	return nil
+}
+
+func (p *PlayoffsDBConnection) ListArchivedSeasons() ([]models.ArchivedSeasonModel, error) {
+	seasons := []models.ArchivedSeasonModel{}
+	query :=
+		`
+	SELECT season, competition, MAX(archived_at) AS archived_at, COUNT(*) AS games
+	FROM playoffs
+	WHERE league = $1
+	AND archived_at IS NOT NULL
+	GROUP BY season, competition
+	ORDER BY archived_at DESC
+	`
+	err := p.DB.Select(&seasons, query, p.LeagueOrDefault())
+	if err != nil {
+		log.Println("error SELECTING archived seasons: ", err.Error())
+		return []models.ArchivedSeasonModel{}, err
+	}
+	return seasons, nil
+}
@@ -0,0 +1,95 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRestorePlayoffs_Success tests bringing back an archived season
+func (suite *PlayoffsTestSuite) TestRestorePlayoffs_Success() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`UPDATE playoffs SET archived_at = NULL WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NOT NULL`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, nil, AuditRestorePlayoffs, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.expectOutbox(season, AuditRestorePlayoffs)
+	suite.mock.ExpectCommit()
+
+	err := suite.conn.RestorePlayoffs(season, "")
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRestorePlayoffs_NotArchived tests restoring a season that is not archived
+func (suite *PlayoffsTestSuite) TestRestorePlayoffs_NotArchived() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`UPDATE playoffs SET archived_at = NULL`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.RestorePlayoffs(season, "")
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "could not restore the requested records")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestPurgePlayoffs_RequiresConfirmation tests that purge refuses without repeating the season
+func (suite *PlayoffsTestSuite) TestPurgePlayoffs_RequiresConfirmation() {
+	err := suite.conn.PurgePlayoffs("2023-2024", "", "2023")
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "confirmation must repeat the season")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestPurgePlayoffs_Success tests permanently removing an archived season
+func (suite *PlayoffsTestSuite) TestPurgePlayoffs_Success() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`DELETE FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NOT NULL RETURNING \*`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "season", "archived_at"}).
+			AddRow(uuid.New(), season, time.Now()))
+	suite.mock.ExpectExec(`DELETE FROM playoffs_changes WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, nil, AuditPurgePlayoffs, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.expectOutbox(season, AuditPurgePlayoffs)
+	suite.mock.ExpectCommit()
+
+	err := suite.conn.PurgePlayoffs(season, "", season)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestListArchivedSeasons_Success tests listing archived seasons
+func (suite *PlayoffsTestSuite) TestListArchivedSeasons_Success() {
+	suite.mock.ExpectQuery(`SELECT season, competition, MAX\(archived_at\) AS archived_at, COUNT\(\*\) AS games FROM playoffs WHERE league = \$1 AND archived_at IS NOT NULL GROUP BY season, competition`).
+		WithArgs(DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"season", "competition", "archived_at", "games"}).
+			AddRow("2022-2023", DefaultCompetition, time.Now(), 45))
+
+	result, err := suite.conn.ListArchivedSeasons()
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 1)
+	assert.Equal(suite.T(), 45, result[0].Games)
+	assert.Equal(suite.T(), DefaultCompetition, result[0].Competition)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
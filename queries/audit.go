@@ -0,0 +1,106 @@
+package queries
+
+import (
+	"encoding/json"
+	"log"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// OPERATIONS RECORDED IN THE playoffs_audit TABLE
+const (
+	AuditCreatePlayoffs       = "CREATE_PLAYOFFS"
+	AuditUpdatePlayoffs       = "UPDATE_PLAYOFFS"
+	AuditUpdatePlayoffsToNull = "UPDATE_PLAYOFFS_TO_NULL"
+	AuditDeletePlayoffs       = "DELETE_PLAYOFFS"
+)
+
+// ACTOR RECORDED WHEN THE CONNECTION WAS NOT GIVEN ONE THROUGH WithActor
+const unknownActor = "unknown"
+
+// RETURNS A COPY OF THE CONNECTION THAT RECORDS THE GIVEN ACTOR (USER NAME, EMAIL, SERVICE NAME...)
+// IN THE AUDIT LOG FOR EVERY MUTATION IT RUNS. THE UNDERLYING DATABASE POOL IS SHARED
+func (p *PlayoffsDBConnection) WithActor(actor string) *PlayoffsDBConnection {
+	c := *p
+	c.Actor = actor
+	return &c
+}
+
+func (p *PlayoffsDBConnection) actor() string {
+	if p.Actor == "" {
+		return unknownActor
+	}
+	return p.Actor
+}
+
+// APPENDS A RECORD TO THE AUDIT LOG INSIDE THE CALLER'S TRANSACTION SO IT IS ONLY KEPT WHEN THE MUTATION COMMITS
+func (p *PlayoffsDBConnection) writeAudit(tx *sqlx.Tx, season string, competition string, playoffsId *uuid.UUID, operation string, before any, after any) error {
+	query :=
+		`
+	INSERT INTO playoffs_audit
+	(audit_id, season, league, competition, playoffs_id, operation, actor, before_value, after_value)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	beforeValue, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	afterValue, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(query, uuid.New(), season, p.LeagueOrDefault(), competition, playoffsId, operation, p.actor(), types.JSONText(beforeValue), types.JSONText(afterValue))
+	if err != nil {
+		log.Println("failed to INSERT playoffs_audit record: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// APPENDS ONE AUDIT RECORD FOR EVERY OTHER GAME A RESULT ENTRY ON playoffsId CHANGED, I.E. THE NEXT ROUND SLOTS IT
+// FILLED OR CLEARED AS recordChanges FOUND THEM, SO THE HISTORY OF THOSE GAMES SHOWS WHO ADVANCED OR REMOVED A TEAM.
+// THE GAME ITSELF IS AUDITED BY THE CALLER
+func (p *PlayoffsDBConnection) writeCascadeAudit(tx *sqlx.Tx, season string, competition string, playoffsId uuid.UUID, operation string, games []models.GameChangeModel) error {
+	for _, g := range games {
+		if g.After.PlayoffsId == playoffsId {
+			continue
+		}
+		id := g.After.PlayoffsId
+		if err := p.writeAudit(tx, season, competition, &id, operation, g.Before, g.After); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PlayoffsDBConnection) ListAuditLog(season string, competition string) ([]models.AuditModel, error) {
+	auditLog := []models.AuditModel{}
+	query :=
+		`
+	SELECT * FROM playoffs_audit WHERE season = $1 AND league = $2 AND competition = $3 ORDER BY created_at ASC
+	`
+	err := p.DB.Select(&auditLog, query, season, p.LeagueOrDefault(), competitionOrDefault(competition))
+	if err != nil {
+		log.Println("error SELECTING playoffs_audit of season: ", err.Error())
+		return []models.AuditModel{}, err
+	}
+	return auditLog, nil
+}
+
+func (p *PlayoffsDBConnection) ListGameAuditLog(playoffsId uuid.UUID) ([]models.AuditModel, error) {
+	auditLog := []models.AuditModel{}
+	query :=
+		`
+	SELECT * FROM playoffs_audit WHERE playoffs_id = $1 AND league = $2 ORDER BY created_at ASC
+	`
+	err := p.DB.Select(&auditLog, query, playoffsId, p.LeagueOrDefault())
+	if err != nil {
+		log.Println("error SELECTING playoffs_audit of game: ", err.Error())
+		return []models.AuditModel{}, err
+	}
+	return auditLog, nil
+}
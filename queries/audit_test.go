@@ -0,0 +1,59 @@
+package queries
+
+import (
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var auditColumns = []string{"audit_id", "season", "playoffs_id", "operation", "actor", "before_value", "after_value", "created_at"}
+
+// TestListAuditLog_Success tests listing the history of a competition of a season
+func (suite *PlayoffsTestSuite) TestListAuditLog_Success() {
+	season := "2023-2024"
+	playoffsID := uuid.New()
+	now := time.Now()
+
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs_audit WHERE season = \$1 AND league = \$2 AND competition = \$3 ORDER BY created_at ASC`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows(auditColumns).
+			AddRow(uuid.New(), season, nil, AuditCreatePlayoffs, "admin", []byte(`null`), []byte(`{"limit":8}`), now).
+			AddRow(uuid.New(), season, playoffsID, AuditUpdatePlayoffs, "scorekeeper", []byte(`{"winner":null}`), []byte(`{"winner":"x"}`), now))
+
+	result, err := suite.conn.ListAuditLog(season, "")
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 2)
+	assert.Nil(suite.T(), result[0].PlayoffsId)
+	assert.Equal(suite.T(), `{"limit":8}`, result[0].After.String())
+	assert.Equal(suite.T(), playoffsID, *result[1].PlayoffsId)
+	assert.Equal(suite.T(), "scorekeeper", result[1].Actor)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestListGameAuditLog_DatabaseError tests listing the history of a game with a database error
+func (suite *PlayoffsTestSuite) TestListGameAuditLog_DatabaseError() {
+	playoffsID := uuid.New()
+
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs_audit WHERE playoffs_id = \$1`).
+		WithArgs(playoffsID, DefaultLeague).
+		WillReturnError(errors.New("database error"))
+
+	result, err := suite.conn.ListGameAuditLog(playoffsID)
+
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), result)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestWithActor_SharesConnection tests that WithActor does not modify the original connection
+func (suite *PlayoffsTestSuite) TestWithActor_SharesConnection() {
+	scoped := suite.conn.WithActor("scorekeeper")
+
+	assert.Equal(suite.T(), "scorekeeper", scoped.actor())
+	assert.Equal(suite.T(), unknownActor, suite.conn.actor())
+	assert.Same(suite.T(), suite.conn.DB, scoped.DB)
+}
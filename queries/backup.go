@@ -0,0 +1,298 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/lib/pq"
+)
+
+// OPERATION RECORDED IN THE AUDIT LOG WHEN A SEASON IS RESTORED FROM A BACKUP
+const AuditRestoreSeason = "RESTORE_SEASON"
+
+// READS EVERY ROW OF A SEASON IN ONE SNAPSHOT, SO A RESULT ENTERED WHILE THE BACKUP RUNS IS EITHER FULLY IN IT
+// OR NOT AT ALL
+func (p *PlayoffsDBConnection) BackupSeason(season string) (models.SeasonBackupModel, error) {
+	queryStandings :=
+		`
+	SELECT * FROM standings WHERE season = $1 AND league = $2 ORDER BY conference ASC, position ASC, standings_id ASC
+	`
+	queryPlayoffs :=
+		`
+	SELECT * FROM playoffs WHERE season = $1 AND league = $2
+	ORDER BY competition ASC, fixture_round ASC, game_count ASC, game_round ASC
+	`
+	queryAudit :=
+		`
+	SELECT * FROM playoffs_audit WHERE season = $1 AND league = $2 ORDER BY created_at ASC
+	`
+	queryChanges :=
+		`
+	SELECT * FROM playoffs_changes WHERE season = $1 AND league = $2 ORDER BY change_seq ASC
+	`
+	backup := models.SeasonBackupModel{
+		League:    p.LeagueOrDefault(),
+		Season:    season,
+		Standings: []models.StandingsModel{},
+		Playoffs:  []models.PlayoffsModel{},
+		Audit:     []models.AuditModel{},
+		Changes:   []models.ChangeModel{},
+	}
+	tx, errTx := p.DB.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if errTx != nil {
+		return models.SeasonBackupModel{}, errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if err := tx.Select(&backup.Standings, queryStandings, season, p.LeagueOrDefault()); err != nil {
+		log.Println("error SELECTING the standings to back up: ", err.Error())
+		return models.SeasonBackupModel{}, err
+	}
+	if err := tx.Select(&backup.Playoffs, queryPlayoffs, season, p.LeagueOrDefault()); err != nil {
+		log.Println("error SELECTING the playoffs to back up: ", err.Error())
+		return models.SeasonBackupModel{}, err
+	}
+	if len(backup.Standings) == 0 && len(backup.Playoffs) == 0 {
+		return models.SeasonBackupModel{}, newError(ErrNotFound, "season "+season+" has no standings and no playoffs to back up")
+	}
+	if err := tx.Select(&backup.Audit, queryAudit, season, p.LeagueOrDefault()); err != nil {
+		log.Println("error SELECTING the audit log to back up: ", err.Error())
+		return models.SeasonBackupModel{}, err
+	}
+	if err := tx.Select(&backup.Changes, queryChanges, season, p.LeagueOrDefault()); err != nil {
+		log.Println("error SELECTING the change log to back up: ", err.Error())
+		return models.SeasonBackupModel{}, err
+	}
+	if err := tx.Get(&backup.CreatedAt, `SELECT NOW()`); err != nil {
+		return models.SeasonBackupModel{}, err
+	}
+	return backup, nil
+}
+
+// HOW RestoreSeason WRITES THE AUDIT ENTRIES OF A BACKUP
+type RestoreAudit int
+
+const (
+	// AS THEY ARE, WITH THEIR IDS, ACTORS AND DATES. ONLY FOR ARCHIVES OF A TRUSTED SOURCE, E.G. AN OPERATOR
+	// RESTORING THE BACKUP OF THEIR OWN DATABASE
+	RestoreAuditAsIs RestoreAudit = iota
+	// AS NEW RESTORE_SEASON ENTRIES OF THE RESTORING ACTOR, DATED NOW, WHOSE AFTER VALUE IS THE ENTRY OF THE BACKUP,
+	// SO AN UPLOADED ARCHIVE CAN NOT FORGE WHO DID WHAT AND WHEN
+	RestoreAuditRestamped
+)
+
+// RECREATES A SEASON FROM A BACKUP IN THE LEAGUE OF THE CONNECTION, IN ONE TRANSACTION AND WITH THE IDS OF THE
+// BACKUP. A SEASON THAT ALREADY HAS STANDINGS OR PLAYOFFS IS REFUSED UNLESS replace IS SET, WHICH DELETES THEM AND
+// THEIR CHANGE LOG FIRST. THE AUDIT LOG IS APPEND ONLY: ENTRIES ALREADY IN THE DATABASE ARE KEPT AND THE ENTRIES
+// OF THE BACKUP ARE ADDED AS audit SAYS
+func (p *PlayoffsDBConnection) RestoreSeason(backup models.SeasonBackupModel, replace bool, audit RestoreAudit) error {
+	queryCount :=
+		`
+	SELECT (SELECT COUNT(*) FROM standings WHERE season = $1 AND league = $2)
+	+ (SELECT COUNT(*) FROM playoffs WHERE season = $1 AND league = $2) AS count
+	`
+	queryDelete := []string{
+		`DELETE FROM playoffs WHERE season = $1 AND league = $2`,
+		`DELETE FROM standings WHERE season = $1 AND league = $2`,
+		`DELETE FROM playoffs_changes WHERE season = $1 AND league = $2`,
+	}
+	// THE IDS ARE KEPT, SO THEY MAY ONLY BE TAKEN BY ANOTHER LEAGUE OF THE SAME DATABASE
+	queryTaken :=
+		`
+	SELECT (SELECT COUNT(*) FROM standings WHERE standings_id = ANY($1))
+	+ (SELECT COUNT(*) FROM playoffs WHERE playoffs_id = ANY($2)) AS count
+	`
+	queryStandings :=
+		`
+	INSERT INTO standings
+	(standings_id, team_id, position, team_name, acronym, team_pic_url, gp, w, l, win_percentage, gf, pts, conference, season, league)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	queryPlayoffs :=
+		`
+	INSERT INTO playoffs
+	(playoffs_id, fixture_round, game_count, game_round, home_team_id, home_team_name, home_team_url, players_in_home_id,
+	away_team_id, away_team_name, away_team_url, players_in_away_id, season, league, competition, winner, version,
+	archived_at, scheduled_at)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`
+	queryAudit :=
+		`
+	INSERT INTO playoffs_audit
+	(audit_id, season, league, competition, playoffs_id, operation, actor, before_value, after_value, created_at)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (audit_id) DO NOTHING
+	`
+	queryChanges :=
+		`
+	INSERT INTO playoffs_changes
+	(batch_id, season, league, competition, playoffs_id, operation, before_value, after_value, undone, created_at)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	season := backup.Season
+	if err := validateBackup(backup); err != nil {
+		return err
+	}
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var count int
+	if err := tx.Get(&count, queryCount, season, p.LeagueOrDefault()); err != nil {
+		log.Println("error counting the records of the season to restore: ", err.Error())
+		return err
+	}
+	if count > 0 && !replace {
+		return newError(ErrAlreadyExists, "Cannot restore season "+season+", it already has "+strconv.Itoa(count)+" standings and playoffs records. Restore with replace to overwrite them")
+	}
+	if count > 0 {
+		for _, q := range queryDelete {
+			if _, err := tx.Exec(q, season, p.LeagueOrDefault()); err != nil {
+				log.Println("failed to delete the records of the season to replace: ", err.Error())
+				return err
+			}
+		}
+	}
+	standingsIds := make([]string, 0, len(backup.Standings))
+	for _, s := range backup.Standings {
+		standingsIds = append(standingsIds, s.StandingsId.String())
+	}
+	playoffsIds := make([]string, 0, len(backup.Playoffs))
+	for _, g := range backup.Playoffs {
+		playoffsIds = append(playoffsIds, g.PlayoffsId.String())
+	}
+	var taken int
+	if err := tx.Get(&taken, queryTaken, pq.StringArray(standingsIds), pq.StringArray(playoffsIds)); err != nil {
+		return err
+	}
+	if taken > 0 {
+		return newError(ErrAlreadyExists, "Cannot restore season "+season+", "+strconv.Itoa(taken)+" of its records belong to another league of this database")
+	}
+
+	for _, s := range backup.Standings {
+		_, err := tx.Exec(queryStandings, s.StandingsId, s.TeamId, s.Position, s.TeamName, s.Acronym, s.TeamPicUrl,
+			s.Gp, s.W, s.L, s.WinPercentage, s.Gf, s.Pts, s.Conference, season, p.LeagueOrDefault())
+		if err != nil {
+			log.Println("failed to INSERT restored standings record: ", err.Error())
+			return err
+		}
+	}
+	competitions := []string{}
+	for _, g := range backup.Playoffs {
+		competition := competitionOrDefault(g.Competition)
+		_, err := tx.Exec(queryPlayoffs, g.PlayoffsId, g.FixtureRound, g.GameCount, g.GameRound, g.HomeTeamId, g.HomeTeamName,
+			g.HomeTeamURL, g.PlayersInHomeId, g.AwayTeamId, g.AwayTeamName, g.AwayTeamURL, g.PlayersInAwayId, season, p.LeagueOrDefault(),
+			competition, g.Winner, g.Version, g.ArchivedAt, g.ScheduledAt)
+		if err != nil {
+			log.Println("failed to INSERT restored playoffs record: ", err.Error())
+			return err
+		}
+		if len(competitions) == 0 || competitions[len(competitions)-1] != competition {
+			competitions = append(competitions, competition)
+		}
+	}
+	for _, a := range backup.Audit {
+		if audit == RestoreAuditRestamped {
+			if errAudit := p.writeAudit(tx, season, competitionOrDefault(a.Competition), a.PlayoffsId, AuditRestoreSeason, nil, a); errAudit != nil {
+				return errAudit
+			}
+			continue
+		}
+		_, err := tx.Exec(queryAudit, a.AuditId, season, p.LeagueOrDefault(), competitionOrDefault(a.Competition), a.PlayoffsId,
+			a.Operation, a.Actor, jsonOrNull(a.Before), jsonOrNull(a.After), a.CreatedAt)
+		if err != nil {
+			log.Println("failed to INSERT restored audit entry: ", err.Error())
+			return err
+		}
+	}
+	for _, c := range backup.Changes {
+		_, err := tx.Exec(queryChanges, c.BatchId, season, p.LeagueOrDefault(), competitionOrDefault(c.Competition), c.PlayoffsId,
+			c.Operation, jsonOrNull(c.Before), jsonOrNull(c.After), c.Undone, c.CreatedAt)
+		if err != nil {
+			log.Println("failed to INSERT restored change: ", err.Error())
+			return err
+		}
+	}
+
+	// A BACKUP WITHOUT PLAYOFFS STILL RECORDS THE RESTORE OF ITS STANDINGS
+	if len(competitions) == 0 {
+		competitions = append(competitions, DefaultCompetition)
+	}
+	restored := map[string]any{
+		"backupLeague":    backup.League,
+		"backupCreatedAt": backup.CreatedAt,
+		"standings":       len(backup.Standings),
+		"playoffs":        len(backup.Playoffs),
+		"audit":           len(backup.Audit),
+		"auditRestamped":  audit == RestoreAuditRestamped,
+		"replaced":        count,
+	}
+	var changes []models.BracketChangeModel
+	for _, competition := range competitions {
+		if errAudit := p.writeAudit(tx, season, competition, nil, AuditRestoreSeason, nil, restored); errAudit != nil {
+			return errAudit
+		}
+		change := models.BracketChangeModel{Season: season, Competition: competition, Operation: AuditRestoreSeason}
+		if errO := p.writeOutbox(tx, &change); errO != nil {
+			return errO
+		}
+		changes = append(changes, change)
+	}
+	if errC := tx.Commit(); errC != nil {
+		return errC
+	}
+	for _, change := range changes {
+		p.notify(change)
+	}
+	return nil
+}
+
+// EVERY ROW OF A BACKUP MUST BELONG TO ITS SEASON, AND THE STANDINGS AND GAMES MUST HAVE IDS
+func validateBackup(backup models.SeasonBackupModel) error {
+	season := backup.Season
+	if season == "" {
+		return newError(ErrInvalidInput, "the backup has no season")
+	}
+	if len(backup.Standings) == 0 && len(backup.Playoffs) == 0 {
+		return newError(ErrInvalidInput, "the backup of season "+season+" has no standings and no playoffs")
+	}
+	for i, s := range backup.Standings {
+		if s.Season != season || s.StandingsId == uuid.Nil {
+			return newError(ErrInvalidInput, "standings record "+strconv.Itoa(i+1)+" of the backup has no id or is not of season "+season)
+		}
+	}
+	for i, g := range backup.Playoffs {
+		if g.Season != season || g.PlayoffsId == uuid.Nil {
+			return newError(ErrInvalidInput, "playoffs record "+strconv.Itoa(i+1)+" of the backup has no id or is not of season "+season)
+		}
+	}
+	for i, a := range backup.Audit {
+		if a.Season != season {
+			return newError(ErrInvalidInput, "audit entry "+strconv.Itoa(i+1)+" of the backup is not of season "+season)
+		}
+	}
+	for i, c := range backup.Changes {
+		if c.Season != season {
+			return newError(ErrInvalidInput, "change "+strconv.Itoa(i+1)+" of the backup is not of season "+season)
+		}
+	}
+	return nil
+}
+
+// THE JSONB COLUMNS ARE NOT NULL, AN EMPTY VALUE IS STORED AS THE JSON null
+func jsonOrNull(value types.JSONText) types.JSONText {
+	if len(value) == 0 {
+		return types.JSONText("null")
+	}
+	return value
+}
@@ -0,0 +1,196 @@
+package queries
+
+import (
+	"errors"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackupSeason_Success tests reading every row of a season in one read only transaction
+func (suite *PlayoffsTestSuite) TestBackupSeason_Success() {
+	season := "2023-2024"
+	standingsID, playoffsID := uuid.New(), uuid.New()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \* FROM standings WHERE season = \$1 AND league = \$2`).
+		WithArgs(season, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"standings_id", "team_name", "season"}).AddRow(standingsID, "Lions", season))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND league = \$2`).
+		WithArgs(season, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "season", "competition"}).AddRow(playoffsID, season, DefaultCompetition))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs_audit WHERE season = \$1 AND league = \$2`).
+		WithArgs(season, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"audit_id", "season", "operation"}).AddRow(uuid.New(), season, AuditCreatePlayoffs))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs_changes WHERE season = \$1 AND league = \$2`).
+		WithArgs(season, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"change_seq", "season"}))
+	suite.mock.ExpectQuery(`SELECT NOW\(\)`).WillReturnRows(sqlmock.NewRows([]string{"now"}).AddRow(now))
+	suite.mock.ExpectRollback()
+
+	backup, err := suite.conn.BackupSeason(season)
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), DefaultLeague, backup.League)
+	assert.Equal(suite.T(), now, backup.CreatedAt)
+	require.Len(suite.T(), backup.Standings, 1)
+	assert.Equal(suite.T(), standingsID, backup.Standings[0].StandingsId)
+	require.Len(suite.T(), backup.Playoffs, 1)
+	assert.Len(suite.T(), backup.Audit, 1)
+	assert.Empty(suite.T(), backup.Changes)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBackupSeason_NotFound tests backing up a season that has no rows
+func (suite *PlayoffsTestSuite) TestBackupSeason_NotFound() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \* FROM standings`).WillReturnRows(sqlmock.NewRows([]string{"standings_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs`).WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectRollback()
+
+	_, err := suite.conn.BackupSeason("1999")
+
+	assert.True(suite.T(), errors.Is(err, ErrNotFound))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func sampleBackup(season string) models.SeasonBackupModel {
+	teamID := uuid.New()
+	round, count := 0, "FINAL"
+	return models.SeasonBackupModel{
+		League:    "north",
+		Season:    season,
+		Standings: []models.StandingsModel{{StandingsId: uuid.New(), TeamId: &teamID, TeamName: "Lions", Conference: "East", Season: season}},
+		Playoffs:  []models.PlayoffsModel{{PlayoffsId: uuid.New(), FixtureRound: &round, GameCount: &count, GameRound: "1", HomeTeamId: &teamID, Season: season, Competition: "cup", Version: 2}},
+		Audit:     []models.AuditModel{{AuditId: uuid.New(), Season: season, Competition: "cup", Operation: AuditCreatePlayoffs, Actor: "admin"}},
+	}
+}
+
+// TestRestoreSeason_Conflict tests that an existing season is only overwritten when asked to
+func (suite *PlayoffsTestSuite) TestRestoreSeason_Conflict() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings WHERE season = \$1 AND league = \$2\)`).
+		WithArgs(season, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.RestoreSeason(sampleBackup(season), false, RestoreAuditAsIs)
+
+	assert.True(suite.T(), errors.Is(err, ErrAlreadyExists))
+	assert.Contains(suite.T(), err.Error(), "replace")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRestoreSeason_Replace tests replacing a season with the rows of a backup, keeping their ids
+func (suite *PlayoffsTestSuite) TestRestoreSeason_Replace() {
+	season := "2023-2024"
+	backup := sampleBackup(season)
+	s, g, a := backup.Standings[0], backup.Playoffs[0], backup.Audit[0]
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings`).
+		WithArgs(season, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	suite.mock.ExpectExec(`DELETE FROM playoffs WHERE season = \$1 AND league = \$2`).WithArgs(season, DefaultLeague).WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(`DELETE FROM standings WHERE season = \$1 AND league = \$2`).WithArgs(season, DefaultLeague).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(`DELETE FROM playoffs_changes WHERE season = \$1 AND league = \$2`).WithArgs(season, DefaultLeague).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings WHERE standings_id = ANY\(\$1\)\)`).
+		WithArgs(pq.StringArray{s.StandingsId.String()}, pq.StringArray{g.PlayoffsId.String()}).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectExec(`INSERT INTO standings`).
+		WithArgs(s.StandingsId, s.TeamId, 0, "Lions", "", nil, 0, 0, 0, 0.0, 0, 0, "East", season, DefaultLeague).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs`).
+		WithArgs(g.PlayoffsId, g.FixtureRound, g.GameCount, "1", g.HomeTeamId, nil, nil, uuid.Nil, nil, nil, nil, uuid.Nil,
+			season, DefaultLeague, "cup", nil, 2, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit .* ON CONFLICT \(audit_id\) DO NOTHING`).
+		WithArgs(a.AuditId, season, DefaultLeague, "cup", nil, AuditCreatePlayoffs, "admin", sqlmock.AnyArg(), sqlmock.AnyArg(), a.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, "cup", nil, AuditRestoreSeason, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO outbox`).
+		WithArgs(sqlmock.AnyArg(), DefaultLeague, season, "cup", AuditRestoreSeason, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.conn.RestoreSeason(backup, true, RestoreAuditAsIs)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRestoreSeason_IdsOfAnotherLeague tests that a backup is not restored over the rows of another league
+func (suite *PlayoffsTestSuite) TestRestoreSeason_IdsOfAnotherLeague() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings WHERE season`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings WHERE standings_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.RestoreSeason(sampleBackup(season), false, RestoreAuditAsIs)
+
+	assert.True(suite.T(), errors.Is(err, ErrAlreadyExists))
+	assert.Contains(suite.T(), err.Error(), "another league")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRestoreSeason_RowOfAnotherSeason tests that a tampered backup is refused before touching the database
+func (suite *PlayoffsTestSuite) TestRestoreSeason_RowOfAnotherSeason() {
+	backup := sampleBackup("2023-2024")
+	backup.Playoffs[0].Season = "2022-2023"
+
+	err := suite.conn.RestoreSeason(backup, true, RestoreAuditAsIs)
+
+	assert.True(suite.T(), errors.Is(err, ErrInvalidInput))
+	assert.Contains(suite.T(), err.Error(), "playoffs record 1")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRestoreSeason_RestampedAudit tests that the audit entries of an uploaded backup are recorded as restore entries
+// of the restoring actor, not under the actor and date they claim
+func (suite *PlayoffsTestSuite) TestRestoreSeason_RestampedAudit() {
+	season := "2023-2024"
+	backup := sampleBackup(season)
+	backup.Standings = nil
+	backup.Audit[0].Actor = "commissioner"
+	backup.Audit[0].CreatedAt = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := backup.Playoffs[0]
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings WHERE season`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings WHERE standings_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectExec(`INSERT INTO playoffs`).
+		WithArgs(g.PlayoffsId, g.FixtureRound, g.GameCount, "1", g.HomeTeamId, nil, nil, uuid.Nil, nil, nil, nil, uuid.Nil,
+			season, "north", "cup", nil, 2, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit .* VALUES\(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9\)$`).
+		WithArgs(sqlmock.AnyArg(), season, "north", "cup", nil, AuditRestoreSeason, "scorekeeper", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, "north", "cup", nil, AuditRestoreSeason, "scorekeeper", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO outbox`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.conn.ForLeague("north").WithActor("scorekeeper").RestoreSeason(backup, false, RestoreAuditRestamped)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,326 @@
+package queries
+
+import (
+	"encoding/json"
+	"log"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// OPERATIONS RECORDED IN THE AUDIT LOG BY UndoPlayoffs AND RedoPlayoffs
+const (
+	AuditUndoPlayoffs = "UNDO_PLAYOFFS"
+	AuditRedoPlayoffs = "REDO_PLAYOFFS"
+)
+
+// SELECTS AND LOCKS EVERY GAME OF THE SEASON FROM THE GIVEN ROUND ONWARDS, WHICH ARE ALL THE ROWS A RESULT
+// ENTRY IN THAT ROUND CAN TOUCH (THE GAME ITSELF AND THE NEXT ROUND SLOTS IT FILLS OR CLEARS). TAKEN BEFORE
+// lockFixture SO NO OTHER ENTRY CAN CHANGE A SIBLING SERIES OR A SHARED NEXT ROUND ROW BETWEEN THE SNAPSHOTS
+// recordChanges COMPARES, AND IN playoffs_id ORDER SO TWO ENTRIES OF THE SAME ROUND WAIT FOR EACH OTHER
+// INSTEAD OF DEADLOCKING
+func snapshotSeason(tx *sqlx.Tx, league string, competition string, season string, round int) ([]models.PlayoffsModel, error) {
+	snapshot := []models.PlayoffsModel{}
+	query :=
+		`
+	SELECT * FROM playoffs WHERE season = $1 AND fixture_round >= $2 AND league = $3 AND competition = $4 ORDER BY playoffs_id FOR UPDATE
+	`
+	err := tx.Select(&snapshot, query, season, round, league, competition)
+	if err != nil {
+		log.Println("error SELECTING playoffs snapshot: ", err.Error())
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func equalPtr[T comparable](a *T, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// COMPARES THE FIELDS A RESULT ENTRY CAN CHANGE: THE TEAMS IN BOTH SLOTS AND THE WINNER
+func sameSlots(a models.PlayoffsModel, b models.PlayoffsModel) bool {
+	return equalPtr(a.HomeTeamId, b.HomeTeamId) &&
+		equalPtr(a.HomeTeamName, b.HomeTeamName) &&
+		equalPtr(a.HomeTeamURL, b.HomeTeamURL) &&
+		equalPtr(a.AwayTeamId, b.AwayTeamId) &&
+		equalPtr(a.AwayTeamName, b.AwayTeamName) &&
+		equalPtr(a.AwayTeamURL, b.AwayTeamURL) &&
+		equalPtr(a.Winner, b.Winner)
+}
+
+// WRITES ONE playoffs_changes ROW FOR EVERY GAME WHOSE SLOTS DIFFER BETWEEN THE TWO SNAPSHOTS AND RETURNS THOSE GAMES.
+// ALL THE ROWS SHARE THE SAME BATCH SO THE WHOLE RESULT ENTRY, INCLUDING THE CASCADING NEXT ROUND UPDATES, IS UNDONE
+// AT ONCE. A NEW CHANGE DISCARDS THE CHANGES THAT WERE UNDONE BUT NOT REDONE
+func recordChanges(tx *sqlx.Tx, league string, competition string, season string, operation string, before []models.PlayoffsModel, after []models.PlayoffsModel) ([]models.GameChangeModel, error) {
+	queryDiscardRedo :=
+		`
+	DELETE FROM playoffs_changes WHERE season = $1 AND league = $2 AND competition = $3 AND undone = TRUE
+	`
+	query :=
+		`
+	INSERT INTO playoffs_changes
+	(batch_id, season, league, competition, playoffs_id, operation, before_value, after_value)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	batchId := uuid.New()
+	discarded := false
+	var games []models.GameChangeModel
+	for _, a := range after {
+		b := findGame(before, a.PlayoffsId)
+		if b == nil || sameSlots(*b, a) {
+			continue
+		}
+		if !discarded {
+			if _, err := tx.Exec(queryDiscardRedo, season, league, competition); err != nil {
+				log.Println("failed to DELETE undone playoffs_changes: ", err.Error())
+				return nil, err
+			}
+			discarded = true
+		}
+		beforeValue, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		afterValue, err := json.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(query, batchId, season, league, competition, a.PlayoffsId, operation, types.JSONText(beforeValue), types.JSONText(afterValue))
+		if err != nil {
+			log.Println("failed to INSERT playoffs_changes record: ", err.Error())
+			return nil, err
+		}
+		games = append(games, models.GameChangeModel{Before: *b, After: a})
+	}
+	return games, nil
+}
+
+// WRITES THE SLOTS OF A RECORDED SNAPSHOT BACK INTO ITS GAME, AS LONG AS THE GAME STILL HOLDS THE SLOTS OF previous.
+// A GAME CHANGED SINCE BY A WRITE THE CHANGE LOG DOES NOT RECORD (A SCHEDULE, A RESTORE OR AN IMPORT...) IS NOT
+// OVERWRITTEN AND ErrPlayoffsConflict IS RETURNED
+func applySlots(tx *sqlx.Tx, league string, competition string, value types.JSONText, previous types.JSONText) error {
+	game, expected := models.PlayoffsModel{}, models.PlayoffsModel{}
+	if err := value.Unmarshal(&game); err != nil {
+		return err
+	}
+	if err := previous.Unmarshal(&expected); err != nil {
+		return err
+	}
+	var current []models.PlayoffsModel
+	query :=
+		`
+	SELECT * FROM playoffs WHERE playoffs_id = $1 AND league = $2 AND competition = $3 AND archived_at IS NULL
+	`
+	if err := tx.Select(&current, query, game.PlayoffsId, league, competition); err != nil {
+		log.Println("error SELECTING the playoffs to replay: ", err.Error())
+		return err
+	}
+	if len(current) == 0 {
+		return newError(ErrNotFound, "could not restore the game "+game.PlayoffsId.String()+", the record does not exists or its season is archived")
+	}
+	if !sameSlots(current[0], expected) {
+		return newError(ErrPlayoffsConflict, "could not restore the game "+game.PlayoffsId.String()+", it was changed since the result was recorded")
+	}
+	return writeSlots(tx, league, competition, game)
+}
+
+// WRITES THE TEAMS IN BOTH SLOTS AND THE WINNER OF game INTO ITS ROW
+func writeSlots(tx *sqlx.Tx, league string, competition string, game models.PlayoffsModel) error {
+	query :=
+		`
+	UPDATE playoffs
+	SET home_team_id = $1, home_team_name = $2, home_team_url = $3,
+	away_team_id = $4, away_team_name = $5, away_team_url = $6,
+	winner = $7, version = version + 1
+	WHERE playoffs_id = $8
+	AND league = $9
+	AND competition = $10
+	AND archived_at IS NULL
+	`
+	sqlRow, err := tx.Exec(
+		query,
+		game.HomeTeamId,
+		game.HomeTeamName,
+		game.HomeTeamURL,
+		game.AwayTeamId,
+		game.AwayTeamName,
+		game.AwayTeamURL,
+		game.Winner,
+		game.PlayoffsId,
+		league,
+		competition,
+	)
+	if err != nil {
+		return err
+	}
+	row, errR := sqlRow.RowsAffected()
+	if errR != nil {
+		return errR
+	}
+	if row == 0 {
+		return newError(ErrNotFound, "could not restore the game "+game.PlayoffsId.String()+", the record does not exists or its season is archived")
+	}
+	return nil
+}
+
+// LOCKS EVERY GAME OF THE SEASON SO NO RESULT CAN BE ENTERED WHILE CHANGES ARE REPLAYED
+func lockSeason(tx *sqlx.Tx, league string, competition string, season string) error {
+	var locked []uuid.UUID
+	query :=
+		`
+	SELECT playoffs_id FROM playoffs WHERE season = $1 AND league = $2 AND competition = $3 ORDER BY playoffs_id FOR UPDATE
+	`
+	err := tx.Select(&locked, query, season, league, competition)
+	if err != nil {
+		log.Println("error locking playoffs season: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// REVERTS THE LAST steps RESULT ENTRIES (UpdatePlayoffs, UpdatePlayoffsToNull, OR A PREVIOUS REDO) OF THE BRACKET,
+// RESTORING EVERY GAME THEY TOUCHED. RETURNS HOW MANY ENTRIES WERE REVERTED, WHICH IS LESS THAN steps WHEN
+// THE CHANGE LOG RUNS OUT. NOTHING IS REVERTED AND ErrPlayoffsConflict IS RETURNED WHEN A GAME NO LONGER HOLDS
+// WHAT THE ENTRY WROTE
+func (p *PlayoffsDBConnection) UndoPlayoffs(season string, competition string, steps int) (int, error) {
+	queryLast :=
+		`
+	SELECT batch_id FROM playoffs_changes
+	WHERE season = $1 AND league = $2 AND competition = $3 AND undone = FALSE
+	ORDER BY change_seq DESC
+	LIMIT 1
+	`
+	queryBatch :=
+		`
+	SELECT * FROM playoffs_changes WHERE batch_id = $1 ORDER BY change_seq DESC
+	`
+	queryMark :=
+		`
+	UPDATE playoffs_changes SET undone = TRUE WHERE batch_id = $1
+	`
+	return p.replayChanges(season, competition, steps, AuditUndoPlayoffs, queryLast, queryBatch, queryMark, func(c models.ChangeModel) types.JSONText {
+		return c.Before
+	}, func(c models.ChangeModel) types.JSONText {
+		return c.After
+	})
+}
+
+// REAPPLIES THE LAST steps ENTRIES REVERTED BY UndoPlayoffs, MOST RECENTLY UNDONE FIRST.
+// ENTERING A NEW RESULT AFTER AN UNDO DISCARDS THE ENTRIES THAT COULD HAVE BEEN REDONE
+func (p *PlayoffsDBConnection) RedoPlayoffs(season string, competition string, steps int) (int, error) {
+	queryLast :=
+		`
+	SELECT batch_id FROM playoffs_changes
+	WHERE season = $1 AND league = $2 AND competition = $3 AND undone = TRUE
+	ORDER BY change_seq ASC
+	LIMIT 1
+	`
+	queryBatch :=
+		`
+	SELECT * FROM playoffs_changes WHERE batch_id = $1 ORDER BY change_seq ASC
+	`
+	queryMark :=
+		`
+	UPDATE playoffs_changes SET undone = FALSE WHERE batch_id = $1
+	`
+	return p.replayChanges(season, competition, steps, AuditRedoPlayoffs, queryLast, queryBatch, queryMark, func(c models.ChangeModel) types.JSONText {
+		return c.After
+	}, func(c models.ChangeModel) types.JSONText {
+		return c.Before
+	})
+}
+
+func (p *PlayoffsDBConnection) replayChanges(
+	season string,
+	competition string,
+	steps int,
+	operation string,
+	queryLast string,
+	queryBatch string,
+	queryMark string,
+	value func(models.ChangeModel) types.JSONText,
+	previous func(models.ChangeModel) types.JSONText,
+) (int, error) {
+	if steps < 1 {
+		return 0, newError(ErrInvalidInput, "the number of changes to replay must be at least 1")
+	}
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return 0, errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	league := p.LeagueOrDefault()
+	competition = competitionOrDefault(competition)
+	if err := lockSeason(tx, league, competition, season); err != nil {
+		return 0, err
+	}
+	replayed := 0
+	var batches []uuid.UUID
+	var games []models.GameChangeModel
+	for replayed < steps {
+		var last []uuid.UUID
+		err := tx.Select(&last, queryLast, season, league, competition)
+		if err != nil {
+			return 0, err
+		}
+		if len(last) == 0 {
+			break
+		}
+		var changes []models.ChangeModel
+		errB := tx.Select(&changes, queryBatch, last[0])
+		if errB != nil {
+			return 0, errB
+		}
+		for _, c := range changes {
+			if errA := applySlots(tx, league, competition, value(c), previous(c)); errA != nil {
+				return 0, errA
+			}
+			game := models.GameChangeModel{}
+			previousValue, currentValue := previous(c), value(c)
+			if errU := previousValue.Unmarshal(&game.Before); errU != nil {
+				return 0, errU
+			}
+			if errU := currentValue.Unmarshal(&game.After); errU != nil {
+				return 0, errU
+			}
+			games = append(games, game)
+		}
+		if _, errM := tx.Exec(queryMark, last[0]); errM != nil {
+			return 0, errM
+		}
+		batches = append(batches, last[0])
+		replayed++
+	}
+	if replayed == 0 {
+		return 0, nil
+	}
+	if errAudit := p.writeAudit(tx, season, competition, nil, operation, nil, map[string]any{"batches": batches}); errAudit != nil {
+		return 0, errAudit
+	}
+	change := models.BracketChangeModel{Season: season, Competition: competition, Operation: operation, Games: games}
+	if errO := p.writeOutbox(tx, &change); errO != nil {
+		return 0, errO
+	}
+	if errC := tx.Commit(); errC != nil {
+		return 0, errC
+	}
+	p.notify(change)
+	return replayed, nil
+}
+
+// HANDS A COMMITTED CHANGE, AS WRITTEN TO THE OUTBOX, TO THE OnChange HOOK
+func (p *PlayoffsDBConnection) notify(change models.BracketChangeModel) {
+	if p.OnChange == nil {
+		return
+	}
+	p.OnChange(change)
+}
@@ -0,0 +1,175 @@
+package queries
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var changeColumns = []string{"change_seq", "batch_id", "season", "playoffs_id", "operation", "before_value", "after_value", "undone", "created_at"}
+
+func changeValue(t require.TestingT, game models.PlayoffsModel) []byte {
+	value, err := json.Marshal(game)
+	require.NoError(t, err)
+	return value
+}
+
+// TestUndoPlayoffs_RestoresCascadingSlots tests that undo restores the game and the next round slot it filled
+func (suite *PlayoffsTestSuite) TestUndoPlayoffs_RestoresCascadingSlots() {
+	season := "2023-2024"
+	batchID := uuid.New()
+	teamID := uuid.New()
+	teamName := "Team1"
+	gameID := uuid.New()
+	nextRoundID := uuid.New()
+
+	game := models.PlayoffsModel{PlayoffsId: gameID, HomeTeamId: &teamID, HomeTeamName: &teamName, Season: season}
+	gameWon := game
+	gameWon.Winner = &teamID
+	nextRound := models.PlayoffsModel{PlayoffsId: nextRoundID, Season: season}
+	nextRoundFilled := nextRound
+	nextRoundFilled.HomeTeamId = &teamID
+	nextRoundFilled.HomeTeamName = &teamName
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3 ORDER BY playoffs_id FOR UPDATE`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}).AddRow(gameID).AddRow(nextRoundID))
+	suite.mock.ExpectQuery(`SELECT batch_id FROM playoffs_changes WHERE season = \$1 AND league = \$2 AND competition = \$3 AND undone = FALSE ORDER BY change_seq DESC`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"batch_id"}).AddRow(batchID))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs_changes WHERE batch_id = \$1 ORDER BY change_seq DESC`).
+		WithArgs(batchID).
+		WillReturnRows(sqlmock.NewRows(changeColumns).
+			AddRow(2, batchID, season, nextRoundID, AuditUpdatePlayoffs, changeValue(suite.T(), nextRound), changeValue(suite.T(), nextRoundFilled), false, time.Now()).
+			AddRow(1, batchID, season, gameID, AuditUpdatePlayoffs, changeValue(suite.T(), game), changeValue(suite.T(), gameWon), false, time.Now()))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE playoffs_id = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs(nextRoundID, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "home_team_id", "home_team_name"}).AddRow(nextRoundID, teamID, teamName))
+	suite.mock.ExpectExec(`UPDATE playoffs SET home_team_id = \$1`).
+		WithArgs(nil, nil, nil, nil, nil, nil, nil, nextRoundID, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE playoffs_id = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs(gameID, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "home_team_id", "home_team_name", "winner"}).AddRow(gameID, teamID, teamName, teamID))
+	suite.mock.ExpectExec(`UPDATE playoffs SET home_team_id = \$1`).
+		WithArgs(teamID, teamName, nil, nil, nil, nil, nil, gameID, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(`UPDATE playoffs_changes SET undone = TRUE WHERE batch_id = \$1`).
+		WithArgs(batchID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectQuery(`SELECT batch_id FROM playoffs_changes WHERE season = \$1 AND league = \$2 AND competition = \$3 AND undone = FALSE`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"batch_id"}))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, nil, AuditUndoPlayoffs, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.expectOutbox(season, AuditUndoPlayoffs)
+	suite.mock.ExpectCommit()
+
+	var notified []models.BracketChangeModel
+	suite.conn.OnChange = func(change models.BracketChangeModel) {
+		notified = append(notified, change)
+	}
+	undone, err := suite.conn.UndoPlayoffs(season, "", 5)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, undone)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+	require.Len(suite.T(), notified, 1)
+	assert.Equal(suite.T(), AuditUndoPlayoffs, notified[0].Operation)
+	assert.Equal(suite.T(), DefaultLeague, notified[0].League)
+	assert.NotEqual(suite.T(), uuid.Nil, notified[0].EventId)
+	require.Len(suite.T(), notified[0].Games, 2)
+	assert.Equal(suite.T(), &teamID, notified[0].Games[0].Before.HomeTeamId)
+	assert.Nil(suite.T(), notified[0].Games[0].After.HomeTeamId)
+}
+
+// TestUndoPlayoffs_ChangedSince tests that a game changed by a write the change log does not record is not overwritten
+func (suite *PlayoffsTestSuite) TestUndoPlayoffs_ChangedSince() {
+	season := "2023-2024"
+	batchID := uuid.New()
+	teamID, otherID := uuid.New(), uuid.New()
+	teamName, otherName := "Team1", "Team2"
+	nextRoundID := uuid.New()
+
+	nextRound := models.PlayoffsModel{PlayoffsId: nextRoundID, Season: season}
+	nextRoundFilled := nextRound
+	nextRoundFilled.HomeTeamId = &teamID
+	nextRoundFilled.HomeTeamName = &teamName
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}).AddRow(nextRoundID))
+	suite.mock.ExpectQuery(`SELECT batch_id FROM playoffs_changes`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"batch_id"}).AddRow(batchID))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs_changes WHERE batch_id = \$1`).
+		WithArgs(batchID).
+		WillReturnRows(sqlmock.NewRows(changeColumns).
+			AddRow(1, batchID, season, nextRoundID, AuditUpdatePlayoffs, changeValue(suite.T(), nextRound), changeValue(suite.T(), nextRoundFilled), false, time.Now()))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE playoffs_id = \$1`).
+		WithArgs(nextRoundID, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "home_team_id", "home_team_name"}).AddRow(nextRoundID, otherID, otherName))
+	suite.mock.ExpectRollback()
+
+	undone, err := suite.conn.UndoPlayoffs(season, "", 1)
+
+	assert.ErrorIs(suite.T(), err, ErrPlayoffsConflict)
+	assert.Equal(suite.T(), 0, undone)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRedoPlayoffs_NothingToRedo tests redo when no change was undone
+func (suite *PlayoffsTestSuite) TestRedoPlayoffs_NothingToRedo() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT batch_id FROM playoffs_changes WHERE season = \$1 AND league = \$2 AND competition = \$3 AND undone = TRUE ORDER BY change_seq ASC`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"batch_id"}))
+	suite.mock.ExpectRollback()
+
+	redone, err := suite.conn.RedoPlayoffs(season, "", 1)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, redone)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestUndoPlayoffs_InvalidSteps tests that at least one step is required
+func (suite *PlayoffsTestSuite) TestUndoPlayoffs_InvalidSteps() {
+	undone, err := suite.conn.UndoPlayoffs("2023-2024", "", 0)
+
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), 0, undone)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TESTS THE sameSlots HELPER FUNCTION
+func TestSameSlots(t *testing.T) {
+	teamID := uuid.New()
+	otherID := uuid.New()
+	name := "Team1"
+
+	a := models.PlayoffsModel{HomeTeamId: &teamID, HomeTeamName: &name}
+	b := models.PlayoffsModel{HomeTeamId: &teamID, HomeTeamName: &name, Version: 4}
+	assert.True(t, sameSlots(a, b))
+
+	b.Winner = &teamID
+	assert.False(t, sameSlots(a, b))
+
+	c := models.PlayoffsModel{HomeTeamId: &otherID, HomeTeamName: &name}
+	assert.False(t, sameSlots(a, c))
+}
@@ -0,0 +1,32 @@
+package queries
+
+import "errors"
+
+// RETURNED WHEN THE VERSION SENT BY THE CALLER NO LONGER MATCHES THE STORED ROW,
+// MEANING ANOTHER UPDATE WAS COMMITTED SINCE THE CALLER LAST READ THE BRACKET
+var ErrPlayoffsConflict = errors.New("the requested playoffs record was modified by another update, reload the playoffs and try again")
+
+// KINDS OF FAILURE CALLERS CAN TEST WITH errors.Is (THE HTTP SERVER MAPS THEM TO STATUS CODES).
+// THE ERRORS RETURNED BY THE QUERIES KEEP THEIR OWN MESSAGE AND UNWRAP TO ONE OF THESE
+var (
+	ErrNotFound      = errors.New("the requested record does not exist")
+	ErrAlreadyExists = errors.New("the requested record already exists")
+	ErrInvalidInput  = errors.New("the request is invalid")
+)
+
+type queryError struct {
+	kind    error
+	message string
+}
+
+func (e *queryError) Error() string {
+	return e.message
+}
+
+func (e *queryError) Unwrap() error {
+	return e.kind
+}
+
+func newError(kind error, message string) error {
+	return &queryError{kind: kind, message: message}
+}
@@ -0,0 +1,173 @@
+package queries
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// THE SERIES CreatePlayoffs GENERATES: BEST OF 3 UNTIL A FINAL OF ONE GAME, SEEDED BY THE POINTS OF THE STANDINGS
+const (
+	SeriesGames   = 3
+	FinalGames    = 1
+	SeedingPoints = "points"
+)
+
+// THE ARGUMENTS CreatePlayoffs RECORDS IN THE AUDIT LOG
+type createdPlayoffs struct {
+	Competition string   `json:"competition"`
+	Conferences []string `json:"conferences"`
+	Limit       int      `json:"limit"`
+}
+
+// RETURNS THE FORMAT THE BRACKET OF A COMPETITION WAS CREATED WITH. THE CONFERENCES AND LIMIT ARE READ FROM THE
+// LATEST CREATE_PLAYOFFS ENTRY OF THE AUDIT LOG, THE LENGTH OF THE SERIES FROM THE BRACKET WHEN IT STILL EXISTS
+func (p *PlayoffsDBConnection) GetPlayoffsFormat(season string, competition string) (models.PlayoffsFormatModel, error) {
+	var after []types.JSONText
+	query :=
+		`
+	SELECT after_value FROM playoffs_audit
+	WHERE season = $1 AND league = $2 AND competition = $3 AND operation = $4
+	ORDER BY created_at DESC
+	LIMIT 1
+	`
+	competition = competitionOrDefault(competition)
+	if err := p.DB.Select(&after, query, season, p.LeagueOrDefault(), competition, AuditCreatePlayoffs); err != nil {
+		log.Println("error SELECTING the creation of the playoffs: ", err.Error())
+		return models.PlayoffsFormatModel{}, err
+	}
+	if len(after) == 0 {
+		return models.PlayoffsFormatModel{}, newError(ErrNotFound, "the Playoffs "+competition+" of season "+season+" were not created by CreatePlayoffs, their format is unknown")
+	}
+	var created createdPlayoffs
+	if err := json.Unmarshal(after[0], &created); err != nil {
+		return models.PlayoffsFormatModel{}, err
+	}
+	format := models.PlayoffsFormatModel{
+		Season:      season,
+		Competition: competition,
+		Conferences: created.Conferences,
+		Limit:       created.Limit,
+		SeriesGames: SeriesGames,
+		FinalGames:  FinalGames,
+		Seeding:     SeedingPoints,
+	}
+	playoffs, err := p.ListPlayoffs(season, competition)
+	if err != nil {
+		return models.PlayoffsFormatModel{}, err
+	}
+	for _, round := range playoffs {
+		for _, series := range round {
+			if len(series) > 0 && series[0].GameCount != nil && *series[0].GameCount == "FINAL" {
+				format.FinalGames = len(series)
+			} else {
+				format.SeriesGames = len(series)
+			}
+		}
+	}
+	return format, nil
+}
+
+// CREATES THE BRACKET OF season WITH THE FORMAT OF THE SAME COMPETITION IN fromSeason, AGAINST THE STANDINGS OF
+// season, AND REPORTS WHICH TEAMS OF THE FIRST ROUND QUALIFIED AGAIN, NEWLY OR NO LONGER
+func (p *PlayoffsDBConnection) ClonePlayoffs(fromSeason string, season string, competition string) (models.CloneReportModel, error) {
+	format, err := p.GetPlayoffsFormat(fromSeason, competition)
+	if err != nil {
+		return models.CloneReportModel{}, err
+	}
+	if format.SeriesGames != SeriesGames || format.FinalGames != FinalGames {
+		return models.CloneReportModel{}, newError(ErrInvalidInput, fmt.Sprintf("the series of season %s were changed to %d games and a final of %d, CreatePlayoffs only generates %d and %d",
+			fromSeason, format.SeriesGames, format.FinalGames, SeriesGames, FinalGames))
+	}
+	previous, err := p.ListPlayoffs(fromSeason, format.Competition)
+	if err != nil {
+		return models.CloneReportModel{}, err
+	}
+	if errC := p.CreatePlayoffs(format.Conferences, season, format.Competition, format.Limit); errC != nil {
+		return models.CloneReportModel{}, errC
+	}
+	created, err := p.ListPlayoffs(season, format.Competition)
+	if err != nil {
+		return models.CloneReportModel{}, err
+	}
+	report := models.CloneReportModel{
+		FromSeason:  fromSeason,
+		Season:      season,
+		Competition: format.Competition,
+		Conferences: format.Conferences,
+		Limit:       format.Limit,
+	}
+	report.Kept, report.Added, report.Dropped = compareQualified(QualifiedTeams(previous), QualifiedTeams(created))
+	return report, nil
+}
+
+// THE TEAMS OF THE FIRST ROUND OF A BRACKET WITH THEIR SEEDS, BY SEED
+func QualifiedTeams(playoffs [][][]models.PlayoffsModel) []models.QualifiedTeamModel {
+	teams := []models.QualifiedTeamModel{}
+	if len(playoffs) == 0 {
+		return teams
+	}
+	first := playoffs[0]
+	var away []models.QualifiedTeamModel
+	for s, series := range first {
+		if len(series) == 0 {
+			continue
+		}
+		g := series[0]
+		if g.HomeTeamId != nil || g.HomeTeamName != nil {
+			teams = append(teams, qualifiedTeam(g.HomeTeamId, g.HomeTeamName, s+1))
+		}
+		if g.AwayTeamId != nil || g.AwayTeamName != nil {
+			away = append(away, qualifiedTeam(g.AwayTeamId, g.AwayTeamName, 2*len(first)-s))
+		}
+	}
+	for i := len(away) - 1; i >= 0; i-- {
+		teams = append(teams, away[i])
+	}
+	return teams
+}
+
+func qualifiedTeam(id *uuid.UUID, name *string, seed int) models.QualifiedTeamModel {
+	t := models.QualifiedTeamModel{TeamId: id, Seed: seed}
+	if name != nil {
+		t.TeamName = *name
+	}
+	return t
+}
+
+// TEAMS ARE THE SAME WHEN THEY HAVE THE SAME ID, OR THE SAME NAME WHEN THEY HAVE NO ID
+func qualifiedKey(t models.QualifiedTeamModel) string {
+	if t.TeamId != nil {
+		return t.TeamId.String()
+	}
+	return "name:" + t.TeamName
+}
+
+func compareQualified(previous []models.QualifiedTeamModel, current []models.QualifiedTeamModel) ([]models.QualifiedTeamModel, []models.QualifiedTeamModel, []models.QualifiedTeamModel) {
+	kept, added, dropped := []models.QualifiedTeamModel{}, []models.QualifiedTeamModel{}, []models.QualifiedTeamModel{}
+	seeds := map[string]int{}
+	for _, t := range previous {
+		seeds[qualifiedKey(t)] = t.Seed
+	}
+	qualified := map[string]bool{}
+	for _, t := range current {
+		qualified[qualifiedKey(t)] = true
+		if seed, ok := seeds[qualifiedKey(t)]; ok {
+			t.PreviousSeed = &seed
+			kept = append(kept, t)
+		} else {
+			added = append(added, t)
+		}
+	}
+	for _, t := range previous {
+		if !qualified[qualifiedKey(t)] {
+			dropped = append(dropped, t)
+		}
+	}
+	return kept, added, dropped
+}
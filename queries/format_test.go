@@ -0,0 +1,143 @@
+package queries
+
+import (
+	"errors"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type formatTeam struct {
+	id   uuid.UUID
+	name string
+}
+
+// EXPECTS THE QUERIES OF ListPlayoffs FOR A BRACKET OF TWO BEST OF 3 SERIES AND A FINAL, PAIRING THE TEAMS BY SEED
+func (suite *PlayoffsTestSuite) expectFormatBracket(season string, teams []formatTeam) {
+	columns := []string{"playoffs_id", "fixture_round", "game_count", "game_round", "home_team_id", "home_team_name", "away_team_id", "away_team_name", "season"}
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}).AddRow(1).AddRow(2))
+	suite.mock.ExpectQuery(`SELECT fixture_round, game_count`).
+		WithArgs(season, 1, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round", "game_count"}).AddRow(1, "1").AddRow(1, "2"))
+	for s, count := range []string{"1", "2"} {
+		home, away := teams[s], teams[len(teams)-1-s]
+		rows := sqlmock.NewRows(columns)
+		for game := 1; game <= SeriesGames; game++ {
+			rows.AddRow(uuid.New(), 1, count, game, home.id, home.name, away.id, away.name, season)
+		}
+		suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season`).
+			WithArgs(season, 1, count, DefaultLeague, DefaultCompetition).
+			WillReturnRows(rows)
+	}
+	suite.mock.ExpectQuery(`SELECT fixture_round, game_count`).
+		WithArgs(season, 2, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round", "game_count"}).AddRow(2, "FINAL"))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season`).
+		WithArgs(season, 2, "FINAL", DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(uuid.New(), 2, "FINAL", "1", nil, nil, nil, nil, season))
+}
+
+func (suite *PlayoffsTestSuite) expectCreatedFormat(season string) {
+	suite.mock.ExpectQuery(`SELECT after_value FROM playoffs_audit`).
+		WithArgs(season, DefaultLeague, DefaultCompetition, AuditCreatePlayoffs).
+		WillReturnRows(sqlmock.NewRows([]string{"after_value"}).AddRow([]byte(`{"competition":"main","conferences":["Main"],"limit":4}`)))
+}
+
+func formatTeams(names ...string) []formatTeam {
+	teams := make([]formatTeam, len(names))
+	for i, name := range names {
+		teams[i] = formatTeam{id: uuid.New(), name: name}
+	}
+	return teams
+}
+
+// TestGetPlayoffsFormat_Success tests reading the conferences and limit of the audit log and the series of the bracket
+func (suite *PlayoffsTestSuite) TestGetPlayoffsFormat_Success() {
+	season := "2023-2024"
+	suite.expectCreatedFormat(season)
+	suite.expectFormatBracket(season, formatTeams("Team1", "Team2", "Team3", "Team4"))
+
+	format, err := suite.conn.GetPlayoffsFormat(season, "")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.PlayoffsFormatModel{
+		Season: season, Competition: DefaultCompetition, Conferences: []string{"Main"}, Limit: 4,
+		SeriesGames: SeriesGames, FinalGames: FinalGames, Seeding: SeedingPoints,
+	}, format)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestGetPlayoffsFormat_NotCreated tests that a bracket imported or created before the audit log has no format
+func (suite *PlayoffsTestSuite) TestGetPlayoffsFormat_NotCreated() {
+	season := "2023-2024"
+	suite.mock.ExpectQuery(`SELECT after_value FROM playoffs_audit`).
+		WithArgs(season, DefaultLeague, "cup", AuditCreatePlayoffs).
+		WillReturnRows(sqlmock.NewRows([]string{"after_value"}))
+
+	_, err := suite.conn.GetPlayoffsFormat(season, "cup")
+
+	assert.True(suite.T(), errors.Is(err, ErrNotFound))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestClonePlayoffs_Success tests creating a season with the format of the previous one and comparing the teams
+func (suite *PlayoffsTestSuite) TestClonePlayoffs_Success() {
+	from, season := "2022-2023", "2023-2024"
+	previous := formatTeams("Team1", "Team2", "Team3", "Team4")
+	current := []formatTeam{previous[0], previous[2], previous[1], {id: uuid.New(), name: "Team5"}}
+
+	suite.expectCreatedFormat(from)
+	suite.expectFormatBracket(from, previous)
+	suite.expectFormatBracket(from, previous)
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	standings := sqlmock.NewRows([]string{"team_id", "team_name", "team_pic_url", "conference", "season", "pts", "position"})
+	for i, t := range current {
+		standings.AddRow(t.id, t.name, "", "Main", season, 100-i, i+1)
+	}
+	suite.mock.ExpectQuery(`SELECT \*, RANK\(\)`).
+		WithArgs("Main", season, 4, DefaultLeague).
+		WillReturnRows(standings)
+	for i := 0; i < 2*SeriesGames+FinalGames; i++ {
+		suite.mock.ExpectExec(`INSERT INTO playoffs`).WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, nil, AuditCreatePlayoffs, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.expectOutbox(season, AuditCreatePlayoffs)
+	suite.mock.ExpectCommit()
+	suite.expectFormatBracket(season, current)
+
+	report, err := suite.conn.ClonePlayoffs(from, season, "")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Main"}, report.Conferences)
+	assert.Equal(suite.T(), 4, report.Limit)
+	assert.Len(suite.T(), report.Kept, 3)
+	assert.Equal(suite.T(), "Team3", report.Kept[1].TeamName)
+	assert.Equal(suite.T(), 2, report.Kept[1].Seed)
+	assert.Equal(suite.T(), 3, *report.Kept[1].PreviousSeed)
+	assert.Equal(suite.T(), []models.QualifiedTeamModel{{TeamId: &current[3].id, TeamName: "Team5", Seed: 4}}, report.Added)
+	assert.Equal(suite.T(), []models.QualifiedTeamModel{{TeamId: &previous[3].id, TeamName: "Team4", Seed: 4}}, report.Dropped)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestClonePlayoffs_UnknownFormat tests that nothing is created when the previous season has no format
+func (suite *PlayoffsTestSuite) TestClonePlayoffs_UnknownFormat() {
+	suite.mock.ExpectQuery(`SELECT after_value FROM playoffs_audit`).
+		WithArgs("2022-2023", DefaultLeague, DefaultCompetition, AuditCreatePlayoffs).
+		WillReturnRows(sqlmock.NewRows([]string{"after_value"}))
+
+	_, err := suite.conn.ClonePlayoffs("2022-2023", "2023-2024", "")
+
+	assert.True(suite.T(), errors.Is(err, ErrNotFound))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
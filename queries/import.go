@@ -0,0 +1,117 @@
+package queries
+
+import (
+	"log"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/lib/pq"
+)
+
+// OPERATION RECORDED IN THE AUDIT LOG WHEN A BRACKET IS RESTORED FROM A DOCUMENT
+const AuditImportPlayoffs = "IMPORT_PLAYOFFS"
+
+// INSERTS THE GAMES OF A WHOLE BRACKET AS THEY ARE, KEEPING THEIR IDS, RESULTS, VERSIONS AND DATES, SO A BRACKET
+// EXPORTED FROM ANOTHER DATABASE COMES BACK AS IT WAS. EVERY GAME MUST BELONG TO THE SAME SEASON AND COMPETITION,
+// WHICH MUST NOT EXIST YET IN THE LEAGUE OF THE CONNECTION, AND NO GAME ID MAY BE TAKEN IN THE DATABASE
+func (p *PlayoffsDBConnection) ImportPlayoffs(games []models.PlayoffsModel) error {
+	queryCount :=
+		`
+	SELECT COUNT(*) AS count FROM playoffs WHERE season = $1 AND league = $2 AND competition = $3
+	`
+	// THE IDS ARE KEPT, SO THEY MAY ALREADY BE TAKEN BY ANOTHER BRACKET OR LEAGUE OF THE SAME DATABASE
+	queryTaken :=
+		`
+	SELECT COUNT(*) AS count FROM playoffs WHERE playoffs_id = ANY($1)
+	`
+	query :=
+		`
+	INSERT INTO playoffs
+	(playoffs_id, fixture_round, game_count, game_round, home_team_id, home_team_name, home_team_url, players_in_home_id,
+	away_team_id, away_team_name, away_team_url, players_in_away_id, season, league, competition, winner, version, scheduled_at)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+	`
+	if len(games) == 0 {
+		return newError(ErrInvalidInput, "no games to import")
+	}
+	season, competition := games[0].Season, competitionOrDefault(games[0].Competition)
+	if season == "" {
+		return newError(ErrInvalidInput, "the games have no season")
+	}
+	for i, g := range games {
+		if g.Season != season || competitionOrDefault(g.Competition) != competition {
+			return newError(ErrInvalidInput, "game "+strconv.Itoa(i+1)+" does not belong to the Playoffs "+competition+" of season "+season)
+		}
+		if g.FixtureRound == nil || g.GameCount == nil {
+			return newError(ErrInvalidInput, "game "+strconv.Itoa(i+1)+" has no round or series")
+		}
+	}
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var count int
+	if err := tx.Get(&count, queryCount, season, p.LeagueOrDefault(), competition); err != nil {
+		log.Println("error counting playoffs records: ", err.Error())
+		return err
+	}
+	if count >= 1 {
+		return newError(ErrAlreadyExists, "Cannot import the requested Playoffs "+competition+" of season "+season+", this season already exists!")
+	}
+	playoffsIds := make([]string, len(games))
+	for i, g := range games {
+		playoffsIds[i] = g.PlayoffsId.String()
+	}
+	var taken int
+	if err := tx.Get(&taken, queryTaken, pq.StringArray(playoffsIds)); err != nil {
+		log.Println("error counting taken playoffs ids: ", err.Error())
+		return err
+	}
+	if taken > 0 {
+		return newError(ErrAlreadyExists, "Cannot import the requested Playoffs "+competition+" of season "+season+", "+strconv.Itoa(taken)+" of its games already exist in this database")
+	}
+	for _, g := range games {
+		_, err := tx.Exec(
+			query,
+			g.PlayoffsId,
+			g.FixtureRound,
+			g.GameCount,
+			g.GameRound,
+			g.HomeTeamId,
+			g.HomeTeamName,
+			g.HomeTeamURL,
+			g.PlayersInHomeId,
+			g.AwayTeamId,
+			g.AwayTeamName,
+			g.AwayTeamURL,
+			g.PlayersInAwayId,
+			season,
+			p.LeagueOrDefault(),
+			competition,
+			g.Winner,
+			g.Version,
+			g.ScheduledAt,
+		)
+		if err != nil {
+			log.Println("failed to INSERT imported playoffs record: ", err.Error())
+			return err
+		}
+	}
+	imported := map[string]any{"competition": competition, "games": len(games)}
+	if err := p.writeAudit(tx, season, competition, nil, AuditImportPlayoffs, nil, imported); err != nil {
+		return err
+	}
+	change := models.BracketChangeModel{Season: season, Competition: competition, Operation: AuditImportPlayoffs}
+	if err := p.writeOutbox(tx, &change); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	p.notify(change)
+	return nil
+}
@@ -0,0 +1,106 @@
+package queries
+
+import (
+	"errors"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func importedGames(season string) []models.PlayoffsModel {
+	round, count := 0, "FINAL"
+	home, away := uuid.New(), uuid.New()
+	return []models.PlayoffsModel{{
+		PlayoffsId:   uuid.New(),
+		FixtureRound: &round,
+		GameCount:    &count,
+		GameRound:    "1",
+		HomeTeamId:   &home,
+		AwayTeamId:   &away,
+		Season:       season,
+		Winner:       &away,
+		Version:      4,
+	}}
+}
+
+// TestImportPlayoffs_Success tests restoring a bracket with its ids, results and versions
+func (suite *PlayoffsTestSuite) TestImportPlayoffs_Success() {
+	season := "2023-2024"
+	games := importedGames(season)
+	g := games[0]
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE playoffs_id = ANY\(\$1\)`).
+		WithArgs(pq.StringArray{g.PlayoffsId.String()}).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectExec(`INSERT INTO playoffs`).
+		WithArgs(g.PlayoffsId, g.FixtureRound, g.GameCount, "1", g.HomeTeamId, nil, nil, g.PlayersInHomeId,
+			g.AwayTeamId, nil, nil, g.PlayersInAwayId, season, DefaultLeague, DefaultCompetition, g.Winner, 4, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, nil, AuditImportPlayoffs, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.expectOutbox(season, AuditImportPlayoffs)
+	suite.mock.ExpectCommit()
+
+	err := suite.conn.ImportPlayoffs(games)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportPlayoffs_SeasonAlreadyExists tests that a bracket is never imported over an existing one
+func (suite *PlayoffsTestSuite) TestImportPlayoffs_SeasonAlreadyExists() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.ImportPlayoffs(importedGames(season))
+
+	assert.True(suite.T(), errors.Is(err, ErrAlreadyExists))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportPlayoffs_IdsTaken tests that games whose ids another league already uses are refused before any insert
+func (suite *PlayoffsTestSuite) TestImportPlayoffs_IdsTaken() {
+	season := "2023-2024"
+	games := importedGames(season)
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE playoffs_id = ANY\(\$1\)`).
+		WithArgs(pq.StringArray{games[0].PlayoffsId.String()}).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.ImportPlayoffs(games)
+
+	assert.True(suite.T(), errors.Is(err, ErrAlreadyExists))
+	assert.EqualError(suite.T(), err, "Cannot import the requested Playoffs main of season 2023-2024, 1 of its games already exist in this database")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportPlayoffs_MixedSeasons tests that the games of one import belong to one bracket
+func (suite *PlayoffsTestSuite) TestImportPlayoffs_MixedSeasons() {
+	games := append(importedGames("2023-2024"), importedGames("2024-2025")...)
+
+	err := suite.conn.ImportPlayoffs(games)
+
+	assert.True(suite.T(), errors.Is(err, ErrInvalidInput))
+	assert.Contains(suite.T(), err.Error(), "game 2")
+	assert.True(suite.T(), errors.Is(suite.conn.ImportPlayoffs(nil), ErrInvalidInput))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,479 @@
+package queries
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// OPERATIONS RECORDED IN THE AUDIT LOG WHEN THE NEXT ROUND SLOTS OF A BRACKET ARE REPAIRED OR REBUILT
+const (
+	AuditRepairPlayoffs    = "REPAIR_PLAYOFFS"
+	AuditRecomputePlayoffs = "RECOMPUTE_PLAYOFFS"
+)
+
+// THE RULES A BRACKET CAN BREAK
+const (
+	// A NEXT ROUND SLOT DOES NOT HOLD THE WINNER OF THE SERIES THAT FEEDS IT
+	ViolationSlot = "slot_mismatch"
+	// THE GAMES OF A SERIES ARE NOT ALL PLAYED BY THE SAME TWO TEAMS
+	ViolationSeriesTeams = "series_teams"
+	// A GAME WAS WON BY A TEAM THAT DOES NOT PLAY IT
+	ViolationWinnerNotInGame = "winner_not_in_game"
+	// A GAME HAS A WINNER ALTHOUGH ITS SERIES WAS ALREADY DECIDED
+	ViolationTooManyWinners = "too_many_winners"
+	// A ROUND DOES NOT HAVE HALF THE SERIES OF THE ROUND BEFORE
+	ViolationRoundSize = "round_size"
+	// A FINAL THAT IS NOT THE ONLY SERIES OF THE LAST ROUND
+	ViolationOrphanFinal = "orphan_final"
+	// THE LAST ROUND IS NOT A FINAL
+	ViolationMissingFinal = "missing_final"
+	// A GAME WITHOUT A fixture_round OR game_count, WHICH NO ROUND OR SERIES OF THE BRACKET HOLDS
+	ViolationUnplaced = "unplaced_game"
+)
+
+// THE TEAM IN A SLOT, NIL FOR AN EMPTY SLOT. THE ZERO UUID ALSO MEANS EMPTY, AS IN UpdatePlayoffs
+func slotId(id *uuid.UUID) *uuid.UUID {
+	if id == nil || *id == uuid.Nil {
+		return nil
+	}
+	return id
+}
+
+func isFinal(g models.PlayoffsModel) bool {
+	return g.GameCount != nil && *g.GameCount == "FINAL"
+}
+
+func seriesOf(g models.PlayoffsModel) (int, string) {
+	round, count := 0, ""
+	if g.FixtureRound != nil {
+		round = *g.FixtureRound
+	}
+	if g.GameCount != nil {
+		count = *g.GameCount
+	}
+	return round, count
+}
+
+// "R2 #9", "FINAL", OR "game <id>" FOR A GAME WITHOUT A ROUND OR SERIES
+func seriesLabel(g models.PlayoffsModel) string {
+	round, count := seriesOf(g)
+	if isFinal(g) {
+		return "FINAL"
+	}
+	if g.FixtureRound == nil || g.GameCount == nil {
+		return "game " + g.PlayoffsId.String()
+	}
+	return "R" + strconv.Itoa(round) + " #" + count
+}
+
+func violation(code string, g models.PlayoffsModel, repairable bool, format string, args ...any) models.BracketViolationModel {
+	round, count := seriesOf(g)
+	id := g.PlayoffsId
+	return models.BracketViolationModel{
+		Code:       code,
+		Round:      round,
+		Series:     count,
+		PlayoffsId: &id,
+		Message:    seriesLabel(g) + ": " + fmt.Sprintf(format, args...),
+		Repairable: repairable,
+	}
+}
+
+// A TEAM AS IT IS WRITTEN IN A SLOT
+type slotTeam struct {
+	id   uuid.UUID
+	name *string
+	url  *string
+}
+
+func (t *slotTeam) String() string {
+	if t == nil {
+		return "empty"
+	}
+	if t.name != nil && *t.name != "" {
+		return *t.name
+	}
+	return t.id.String()
+}
+
+func homeTeam(g models.PlayoffsModel) *slotTeam {
+	if id := slotId(g.HomeTeamId); id != nil {
+		return &slotTeam{id: *id, name: g.HomeTeamName, url: g.HomeTeamURL}
+	}
+	return nil
+}
+
+func awayTeam(g models.PlayoffsModel) *slotTeam {
+	if id := slotId(g.AwayTeamId); id != nil {
+		return &slotTeam{id: *id, name: g.AwayTeamName, url: g.AwayTeamURL}
+	}
+	return nil
+}
+
+func sameTeam(id *uuid.UUID, t *slotTeam) bool {
+	id = slotId(id)
+	if id == nil || t == nil {
+		return id == nil && t == nil
+	}
+	return *id == t.id
+}
+
+// THE GAMES OF A SERIES BY game_round, THE ORDER THEY ARE PLAYED IN
+func byGameRound(series []models.PlayoffsModel) []models.PlayoffsModel {
+	games := append([]models.PlayoffsModel(nil), series...)
+	sort.SliceStable(games, func(i, j int) bool {
+		a, errA := strconv.Atoi(games[i].GameRound)
+		b, errB := strconv.Atoi(games[j].GameRound)
+		if errA != nil || errB != nil {
+			return games[i].GameRound < games[j].GameRound
+		}
+		return a < b
+	})
+	return games
+}
+
+// THE TEAM THAT WON MORE THAN HALF OF THE GAMES OF A SERIES, WITH THE SLOTS OF ITS FIRST GAME AS Summarize DOES.
+// NIL WHILE THE SERIES IS NOT DECIDED
+func seriesWinner(series []models.PlayoffsModel) *slotTeam {
+	if len(series) == 0 {
+		return nil
+	}
+	home, away := homeTeam(series[0]), awayTeam(series[0])
+	wins := map[uuid.UUID]int{}
+	for _, g := range series {
+		if g.Winner != nil {
+			wins[*g.Winner]++
+		}
+	}
+	needed := len(series)/2 + 1
+	switch {
+	case home != nil && wins[home.id] >= needed:
+		return home
+	case away != nil && wins[away.id] >= needed:
+		return away
+	}
+	return nil
+}
+
+// CreatePlayoffs GIVES A BRACKET OF TWO TEAMS ITS FINAL IN ROUND 0 AND AN EMPTY FINAL AFTER IT, WHICH NOTHING FEEDS.
+// THAT EMPTY FINAL IS LEFT OUT OF THE CHECKS
+func withoutTrailingFinal(playoffs [][][]models.PlayoffsModel) [][][]models.PlayoffsModel {
+	n := len(playoffs)
+	if n < 2 || len(playoffs[n-1]) != 1 || len(playoffs[n-2]) != 1 {
+		return playoffs
+	}
+	last, previous := playoffs[n-1][0], playoffs[n-2][0]
+	if len(last) == 0 || len(previous) == 0 || !isFinal(last[0]) || !isFinal(previous[0]) {
+		return playoffs
+	}
+	for _, g := range last {
+		if slotId(g.HomeTeamId) != nil || slotId(g.AwayTeamId) != nil || g.Winner != nil {
+			return playoffs
+		}
+	}
+	return playoffs[:n-1]
+}
+
+// CHECKS EVERY RULE OF A BRACKET AS LISTED BY ListPlayoffs: THE SERIES OF A ROUND FEED THE NEXT ROUND IN PAIRS,
+// SERIES s FILLING THE HOME SLOT OF SERIES s/2 WHEN s IS EVEN AND ITS AWAY SLOT OTHERWISE, UP TO A SINGLE FINAL
+func checkBracket(playoffs [][][]models.PlayoffsModel) []models.BracketViolationModel {
+	violations := []models.BracketViolationModel{}
+	playoffs = withoutTrailingFinal(playoffs)
+	for r, round := range playoffs {
+		last := r == len(playoffs)-1
+		for _, series := range round {
+			if len(series) == 0 {
+				continue
+			}
+			violations = append(violations, checkSeries(series, r > 0, last && len(round) == 1)...)
+		}
+		if last {
+			if len(round) != 1 || len(round[0]) == 0 || !isFinal(round[0][0]) {
+				first := models.PlayoffsModel{}
+				if len(round) > 0 && len(round[0]) > 0 {
+					first = round[0][0]
+				}
+				violations = append(violations, violation(ViolationMissingFinal, first, false, "the last round has %d series and no single FINAL", len(round)))
+			}
+			continue
+		}
+		next := playoffs[r+1]
+		if len(round) != 2*len(next) {
+			violations = append(violations, violation(ViolationRoundSize, next[0][0], false, "the round has %d series but the round before has %d", len(next), len(round)))
+			continue
+		}
+		for p, series := range next {
+			home, away := seriesWinner(round[2*p]), seriesWinner(round[2*p+1])
+			for _, g := range series {
+				if !sameTeam(g.HomeTeamId, home) {
+					violations = append(violations, slotViolation(g, "home", homeTeam(g), home, round[2*p][0]))
+					break
+				}
+				if !sameTeam(g.AwayTeamId, away) {
+					violations = append(violations, slotViolation(g, "away", awayTeam(g), away, round[2*p+1][0]))
+					break
+				}
+			}
+		}
+	}
+	return violations
+}
+
+func slotViolation(g models.PlayoffsModel, slot string, actual *slotTeam, expected *slotTeam, feeder models.PlayoffsModel) models.BracketViolationModel {
+	if expected == nil {
+		return violation(ViolationSlot, g, true, "the %s slot holds %s but series %s is not decided", slot, actual, seriesLabel(feeder))
+	}
+	return violation(ViolationSlot, g, true, "the %s slot holds %s but %s won series %s", slot, actual, expected, seriesLabel(feeder))
+}
+
+// CHECKS THE GAMES OF ONE SERIES. THE TEAMS OF A SERIES THE ROUND BEFORE FEEDS ARE REPAIRED WITH ITS SLOTS, AND A
+// FINAL IS ONLY VALID AS THE SINGLE SERIES OF THE LAST ROUND
+func checkSeries(series []models.PlayoffsModel, fed bool, finalAllowed bool) []models.BracketViolationModel {
+	var violations []models.BracketViolationModel
+	games := byGameRound(series)
+	first := games[0]
+	if isFinal(first) && !finalAllowed {
+		violations = append(violations, violation(ViolationOrphanFinal, first, false, "a FINAL that is not the only series of the last round"))
+	}
+	for _, g := range games[1:] {
+		if !sameTeam(g.HomeTeamId, homeTeam(first)) || !sameTeam(g.AwayTeamId, awayTeam(first)) {
+			violations = append(violations, violation(ViolationSeriesTeams, g, fed,
+				"game %s is played by %s and %s, game %s by %s and %s", g.GameRound, homeTeam(g), awayTeam(g), first.GameRound, homeTeam(first), awayTeam(first)))
+			break
+		}
+	}
+	wins := map[uuid.UUID]int{}
+	needed := len(games)/2 + 1
+	decided := false
+	for _, g := range games {
+		if g.Winner == nil {
+			continue
+		}
+		if !sameTeam(g.Winner, homeTeam(g)) && !sameTeam(g.Winner, awayTeam(g)) {
+			violations = append(violations, violation(ViolationWinnerNotInGame, g, false, "game %s was won by %s, who does not play it", g.GameRound, g.Winner))
+			continue
+		}
+		if decided {
+			violations = append(violations, violation(ViolationTooManyWinners, g, false, "game %s has a winner but the series was already decided", g.GameRound))
+			continue
+		}
+		wins[*g.Winner]++
+		decided = wins[*g.Winner] >= needed
+	}
+	return violations
+}
+
+// RECOMPUTES EVERY NEXT ROUND SLOT FROM THE WINNERS RECORDED IN THE ROUND BEFORE, ROUND BY ROUND SO A REPAIRED SLOT
+// IS CARRIED FURTHER. WINNERS ARE NEVER CHANGED. RETURNS THE REPAIRED BRACKET AND THE GAMES IT CHANGED
+func repairBracket(playoffs [][][]models.PlayoffsModel) ([][][]models.PlayoffsModel, []models.PlayoffsModel) {
+	repaired := make([][][]models.PlayoffsModel, len(playoffs))
+	for r, round := range playoffs {
+		repaired[r] = make([][]models.PlayoffsModel, len(round))
+		for s, series := range round {
+			repaired[r][s] = append([]models.PlayoffsModel(nil), series...)
+		}
+	}
+	var changed []models.PlayoffsModel
+	checked := withoutTrailingFinal(repaired)
+	for r := 0; r+1 < len(checked); r++ {
+		round, next := checked[r], checked[r+1]
+		// THE SLOTS OF A ROUND THAT IS NOT FED IN PAIRS CAN NOT BE KNOWN, NOR THOSE OF THE ROUNDS AFTER IT
+		if len(round) != 2*len(next) {
+			break
+		}
+		for p := range next {
+			home, away := seriesWinner(round[2*p]), seriesWinner(round[2*p+1])
+			for i := range next[p] {
+				g := &next[p][i]
+				if sameTeam(g.HomeTeamId, home) && sameTeam(g.AwayTeamId, away) {
+					continue
+				}
+				g.HomeTeamId, g.HomeTeamName, g.HomeTeamURL = nil, nil, nil
+				if home != nil {
+					id := home.id
+					g.HomeTeamId, g.HomeTeamName, g.HomeTeamURL = &id, home.name, home.url
+				}
+				g.AwayTeamId, g.AwayTeamName, g.AwayTeamURL = nil, nil, nil
+				if away != nil {
+					id := away.id
+					g.AwayTeamId, g.AwayTeamName, g.AwayTeamURL = &id, away.name, away.url
+				}
+				changed = append(changed, *g)
+			}
+		}
+	}
+	return repaired, changed
+}
+
+// GROUPS THE GAMES OF A BRACKET AS groupPlayoffs DOES AND CHECKS IT. A GAME WITHOUT A ROUND OR SERIES IS LEFT OUT OF
+// THE BRACKET AND REPORTED ON ITS OWN, SINCE NEITHER A REPAIR NOR A REBUILD CAN TELL WHERE IT BELONGS
+func checkGames(games []models.PlayoffsModel) ([][][]models.PlayoffsModel, []models.BracketViolationModel) {
+	var placed []models.PlayoffsModel
+	unplaced := []models.BracketViolationModel{}
+	for _, g := range games {
+		if g.FixtureRound == nil || g.GameCount == nil {
+			unplaced = append(unplaced, violation(ViolationUnplaced, g, false, "game %s has no fixture_round or game_count", g.GameRound))
+			continue
+		}
+		placed = append(placed, g)
+	}
+	playoffs := groupPlayoffs(placed)
+	return playoffs, append(unplaced, checkBracket(playoffs)...)
+}
+
+// THE VIOLATIONS OF THE UNPLACED GAMES
+func unplacedViolations(violations []models.BracketViolationModel) []models.BracketViolationModel {
+	unplaced := []models.BracketViolationModel{}
+	for _, v := range violations {
+		if v.Code == ViolationUnplaced {
+			unplaced = append(unplaced, v)
+		}
+	}
+	return unplaced
+}
+
+// THE GAMES OF A BRACKET THAT IS NOT ARCHIVED, IN THE ORDER OF ListPlayoffs. UNLIKE ListPlayoffs THE GAMES WITHOUT A
+// fixture_round OR game_count ARE READ TOO, SO checkGames CAN REPORT THEM
+func selectBracket(q sqlx.Queryer, season string, league string, competition string) ([]models.PlayoffsModel, error) {
+	var games []models.PlayoffsModel
+	query :=
+		`
+	SELECT * FROM playoffs
+	WHERE season = $1 AND league = $2 AND competition = $3 AND archived_at IS NULL
+	ORDER BY fixture_round,
+	 CASE
+		WHEN game_count ~ '^\d+$' THEN CAST(game_count AS integer)
+	 ELSE NULL
+	 END ASC,
+	game_count ASC, game_round ASC
+	`
+	if err := sqlx.Select(q, &games, query, season, league, competition); err != nil {
+		log.Println("error SELECTING the playoffs of the bracket: ", err.Error())
+		return nil, err
+	}
+	return games, nil
+}
+
+func integrityReport(season string, competition string, violations []models.BracketViolationModel) models.BracketIntegrityModel {
+	return models.BracketIntegrityModel{
+		Season:      season,
+		Competition: competition,
+		Violations:  violations,
+		Repaired:    []models.GameChangeModel{},
+		Remaining:   violations,
+		Valid:       len(violations) == 0,
+	}
+}
+
+// CHECKS THE BRACKET OF A COMPETITION AND REPORTS EVERY RULE IT BREAKS, WITHOUT CHANGING IT
+func (p *PlayoffsDBConnection) ValidatePlayoffs(season string, competition string) (models.BracketIntegrityModel, error) {
+	competition = competitionOrDefault(competition)
+	games, err := selectBracket(p.DB, season, p.LeagueOrDefault(), competition)
+	if err != nil {
+		return models.BracketIntegrityModel{}, err
+	}
+	if len(games) == 0 {
+		return models.BracketIntegrityModel{}, newError(ErrNotFound, "the Playoffs "+competition+" of season "+season+" do not exist or are archived")
+	}
+	_, violations := checkGames(games)
+	return integrityReport(season, competition, violations), nil
+}
+
+// RECOMPUTES THE NEXT ROUND SLOTS OF THE BRACKET FROM ITS RECORDED WINNERS. THE CHANGED GAMES ARE WRITTEN TO THE
+// CHANGE LOG, SO UndoPlayoffs REVERTS A REPAIR LIKE A RESULT ENTRY. VIOLATIONS A REPAIR CAN NOT FIX ARE REPORTED IN
+// Remaining AND LEFT FOR A RESULT ENTRY OR A REVERT
+func (p *PlayoffsDBConnection) RepairPlayoffs(season string, competition string) (models.BracketIntegrityModel, error) {
+	return p.rebuildSlots(season, competition, AuditRepairPlayoffs, false)
+}
+
+// REBUILDS EVERY SLOT AFTER THE FIRST ROUND FROM THE FIRST ROUND PAIRINGS AND THE WINNERS OF THE GAMES ONLY, WHATEVER
+// THE LATER ROUNDS HOLD, E.G. AFTER RESULTS WERE WRITTEN WITHOUT UpdatePlayoffs. UNLIKE RepairPlayoffs THE BRACKET
+// IS REFUSED WITH ErrInvalidInput WHEN ITS ROUNDS DO NOT FEED EACH OTHER IN PAIRS UP TO A FINAL, ITS FIRST ROUND
+// SERIES ARE NOT PLAYED BY TWO TEAMS OR A GAME HAS NO ROUND OR SERIES, SINCE THE LATER ROUNDS CAN THEN NOT BE KNOWN
+func (p *PlayoffsDBConnection) RecomputePlayoffs(season string, competition string) (models.BracketIntegrityModel, error) {
+	return p.rebuildSlots(season, competition, AuditRecomputePlayoffs, true)
+}
+
+// THE FIRST VIOLATION THAT KEEPS THE LATER ROUNDS OF A BRACKET FROM BEING REBUILT FROM ITS FIRST ROUND
+func shapeViolation(violations []models.BracketViolationModel, firstRound int) *models.BracketViolationModel {
+	for i, v := range violations {
+		switch v.Code {
+		case ViolationRoundSize, ViolationOrphanFinal, ViolationMissingFinal, ViolationUnplaced:
+			return &violations[i]
+		case ViolationSeriesTeams:
+			if v.Round == firstRound {
+				return &violations[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (p *PlayoffsDBConnection) rebuildSlots(season string, competition string, operation string, requireShape bool) (models.BracketIntegrityModel, error) {
+	league := p.LeagueOrDefault()
+	competition = competitionOrDefault(competition)
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return models.BracketIntegrityModel{}, errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if err := lockSeason(tx, league, competition, season); err != nil {
+		return models.BracketIntegrityModel{}, err
+	}
+	games, err := selectBracket(tx, season, league, competition)
+	if err != nil {
+		return models.BracketIntegrityModel{}, err
+	}
+	if len(games) == 0 {
+		return models.BracketIntegrityModel{}, newError(ErrNotFound, "the Playoffs "+competition+" of season "+season+" do not exist or are archived")
+	}
+	playoffs, violations := checkGames(games)
+	report := integrityReport(season, competition, violations)
+	if requireShape {
+		firstRound, _ := seriesOf(games[0])
+		if v := shapeViolation(report.Violations, firstRound); v != nil {
+			return models.BracketIntegrityModel{}, newError(ErrInvalidInput, "the rounds can not be rebuilt: "+v.Message)
+		}
+	}
+	repaired, changed := repairBracket(playoffs)
+	if len(changed) == 0 {
+		return report, nil
+	}
+	for _, g := range changed {
+		if err := writeSlots(tx, league, competition, g); err != nil {
+			return models.BracketIntegrityModel{}, err
+		}
+	}
+	after, errS := snapshotSeason(tx, league, competition, season, 0)
+	if errS != nil {
+		return models.BracketIntegrityModel{}, errS
+	}
+	changes, errCh := recordChanges(tx, league, competition, season, operation, games, after)
+	if errCh != nil {
+		return models.BracketIntegrityModel{}, errCh
+	}
+	report.Repaired = changes
+	report.Remaining = append(unplacedViolations(report.Violations), checkBracket(repaired)...)
+	report.Valid = len(report.Remaining) == 0
+	if errAudit := p.writeAudit(tx, season, competition, nil, operation, report.Violations, report.Remaining); errAudit != nil {
+		return models.BracketIntegrityModel{}, errAudit
+	}
+	change := models.BracketChangeModel{Season: season, Competition: competition, Operation: operation, Games: changes}
+	if errO := p.writeOutbox(tx, &change); errO != nil {
+		return models.BracketIntegrityModel{}, errO
+	}
+	if errC := tx.Commit(); errC != nil {
+		return models.BracketIntegrityModel{}, errC
+	}
+	p.notify(change)
+	return report, nil
+}
@@ -0,0 +1,329 @@
+package queries
+
+import (
+	"errors"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// THE GAMES OF A SERIES BETWEEN home AND away, WON BY winners IN THAT ORDER. A NIL TEAM LEAVES ITS SLOT EMPTY
+func integritySeries(round int, count string, home *formatTeam, away *formatTeam, games int, winners ...*formatTeam) []models.PlayoffsModel {
+	series := make([]models.PlayoffsModel, games)
+	for i := range series {
+		r, c := round, count
+		series[i] = models.PlayoffsModel{PlayoffsId: uuid.New(), FixtureRound: &r, GameCount: &c, GameRound: strconv.Itoa(i + 1), Season: "2023-2024"}
+		if home != nil {
+			series[i].HomeTeamId, series[i].HomeTeamName = &home.id, &home.name
+		}
+		if away != nil {
+			series[i].AwayTeamId, series[i].AwayTeamName = &away.id, &away.name
+		}
+		if i < len(winners) && winners[i] != nil {
+			series[i].Winner = &winners[i].id
+		}
+	}
+	return series
+}
+
+// A BRACKET OF FOUR TEAMS WHERE THE FIRST SERIES IS WON BY a AND THE SECOND IS NOT DECIDED
+func integrityBracket() ([][][]models.PlayoffsModel, []formatTeam) {
+	t := formatTeams("A", "B", "C", "D")
+	a, b, c, d := &t[0], &t[1], &t[2], &t[3]
+	return [][][]models.PlayoffsModel{
+		{integritySeries(1, "1", a, d, 3, a, a), integritySeries(1, "2", b, c, 3, b)},
+		{integritySeries(2, "FINAL", a, nil, 1)},
+	}, t
+}
+
+func violationCodes(violations []models.BracketViolationModel) []string {
+	codes := []string{}
+	for _, v := range violations {
+		codes = append(codes, v.Code)
+	}
+	return codes
+}
+
+// TestCheckBracket_Valid tests that a bracket filled by UpdatePlayoffs and a bracket of two teams have no violations
+func (suite *PlayoffsTestSuite) TestCheckBracket_Valid() {
+	playoffs, t := integrityBracket()
+	assert.Empty(suite.T(), checkBracket(playoffs))
+
+	twoTeams := [][][]models.PlayoffsModel{
+		{integritySeries(0, "FINAL", &t[0], &t[1], 1)},
+		{integritySeries(1, "FINAL", nil, nil, 1)},
+	}
+	assert.Empty(suite.T(), checkBracket(twoTeams))
+}
+
+// TestCheckBracket_TeamThatNeverWon tests a slot holding a team that did not win the series feeding it
+func (suite *PlayoffsTestSuite) TestCheckBracket_TeamThatNeverWon() {
+	playoffs, t := integrityBracket()
+	playoffs[1][0][0].AwayTeamId, playoffs[1][0][0].AwayTeamName = &t[2].id, &t[2].name
+
+	violations := checkBracket(playoffs)
+
+	if assert.Len(suite.T(), violations, 1) {
+		assert.Equal(suite.T(), ViolationSlot, violations[0].Code)
+		assert.True(suite.T(), violations[0].Repairable)
+		assert.Equal(suite.T(), "FINAL: the away slot holds C but series R1 #2 is not decided", violations[0].Message)
+	}
+}
+
+// TestCheckBracket_ThreeWinners tests a series whose last game has a winner after a team won the first two
+func (suite *PlayoffsTestSuite) TestCheckBracket_ThreeWinners() {
+	playoffs, t := integrityBracket()
+	playoffs[0][0][2].Winner = &t[3].id
+
+	violations := checkBracket(playoffs)
+
+	assert.Equal(suite.T(), []string{ViolationTooManyWinners}, violationCodes(violations))
+	assert.Equal(suite.T(), playoffs[0][0][2].PlayoffsId, *violations[0].PlayoffsId)
+	assert.False(suite.T(), violations[0].Repairable)
+}
+
+// TestCheckBracket_OrphanFinal tests a FINAL left in a round that is not the last
+func (suite *PlayoffsTestSuite) TestCheckBracket_OrphanFinal() {
+	playoffs, t := integrityBracket()
+	playoffs[0] = append(playoffs[0], integritySeries(1, "FINAL", &t[0], &t[1], 1))
+
+	violations := checkBracket(playoffs)
+
+	assert.Equal(suite.T(), []string{ViolationOrphanFinal, ViolationRoundSize}, violationCodes(violations))
+}
+
+// TestRepairBracket_CarriesRepairedSlots tests that a repaired slot is carried to the rounds after it
+func (suite *PlayoffsTestSuite) TestRepairBracket_CarriesRepairedSlots() {
+	t := formatTeams("A", "B", "C", "D", "E", "F", "G", "H")
+	a, b, c, d, e, f, g, h := &t[0], &t[1], &t[2], &t[3], &t[4], &t[5], &t[6], &t[7]
+	playoffs := [][][]models.PlayoffsModel{
+		{integritySeries(1, "1", a, h, 3, a, a), integritySeries(1, "2", b, g, 3, b, b), integritySeries(1, "3", c, f, 3), integritySeries(1, "4", d, e, 3)},
+		// H NEVER WON ITS SERIES BUT WAS ADVANCED, AND THEN WON THIS ONE
+		{integritySeries(2, "5", h, b, 3, b, b), integritySeries(2, "6", nil, nil, 3)},
+		{integritySeries(3, "FINAL", nil, nil, 1)},
+	}
+
+	repaired, changed := repairBracket(playoffs)
+
+	assert.Len(suite.T(), changed, 4, "the three games of R2 #5 and the final")
+	assert.Equal(suite.T(), a.id, *repaired[1][0][2].HomeTeamId)
+	assert.Equal(suite.T(), b.id, *repaired[2][0][0].HomeTeamId)
+	assert.Equal(suite.T(), h.id, *playoffs[1][0][0].HomeTeamId, "the listed bracket is not changed")
+	assert.Empty(suite.T(), checkBracket(repaired))
+}
+
+// TestValidatePlayoffs_NotFound tests that validating a season without a bracket is answered with ErrNotFound
+func (suite *PlayoffsTestSuite) TestValidatePlayoffs_NotFound() {
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs\s+WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs("2023-2024", DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+
+	_, err := suite.conn.ValidatePlayoffs("2023-2024", "")
+
+	assert.True(suite.T(), errors.Is(err, ErrNotFound))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// THE ROWS OF THE GAMES OF playoffs FOLLOWED BY unplaced, WHOSE fixture_round AND game_count ARE NULL
+func integrityRows(playoffs [][][]models.PlayoffsModel, unplaced ...models.PlayoffsModel) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "game_round", "home_team_id", "home_team_name", "away_team_id", "away_team_name", "season", "winner", "version"})
+	for _, round := range playoffs {
+		for _, series := range round {
+			for _, g := range series {
+				rows.AddRow(g.PlayoffsId, *g.FixtureRound, *g.GameCount, g.GameRound, g.HomeTeamId, g.HomeTeamName, g.AwayTeamId, g.AwayTeamName, g.Season, g.Winner, 1)
+			}
+		}
+	}
+	for _, g := range unplaced {
+		rows.AddRow(g.PlayoffsId, nil, nil, g.GameRound, g.HomeTeamId, g.HomeTeamName, g.AwayTeamId, g.AwayTeamName, g.Season, g.Winner, 1)
+	}
+	return rows
+}
+
+// TestValidatePlayoffs_UnplacedGame tests that a game without a round or series is reported instead of grouped
+func (suite *PlayoffsTestSuite) TestValidatePlayoffs_UnplacedGame() {
+	playoffs, t := integrityBracket()
+	unplaced := integritySeries(1, "3", &t[0], &t[2], 1)[0]
+
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs\s+WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs("2023-2024", DefaultLeague, DefaultCompetition).
+		WillReturnRows(integrityRows(playoffs, unplaced))
+
+	report, err := suite.conn.ValidatePlayoffs("2023-2024", "")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{ViolationUnplaced}, violationCodes(report.Violations))
+	assert.Equal(suite.T(), "game "+unplaced.PlayoffsId.String()+": game 1 has no fixture_round or game_count", report.Violations[0].Message)
+	assert.False(suite.T(), report.Violations[0].Repairable)
+	assert.False(suite.T(), report.Valid)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRepairPlayoffs_UnplacedGame tests that a game without a round or series is left alone and remains reported
+func (suite *PlayoffsTestSuite) TestRepairPlayoffs_UnplacedGame() {
+	season := "2023-2024"
+	playoffs, t := integrityBracket()
+	unplaced := integritySeries(1, "3", &t[0], &t[2], 1)[0]
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs\s+WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(integrityRows(playoffs, unplaced))
+	suite.mock.ExpectRollback()
+
+	report, err := suite.conn.RepairPlayoffs(season, "")
+
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), report.Repaired)
+	assert.Equal(suite.T(), []string{ViolationUnplaced}, violationCodes(report.Remaining))
+	assert.False(suite.T(), report.Valid)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRepairPlayoffs_Success tests that the repaired slots are written, recorded for undo, audited and published
+func (suite *PlayoffsTestSuite) TestRepairPlayoffs_Success() {
+	season := "2023-2024"
+	playoffs, t := integrityBracket()
+	final := playoffs[1][0][0]
+	playoffs[1][0][0].HomeTeamId, playoffs[1][0][0].HomeTeamName = &t[3].id, &t[3].name
+	repaired, _ := integrityBracket()
+	repaired[1][0][0] = final
+	repaired[1][0][0].HomeTeamId, repaired[1][0][0].HomeTeamName = &t[0].id, &t[0].name
+	for r := range repaired[0] {
+		repaired[0][r] = playoffs[0][r]
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3 ORDER BY playoffs_id FOR UPDATE`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs\s+WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(integrityRows(playoffs))
+	suite.mock.ExpectExec(`UPDATE playoffs\s+SET home_team_id = \$1`).
+		WithArgs(&t[0].id, &t[0].name, nil, nil, nil, nil, nil, final.PlayoffsId, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round >= \$2`).
+		WithArgs(season, 0, DefaultLeague, DefaultCompetition).
+		WillReturnRows(integrityRows(repaired))
+	suite.mock.ExpectExec(`DELETE FROM playoffs_changes`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_changes`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, final.PlayoffsId, AuditRepairPlayoffs, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, nil, AuditRepairPlayoffs, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.expectOutbox(season, AuditRepairPlayoffs)
+	suite.mock.ExpectCommit()
+
+	report, err := suite.conn.RepairPlayoffs(season, "")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{ViolationSlot}, violationCodes(report.Violations))
+	if assert.Len(suite.T(), report.Repaired, 1) {
+		assert.Equal(suite.T(), t[3].id, *report.Repaired[0].Before.HomeTeamId)
+		assert.Equal(suite.T(), t[0].id, *report.Repaired[0].After.HomeTeamId)
+	}
+	assert.Empty(suite.T(), report.Remaining)
+	assert.True(suite.T(), report.Valid)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRecomputePlayoffs_ResultsWrittenDirectly tests that a final left empty by results written without UpdatePlayoffs
+// is filled with the winners of both series
+func (suite *PlayoffsTestSuite) TestRecomputePlayoffs_ResultsWrittenDirectly() {
+	season := "2023-2024"
+	playoffs, t := integrityBracket()
+	playoffs[0][1][1].Winner = &t[1].id
+	final := playoffs[1][0][0]
+	playoffs[1][0][0].HomeTeamId, playoffs[1][0][0].HomeTeamName = nil, nil
+	rebuilt, _ := repairBracket(playoffs)
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs\s+WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(integrityRows(playoffs))
+	suite.mock.ExpectExec(`UPDATE playoffs\s+SET home_team_id = \$1`).
+		WithArgs(&t[0].id, &t[0].name, nil, &t[1].id, &t[1].name, nil, nil, final.PlayoffsId, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round >= \$2`).
+		WithArgs(season, 0, DefaultLeague, DefaultCompetition).
+		WillReturnRows(integrityRows(rebuilt))
+	suite.mock.ExpectExec(`DELETE FROM playoffs_changes`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_changes`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, final.PlayoffsId, AuditRecomputePlayoffs, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, nil, AuditRecomputePlayoffs, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.expectOutbox(season, AuditRecomputePlayoffs)
+	suite.mock.ExpectCommit()
+
+	report, err := suite.conn.RecomputePlayoffs(season, "")
+
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), report.Repaired, 1) {
+		assert.Nil(suite.T(), report.Repaired[0].Before.HomeTeamId)
+		assert.Equal(suite.T(), t[1].id, *report.Repaired[0].After.AwayTeamId)
+	}
+	assert.True(suite.T(), report.Valid)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRecomputePlayoffs_RoundSize tests that a bracket whose rounds do not feed each other in pairs is refused unchanged
+func (suite *PlayoffsTestSuite) TestRecomputePlayoffs_RoundSize() {
+	season := "2023-2024"
+	playoffs, t := integrityBracket()
+	playoffs[0] = append(playoffs[0], integritySeries(1, "3", &t[0], &t[2], 3))
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs\s+WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(integrityRows(playoffs))
+	suite.mock.ExpectRollback()
+
+	_, err := suite.conn.RecomputePlayoffs(season, "")
+
+	assert.True(suite.T(), errors.Is(err, ErrInvalidInput))
+	assert.EqualError(suite.T(), err, "the rounds can not be rebuilt: FINAL: the round has 1 series but the round before has 3")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRecomputePlayoffs_UnplacedGame tests that a bracket with a game without a round or series is refused unchanged
+func (suite *PlayoffsTestSuite) TestRecomputePlayoffs_UnplacedGame() {
+	season := "2023-2024"
+	playoffs, t := integrityBracket()
+	unplaced := integritySeries(1, "3", &t[0], &t[2], 1)[0]
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs\s+WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(integrityRows(playoffs, unplaced))
+	suite.mock.ExpectRollback()
+
+	_, err := suite.conn.RecomputePlayoffs(season, "")
+
+	assert.True(suite.T(), errors.Is(err, ErrInvalidInput))
+	assert.EqualError(suite.T(), err, "the rounds can not be rebuilt: game "+unplaced.PlayoffsId.String()+": game 1 has no fixture_round or game_count")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,32 @@
+package queries
+
+// LEAGUE USED WHEN THE CONNECTION WAS NOT SCOPED WITH ForLeague. EVERY ROW CREATED BEFORE
+// LEAGUES EXISTED BELONGS TO IT
+const DefaultLeague = "default"
+
+// RETURNS A COPY OF THE CONNECTION WHOSE QUERIES ONLY READ AND WRITE THE ROWS OF THE GIVEN LEAGUE,
+// SO TWO LEAGUES CAN USE THE SAME SEASON NAMES IN ONE DATABASE. THE UNDERLYING DATABASE POOL IS SHARED
+func (p *PlayoffsDBConnection) ForLeague(league string) *PlayoffsDBConnection {
+	c := *p
+	c.League = league
+	return &c
+}
+
+// THE LEAGUE THE QUERIES OF THE CONNECTION READ AND WRITE: THE ONE GIVEN TO ForLeague, OR DefaultLeague
+func (p *PlayoffsDBConnection) LeagueOrDefault() string {
+	if p.League == "" {
+		return DefaultLeague
+	}
+	return p.League
+}
+
+// COMPETITION USED WHEN NONE IS GIVEN. ONE SEASON CAN HOLD SEVERAL INDEPENDENT BRACKETS
+// (MAIN PLAYOFFS, CONSOLATION CUP, WOMEN'S BRACKET...), EACH ONE NAMED BY ITS COMPETITION
+const DefaultCompetition = "main"
+
+func competitionOrDefault(competition string) string {
+	if competition == "" {
+		return DefaultCompetition
+	}
+	return competition
+}
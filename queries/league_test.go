@@ -0,0 +1,99 @@
+package queries
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestForLeague_SameSeasonInAnotherLeague tests that the season check only looks at the rows of the connection's league
+func (suite *PlayoffsTestSuite) TestForLeague_SameSeasonInAnotherLeague() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3`).
+		WithArgs(season, "north", DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.ForLeague("north").CreatePlayoffs([]string{"A", "B", "C"}, season, "", 8)
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "invalid number of conferences")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestForLeague_UpdateOtherLeagueGame tests that a game of another league cannot be updated
+func (suite *PlayoffsTestSuite) TestForLeague_UpdateOtherLeagueGame() {
+	playoffsID := uuid.New()
+	teamID := uuid.New()
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round >= \$2 AND league = \$3 AND competition = \$4`).
+		WithArgs(season, 1, "south", DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT p\.\* FROM playoffs p`).
+		WithArgs(playoffsID, "south", DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectExec(`UPDATE playoffs SET winner = \$1, version = version \+ 1 WHERE playoffs_id = \$2 AND version = \$3 AND league = \$4 AND competition = \$5`).
+		WithArgs(teamID, playoffsID, 0, "south", DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.ForLeague("south").UpdatePlayoffs(playoffsID, PlayoffsModelReqQuery{
+		FixtureRound: 1,
+		GameCount:    "1",
+		Season:       season,
+		Winner:       teamID,
+	})
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "failed to update the requested row")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestForLeague_DefaultLeague tests the league used by an unscoped connection
+func (suite *PlayoffsTestSuite) TestForLeague_DefaultLeague() {
+	assert.Equal(suite.T(), DefaultLeague, suite.conn.LeagueOrDefault())
+	assert.Equal(suite.T(), "north", suite.conn.ForLeague("north").LeagueOrDefault())
+	assert.Equal(suite.T(), "admin", suite.conn.ForLeague("north").WithActor("admin").Actor)
+}
+
+// TestCreatePlayoffs_SecondCompetitionInSeason tests that the season check only looks at the rows of the requested competition
+func (suite *PlayoffsTestSuite) TestCreatePlayoffs_SecondCompetitionInSeason() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3`).
+		WithArgs(season, DefaultLeague, "cup").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.CreatePlayoffs([]string{"A", "B", "C"}, season, "cup", 8)
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "invalid number of conferences")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestListPlayoffs_OtherCompetition tests that listing one competition does not return the games of another
+func (suite *PlayoffsTestSuite) TestListPlayoffs_OtherCompetition() {
+	season := "2023-2024"
+
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs(season, DefaultLeague, "cup").
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}))
+
+	result, err := suite.conn.ListPlayoffs(season, "cup")
+
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), result)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCompetitionOrDefault tests the competition used when none is given
+func (suite *PlayoffsTestSuite) TestCompetitionOrDefault() {
+	assert.Equal(suite.T(), DefaultCompetition, competitionOrDefault(""))
+	assert.Equal(suite.T(), "cup", competitionOrDefault("cup"))
+}
@@ -0,0 +1,112 @@
+package queries
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// WRITES A CHANGE TO THE OUTBOX INSIDE THE CALLER'S TRANSACTION SO IT IS PUBLISHED IF AND ONLY IF THE MUTATION
+// COMMITS. IT SETS THE EventId, LEAGUE AND ACTOR OF change, WHICH IS THEN HANDED TO notify AFTER THE COMMIT
+func (p *PlayoffsDBConnection) writeOutbox(tx *sqlx.Tx, change *models.BracketChangeModel) error {
+	query :=
+		`
+	INSERT INTO outbox
+	(event_id, league, season, competition, operation, payload)
+	VALUES($1, $2, $3, $4, $5, $6)
+	`
+	change.EventId = uuid.New()
+	change.League = p.LeagueOrDefault()
+	change.Actor = p.actor()
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(query, change.EventId, change.League, change.Season, change.Competition, change.Operation, types.JSONText(payload))
+	if err != nil {
+		log.Println("failed to INSERT outbox record: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// CLAIMS UP TO limit UNPUBLISHED CHANGES OF EVERY LEAGUE, OLDEST FIRST, THAT WERE TRIED FEWER THAN maxAttempts TIMES.
+// A CLAIMED CHANGE IS HIDDEN FROM THE OTHER RELAYS FOR lease. WHEN IT IS NEITHER PUBLISHED NOR FAILED BY THEN,
+// BECAUSE THE RELAY STOPPED, IT IS CLAIMED AGAIN
+func (p *PlayoffsDBConnection) ClaimOutbox(limit int, maxAttempts int, lease time.Duration) ([]models.OutboxModel, error) {
+	claimed := []models.OutboxModel{}
+	query :=
+		`
+	UPDATE outbox
+	SET attempts = attempts + 1, available_at = NOW() + $3 * INTERVAL '1 millisecond'
+	WHERE outbox_id IN (
+		SELECT outbox_id FROM outbox
+		WHERE published_at IS NULL
+		AND available_at <= NOW()
+		AND attempts < $2
+		ORDER BY outbox_id ASC
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+	`
+	err := p.DB.Select(&claimed, query, limit, maxAttempts, lease.Milliseconds())
+	if err != nil {
+		log.Println("failed to claim outbox records: ", err.Error())
+		return []models.OutboxModel{}, err
+	}
+	// RETURNING DOES NOT KEEP THE ORDER OF THE SUBQUERY
+	sort.Slice(claimed, func(i, j int) bool {
+		return claimed[i].OutboxId < claimed[j].OutboxId
+	})
+	return claimed, nil
+}
+
+func (p *PlayoffsDBConnection) MarkOutboxPublished(outboxId int64) error {
+	query :=
+		`
+	UPDATE outbox SET published_at = NOW(), last_error = NULL WHERE outbox_id = $1
+	`
+	_, err := p.DB.Exec(query, outboxId)
+	if err != nil {
+		log.Println("failed to UPDATE outbox record: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// RECORDS WHY A CLAIMED CHANGE COULD NOT BE PUBLISHED AND MAKES IT AVAILABLE AGAIN AFTER retryAfter
+func (p *PlayoffsDBConnection) FailOutbox(outboxId int64, message string, retryAfter time.Duration) error {
+	query :=
+		`
+	UPDATE outbox SET last_error = $2, available_at = NOW() + $3 * INTERVAL '1 millisecond' WHERE outbox_id = $1
+	`
+	_, err := p.DB.Exec(query, outboxId, message, retryAfter.Milliseconds())
+	if err != nil {
+		log.Println("failed to UPDATE outbox record: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// DELETES THE CHANGES PUBLISHED MORE THAN olderThan AGO. CHANGES THAT EXHAUSTED THEIR ATTEMPTS ARE KEPT
+// WITH THEIR last_error UNTIL THEY ARE DEALT WITH BY HAND
+func (p *PlayoffsDBConnection) PruneOutbox(olderThan time.Duration) (int64, error) {
+	query :=
+		`
+	DELETE FROM outbox WHERE published_at < NOW() - $1 * INTERVAL '1 millisecond'
+	`
+	sqlRow, err := p.DB.Exec(query, olderThan.Milliseconds())
+	if err != nil {
+		log.Println("failed to DELETE outbox records: ", err.Error())
+		return 0, err
+	}
+	return sqlRow.RowsAffected()
+}
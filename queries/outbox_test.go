@@ -0,0 +1,75 @@
+package queries
+
+import (
+	"errors"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// EXPECTS THE CHANGE EVERY COMMITTED MUTATION WRITES TO THE OUTBOX BEFORE COMMITTING
+func (suite *PlayoffsTestSuite) expectOutbox(season string, operation string) {
+	suite.mock.ExpectExec(`INSERT INTO outbox \(event_id, league, season, competition, operation, payload\)`).
+		WithArgs(sqlmock.AnyArg(), DefaultLeague, season, DefaultCompetition, operation, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// TestDeletePlayoffs_OutboxFailureRollsBack tests that a mutation is not committed when its change can not be written to the outbox
+func (suite *PlayoffsTestSuite) TestDeletePlayoffs_OutboxFailureRollsBack() {
+	season := "2023-2024"
+	notified := false
+	suite.conn.OnChange = func(_ models.BracketChangeModel) {
+		notified = true
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`UPDATE playoffs SET archived_at = NOW\(\)`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO outbox`).
+		WillReturnError(errors.New("disk full"))
+	suite.mock.ExpectRollback()
+
+	err := suite.conn.DeletePlayoffs(season, "")
+
+	assert.Error(suite.T(), err)
+	assert.False(suite.T(), notified)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestClaimOutbox_OrderedById tests that the claimed changes are returned oldest first
+func (suite *PlayoffsTestSuite) TestClaimOutbox_OrderedById() {
+	columns := []string{"outbox_id", "event_id", "league", "season", "competition", "operation", "payload", "attempts", "available_at", "last_error", "published_at", "created_at"}
+
+	suite.mock.ExpectQuery(`UPDATE outbox SET attempts = attempts \+ 1, available_at = NOW\(\) \+ \$3 \* INTERVAL '1 millisecond' WHERE outbox_id IN \( SELECT outbox_id FROM outbox WHERE published_at IS NULL AND available_at <= NOW\(\) AND attempts < \$2 ORDER BY outbox_id ASC LIMIT \$1 FOR UPDATE SKIP LOCKED \) RETURNING \*`).
+		WithArgs(100, 10, int64(60000)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(8, uuid.New(), DefaultLeague, "2023-2024", DefaultCompetition, AuditUpdatePlayoffs, `{}`, 1, time.Now(), nil, nil, time.Now()).
+			AddRow(3, uuid.New(), DefaultLeague, "2023-2024", DefaultCompetition, AuditCreatePlayoffs, `{}`, 2, time.Now(), "timeout", nil, time.Now()))
+
+	claimed, err := suite.conn.ClaimOutbox(100, 10, time.Minute)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), claimed, 2)
+	assert.Equal(suite.T(), int64(3), claimed[0].OutboxId)
+	assert.Equal(suite.T(), "timeout", *claimed[0].LastError)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestFailOutbox_Success tests that a failed change is made available again after the backoff
+func (suite *PlayoffsTestSuite) TestFailOutbox_Success() {
+	suite.mock.ExpectExec(`UPDATE outbox SET last_error = \$2, available_at = NOW\(\) \+ \$3 \* INTERVAL '1 millisecond' WHERE outbox_id = \$1`).
+		WithArgs(int64(3), "webhook answered 503", int64(4000)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.conn.FailOutbox(3, "webhook answered 503", 4*time.Second)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
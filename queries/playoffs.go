@@ -1,10 +1,10 @@
 package queries
 
 import (
-	"errors"
 	"fmt"
 	"log"
 	"slices"
+	"strconv"
 
 	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
 
@@ -14,6 +14,14 @@ import (
 
 type PlayoffsDBConnection struct {
 	*sqlx.DB
+	// RECORDED IN THE AUDIT LOG FOR EVERY MUTATION, SEE WithActor
+	Actor string
+	// EVERY QUERY IS SCOPED TO THIS LEAGUE, SEE ForLeague
+	League string
+	// CALLED AFTER EVERY COMMITTED CHANGE OF A BRACKET WITH THE GAMES IT CHANGED. IT RUNS ON THE CALLER'S
+	// GOROUTINE SO IT MUST NOT BLOCK, AND IS SKIPPED WHEN THE PROCESS STOPS RIGHT AFTER THE COMMIT.
+	// CONSUMERS THAT CAN NOT MISS A CHANGE DRAIN THE OUTBOX WITH outbox.Relay INSTEAD
+	OnChange func(models.BracketChangeModel)
 }
 
 type Playoffs interface {
@@ -27,12 +35,8 @@ type seasonCount struct {
 	count int `db:"count"`
 }
 
-func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season string, limit int) error {
-	seasonCount := seasonCount{}
-	query :=
-		`
-		SELECT COUNT(*) AS count FROM playoffs WHERE season = $1
-		`
+func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season string, competition string, limit int) error {
+	competition = competitionOrDefault(competition)
 	tx, errTx := p.DB.Beginx()
 	if errTx != nil {
 		log.Println("error creating playoffs tx: ", errTx.Error())
@@ -42,20 +46,87 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 		_ = tx.Rollback()
 
 	}()
+	games, err := p.planPlayoffs(tx, conferences, season, competition, limit)
+	if err != nil {
+		return err
+	}
+	if err := p.insertPlayoffs(tx, games); err != nil {
+		return err
+	}
+	created := map[string]any{"competition": competition, "conferences": conferences, "limit": limit}
+	if err := p.writeAudit(tx, season, competition, nil, AuditCreatePlayoffs, nil, created); err != nil {
+		return err
+	}
+	change := models.BracketChangeModel{Season: season, Competition: competition, Operation: AuditCreatePlayoffs}
+	if err := p.writeOutbox(tx, &change); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	p.notify(change)
+
+	return nil
+}
+
+// THE GAMES OF A BRACKET AS planPlayoffs PAIRS THEM, IN THE ORDER insertPlayoffs WRITES THEM
+type playoffsPlan struct {
+	season      string
+	league      string
+	competition string
+	games       []models.PlayoffsModel
+}
+
+// ADDS GAME gameRound OF SERIES count OF round. FIRST ROUND GAMES ARE BETWEEN home AND away, THE LATER ROUNDS
+// ARE ADDED WITHOUT TEAMS (nil) AND FILLED AS THE WINNERS ADVANCE. THE VERSION STAYS 0, THE DEFAULT OF THE version
+// COLUMN insertPlayoffs LEAVES IT TO, SO A PREVIEWED GAME HAS THE VERSION OF THE CREATED ONE
+func (plan *playoffsPlan) addGame(round int, count string, gameRound string, home *models.StandingsModel, away *models.StandingsModel) {
+	g := models.PlayoffsModel{
+		PlayoffsId:      uuid.New(),
+		FixtureRound:    &round,
+		GameCount:       &count,
+		GameRound:       gameRound,
+		PlayersInHomeId: uuid.New(),
+		PlayersInAwayId: uuid.New(),
+		Season:          plan.season,
+		League:          plan.league,
+		Competition:     plan.competition,
+	}
+	if home != nil {
+		name := home.TeamName
+		g.HomeTeamId, g.HomeTeamName, g.HomeTeamURL = home.TeamId, &name, home.TeamPicUrl
+	}
+	if away != nil {
+		name := away.TeamName
+		g.AwayTeamId, g.AwayTeamName, g.AwayTeamURL = away.TeamId, &name, away.TeamPicUrl
+	}
+	plan.games = append(plan.games, g)
+}
+
+// CHECKS THE COMPETITION HAS NO BRACKET IN THE SEASON YET, SELECTS THE QUALIFIED TEAMS OF THE STANDINGS AND PAIRS
+// THEM. IT ONLY READS: THE GAMES OF THE BRACKET ARE RETURNED FOR insertPlayoffs TO WRITE OR PreviewPlayoffs TO SHOW
+func (p *PlayoffsDBConnection) planPlayoffs(tx *sqlx.Tx, conferences []string, season string, competition string, limit int) ([]models.PlayoffsModel, error) {
+	seasonCount := seasonCount{}
+	query :=
+		`
+		SELECT COUNT(*) AS count FROM playoffs WHERE season = $1 AND league = $2 AND competition = $3
+		`
+	league := p.LeagueOrDefault()
+	plan := &playoffsPlan{season: season, league: league, competition: competition}
 
-	err := tx.Get(&seasonCount.count, query, season)
+	err := tx.Get(&seasonCount.count, query, season, league, competition)
 	if err != nil {
 		log.Println("error counting playoffs records: ", err.Error())
-		return err
+		return nil, err
 	}
 
 	if seasonCount.count >= 1 {
-		errC := errors.New("Cannot create the requested Playoffs of season " + season + ", this season already exists!")
-		return errC
+		errC := newError(ErrAlreadyExists, "Cannot create the requested Playoffs "+competition+" of season "+season+", this season already exists!")
+		return nil, errC
 	}
 	if len(conferences) != 1 && len(conferences) != 2 && len(conferences) != 4 && len(conferences) != 8 {
-		errL := errors.New("invalid number of conferences for Playoffs generator. valid numbers: (1, 2, 4, 8)")
-		return errL
+		errL := newError(ErrInvalidInput, "invalid number of conferences for Playoffs generator. valid numbers: (1, 2, 4, 8)")
+		return nil, errL
 	}
 
 	// EXPECTED NUMBER OF CONFERENCES IS 1, 2, 4, OR 8 FOR THIS LEAGUE STRUCTURE SINCE
@@ -75,13 +146,13 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 			SELECT *, 
 			RANK() OVER(PARTITION BY conference ORDER BY pts desc) AS position 
 			FROM standings
-			WHERE conference = $1 AND season = $2
+			WHERE conference = $1 AND season = $2 AND league = $4
 			LIMIT $3
 		`
-		errHt := tx.Select(&allTeams, query, conferences[0], season, limit)
+		errHt := tx.Select(&allTeams, query, conferences[0], season, limit, league)
 		if errHt != nil {
 			log.Println("error SELECTING allTeams; CASE = 1 ERROR: ", errHt)
-			return errHt
+			return nil, errHt
 		}
 		partitionLimit := limit / 2
 		if len(allTeams) >= partitionLimit {
@@ -101,64 +172,23 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 		}
 
 		if len(homeTeams) < partitionLimit {
-			err := errors.New(conferences[0] + "has less qualified teams of" + fmt.Sprint(len(homeTeams)) + " teams than the required number of " + fmt.Sprint(partitionLimit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[0]+"has less qualified teams of"+fmt.Sprint(len(homeTeams))+" teams than the required number of "+fmt.Sprint(partitionLimit)+"teams")
+			return nil, err
 		}
 		if len(awayTeams) < partitionLimit {
-			err := errors.New(conferences[0] + "has less qualified teams of" + fmt.Sprint(len(homeTeams)) + " teams than the required number of " + fmt.Sprint(partitionLimit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[0]+"has less qualified teams of"+fmt.Sprint(len(homeTeams))+" teams than the required number of "+fmt.Sprint(partitionLimit)+"teams")
+			return nil, err
 		}
 		if len(homeTeams) != len(awayTeams) {
-			err := errors.New("Invalid number of teams in the conference " + conferences[0] + ". The number of teams must be even to create home and away teams for the playoffs.")
-			return err
+			err := newError(ErrInvalidInput, "Invalid number of teams in the conference "+conferences[0]+". The number of teams must be even to create home and away teams for the playoffs.")
+			return nil, err
 		}
 		reversedAwayTeams := reverseTeam(awayTeams)
-		playoffsQuery :=
-			`
-		INSERT INTO playoffs 
-		(
-		playoffs_id, 
-		fixture_round, 
-		game_count, 
-		game_round, 
-		home_team_id, 
-		home_team_name, 
-		home_team_url, 
-		players_in_home_id, 
-		away_team_id, 
-		away_team_name, 
-		away_team_url, 
-		players_in_away_id, 
-		season)
-		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-		`
-		// Insert AS THE FINAL if limit is 1
+		// PLAYED AS THE FINAL if limit is 1
 		if partitionLimit == 1 {
-			playoffsId := uuid.New()
-			playersInHomeId := uuid.New()
-			playersInAwayId := uuid.New()
 			h := homeTeams[0]
 			a := awayTeams[0]
-			_, err := tx.Exec(
-				playoffsQuery,
-				playoffsId,
-				0000,
-				"FINAL",
-				"1",
-				h.TeamId,
-				h.TeamName,
-				h.TeamPicUrl,
-				playersInHomeId,
-				a.TeamId,
-				a.TeamName,
-				a.TeamPicUrl,
-				playersInAwayId,
-				season,
-			)
-			if err != nil {
-				log.Println("failed to insert FINAL: CASE 1: ", err.Error())
-				return err
-			}
+			plan.addGame(0, "FINAL", "1", &h, &a)
 		}
 
 		count := len(homeTeams)
@@ -166,53 +196,12 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 		for len(homeTeams) > 1 {
 			//
 			for i := 0; i < len(homeTeams); i++ {
-				// INSERTING THE BEST OF 3 GAMES FOR EVERY FIXTURE ROUND
+				// THE BEST OF 3 GAMES OF EVERY FIXTURE ROUND
 				for inner := 0; inner < 3; inner++ {
 					if fixtureRound == 1 {
-						_, err := tx.Exec(
-							playoffsQuery,
-							uuid.New(),
-							fixtureRound,
-							i+1,
-							inner+1,
-							homeTeams[i].TeamId,
-							homeTeams[i].TeamName,
-							homeTeams[i].TeamPicUrl,
-							uuid.New(),
-							reversedAwayTeams[i].TeamId,
-							reversedAwayTeams[i].TeamName,
-							reversedAwayTeams[i].TeamPicUrl,
-							uuid.New(),
-							season,
-						)
-						if err != nil {
-							log.Println("failed to INSERT playoffs records: ERROR CASE = 1, INNER LOOP: ", err.Error())
-							return err
-						}
+						plan.addGame(fixtureRound, strconv.Itoa(i+1), strconv.Itoa(inner+1), &homeTeams[i], &reversedAwayTeams[i])
 					} else {
-						playoffsIdNextRound := uuid.New()
-						playersInHomeIdNextRound := uuid.New()
-						playersInAwayIdNextRound := uuid.New()
-						playoffsQueryNextRound :=
-							`
-								INSERT INTO playoffs 
-								(playoffs_id, fixture_round, game_count, game_round,  players_in_home_id,  players_in_away_id, season)
-								VALUES($1, $2, $3, $4, $5, $6, $7)
-								`
-						_, err := tx.Exec(
-							playoffsQueryNextRound,
-							playoffsIdNextRound,
-							fixtureRound,
-							i+1+count,
-							inner+1,
-							playersInHomeIdNextRound,
-							playersInAwayIdNextRound,
-							season,
-						)
-						if err != nil {
-							log.Println("failed to INSERT playoffs records: ERROR CASE = 1, INNER LOOP fixtureRound > 1: ", err.Error())
-							return err
-						}
+						plan.addGame(fixtureRound, strconv.Itoa(i+1+count), strconv.Itoa(inner+1), nil, nil)
 						// NEXT ROUND COUNT INCREMENT
 						if inner == 2 && i == len(homeTeams)-1 {
 							count = count + len(homeTeams)
@@ -228,26 +217,7 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 			fixtureRound++
 
 		}
-		playoffsQueryFinal :=
-			`
-					INSERT INTO playoffs 
-					(playoffs_id, fixture_round, game_count, game_round,  players_in_home_id,  players_in_away_id, season)
-					VALUES($1, $2, $3, $4, $5, $6, $7)
-					`
-		_, err := tx.Exec(
-			playoffsQueryFinal,
-			uuid.New(),
-			fixtureRound,
-			"FINAL",
-			"1",
-			uuid.New(),
-			uuid.New(),
-			season,
-		)
-		if err != nil {
-			log.Println("failed to INSERT playoffs records: ERROR CASE = 1, FINAL: ", err.Error())
-			return err
-		}
+		plan.addGame(fixtureRound, "FINAL", "1", nil, nil)
 
 	case 2:
 		var homeTeams []models.StandingsModel
@@ -257,77 +227,36 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 				SELECT *, 
 				RANK() OVER(PARTITION BY conference ORDER BY pts desc) AS position 
 				FROM standings
-				WHERE conference = $1 AND season = $2
+				WHERE conference = $1 AND season = $2 AND league = $4
 				LIMIT $3
 			`
-		errHt := tx.Select(&homeTeams, query, conferences[0], season, limit)
+		errHt := tx.Select(&homeTeams, query, conferences[0], season, limit, league)
 		if errHt != nil {
 			log.Println("error SELECTING homeTeams; CASE = 2 ERROR: ", errHt)
-			return errHt
+			return nil, errHt
 		}
-		errAt := tx.Select(&awayTeams, query, conferences[1], season, limit)
+		errAt := tx.Select(&awayTeams, query, conferences[1], season, limit, league)
 		if errAt != nil {
 			log.Println("error SELECTING awayTeams; CASE = 2 ERROR: ", errAt)
-			return errAt
+			return nil, errAt
 		}
 		if len(homeTeams) < limit {
-			err := errors.New(conferences[0] + "has less qualified teams of" + fmt.Sprint(len(homeTeams)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[0]+"has less qualified teams of"+fmt.Sprint(len(homeTeams))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
 		if len(awayTeams) < limit {
-			err := errors.New(conferences[0] + "has less qualified teams of" + fmt.Sprint(len(homeTeams)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[0]+"has less qualified teams of"+fmt.Sprint(len(homeTeams))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
 		if len(homeTeams) != len(awayTeams) {
-			err := errors.New("Ivalid number of teams in the conferences " + conferences[0] + " and " + conferences[1] + ". The number of teams in both conferences must be equal to create home and away teams for the playoffs.")
-			return err
+			err := newError(ErrInvalidInput, "Ivalid number of teams in the conferences "+conferences[0]+" and "+conferences[1]+". The number of teams in both conferences must be equal to create home and away teams for the playoffs.")
+			return nil, err
 		}
 		reversedAwayTeams := reverseTeam(awayTeams)
-		playoffsQuery :=
-			`
-			INSERT INTO playoffs 
-			(
-			playoffs_id, 
-			fixture_round, 
-			game_count, 
-			game_round, 
-			home_team_id, 
-			home_team_name, 
-			home_team_url, 
-			players_in_home_id, 
-			away_team_id, 
-			away_team_name, 
-			away_team_url, 
-			players_in_away_id, 
-			season)
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-			`
 		if limit == 1 {
-			playoffsId := uuid.New()
-			playersInHomeId := uuid.New()
-			playersInAwayId := uuid.New()
 			h := homeTeams[0]
 			a := awayTeams[0]
-			_, err := tx.Exec(
-				playoffsQuery,
-				playoffsId,
-				0000,
-				"FINAL",
-				"1",
-				h.TeamId,
-				h.TeamName,
-				h.TeamPicUrl,
-				playersInHomeId,
-				a.TeamId,
-				a.TeamName,
-				a.TeamPicUrl,
-				playersInAwayId,
-				season,
-			)
-			if err != nil {
-				log.Println("failed to insert FINAL: CASE 2: ", err.Error())
-				return err
-			}
+			plan.addGame(0, "FINAL", "1", &h, &a)
 		}
 
 		count := len(homeTeams)
@@ -336,50 +265,9 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 			for i := 0; i < len(homeTeams); i++ {
 				for inner := 0; inner < 3; inner++ {
 					if fixtureRound == 1 {
-						_, err := tx.Exec(
-							playoffsQuery,
-							uuid.New(),
-							fixtureRound,
-							i+1,
-							inner+1,
-							homeTeams[i].TeamId,
-							homeTeams[i].TeamName,
-							homeTeams[i].TeamPicUrl,
-							uuid.New(),
-							reversedAwayTeams[i].TeamId,
-							reversedAwayTeams[i].TeamName,
-							reversedAwayTeams[i].TeamPicUrl,
-							uuid.New(),
-							season,
-						)
-						if err != nil {
-							log.Println("failed to INSERT playoffs records: ERROR CASE = 2, INNER LOOP: ", err.Error())
-							return err
-						}
+						plan.addGame(fixtureRound, strconv.Itoa(i+1), strconv.Itoa(inner+1), &homeTeams[i], &reversedAwayTeams[i])
 					} else {
-						playoffsIdNextRound := uuid.New()
-						playersInHomeIdNextRound := uuid.New()
-						playersInAwayIdNextRound := uuid.New()
-						playoffsQueryNextRound :=
-							`
-									INSERT INTO playoffs 
-									(playoffs_id, fixture_round, game_count, game_round,  players_in_home_id,  players_in_away_id, season)
-									VALUES($1, $2, $3, $4, $5, $6, $7)
-									`
-						_, err := tx.Exec(
-							playoffsQueryNextRound,
-							playoffsIdNextRound,
-							fixtureRound,
-							i+1+count,
-							inner+1,
-							playersInHomeIdNextRound,
-							playersInAwayIdNextRound,
-							season,
-						)
-						if err != nil {
-							log.Println("failed to INSERT playoffs records: ERROR CASE = 2, INNER LOOP fixtureRound > 1: ", err.Error())
-							return err
-						}
+						plan.addGame(fixtureRound, strconv.Itoa(i+1+count), strconv.Itoa(inner+1), nil, nil)
 						if inner == 2 && i == len(homeTeams)-1 {
 							count = count + len(homeTeams)
 						}
@@ -393,26 +281,7 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 			fixtureRound++
 
 		}
-		playoffsQueryFinal :=
-			`
-						INSERT INTO playoffs 
-						(playoffs_id, fixture_round, game_count, game_round,  players_in_home_id,  players_in_away_id, season)
-						VALUES($1, $2, $3, $4, $5, $6, $7)
-						`
-		_, err := tx.Exec(
-			playoffsQueryFinal,
-			uuid.New(),
-			fixtureRound,
-			"FINAL",
-			"1",
-			uuid.New(),
-			uuid.New(),
-			season,
-		)
-		if err != nil {
-			log.Println("failed to INSERT playoffs records: ERROR CASE =2, FINAL: ", err.Error())
-			return err
-		}
+		plan.addGame(fixtureRound, "FINAL", "1", nil, nil)
 
 	case 4:
 		var homeTeams1 []models.StandingsModel
@@ -425,56 +294,56 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 				SELECT *, 
 				RANK() OVER(PARTITION BY conference ORDER BY pts desc) AS position 
 				FROM standings
-				WHERE conference = $1 AND season = $2 
+				WHERE conference = $1 AND season = $2 AND league = $4
 				LIMIT $3
 			`
-		errHt1 := tx.Select(&homeTeams1, query, conferences[0], season, limit)
+		errHt1 := tx.Select(&homeTeams1, query, conferences[0], season, limit, league)
 		if errHt1 != nil {
 			log.Println("error SELECTING homeTeams1; CASE = 4 ERROR: ", errHt1)
-			return errHt1
+			return nil, errHt1
 		}
 		if len(homeTeams1) < limit {
-			err := errors.New(conferences[0] + "has less qualified teams of" + fmt.Sprint(len(homeTeams1)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[0]+"has less qualified teams of"+fmt.Sprint(len(homeTeams1))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errAt1 := tx.Select(&awayTeams1, query, conferences[1], season, limit)
+		errAt1 := tx.Select(&awayTeams1, query, conferences[1], season, limit, league)
 		if errAt1 != nil {
 			fmt.Println("error SELECTING awayTeams1; CASE = 4 ERROR: ", errAt1)
-			return errAt1
+			return nil, errAt1
 		}
-		errHt2 := tx.Select(&homeTeams2, query, conferences[2], season, limit)
+		errHt2 := tx.Select(&homeTeams2, query, conferences[2], season, limit, league)
 		if errHt2 != nil {
 			log.Println("error SELECTING homeTeams2; CASE = 4 ERROR: ", errHt2)
-			return errHt2
+			return nil, errHt2
 		}
 		if len(homeTeams2) < limit {
-			err := errors.New(conferences[1] + "has less qualified teams of" + fmt.Sprint(len(homeTeams2)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[1]+"has less qualified teams of"+fmt.Sprint(len(homeTeams2))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
 		if len(awayTeams1) < limit {
-			err := errors.New(conferences[2] + "has less qualified teams of" + fmt.Sprint(len(awayTeams1)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[2]+"has less qualified teams of"+fmt.Sprint(len(awayTeams1))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errAt2 := tx.Select(&awayTeams2, query, conferences[3], season, limit)
+		errAt2 := tx.Select(&awayTeams2, query, conferences[3], season, limit, league)
 		if errAt2 != nil {
 			log.Println("error SELECTING awayTeams1; CASE = 4 ERROR: ", errAt2)
-			return errAt2
+			return nil, errAt2
 		}
 		if len(awayTeams2) < limit {
-			err := errors.New(conferences[3] + "has less qualified teams of" + fmt.Sprint(len(awayTeams2)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[3]+"has less qualified teams of"+fmt.Sprint(len(awayTeams2))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
 		if len(homeTeams1) != len(awayTeams1) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[1] + "has " + fmt.Sprint(len(awayTeams1)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[1]+"has "+fmt.Sprint(len(awayTeams1))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		if len(homeTeams1) != len(homeTeams2) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[2] + "has " + fmt.Sprint(len(homeTeams2)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[2]+"has "+fmt.Sprint(len(homeTeams2))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		if len(homeTeams1) != len(awayTeams2) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[3] + "has " + fmt.Sprint(len(homeTeams2)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[3]+"has "+fmt.Sprint(len(homeTeams2))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 
 		reversedAwayTeam1 := reverseTeam(awayTeams1)
@@ -483,72 +352,15 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 			pairedteams = append(pairedteams, []models.StandingsModel{homeTeams1[i], reversedAwayTeam1[i]}, []models.StandingsModel{homeTeams2[i], reversedAwayTeam2[i]})
 		}
 		count := len(pairedteams)
-		playoffsQuery :=
-			`
-			INSERT INTO playoffs 
-			(
-			playoffs_id, 
-			fixture_round, 
-			game_count, 
-			game_round, 
-			home_team_id, 
-			home_team_name, 
-			home_team_url, 
-			players_in_home_id, 
-			away_team_id, 
-			away_team_name, 
-			away_team_url, 
-			players_in_away_id, 
-			season)
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-			`
 		round := 1
 		for len(pairedteams) > 1 {
 			for i := 0; i < len(pairedteams); i++ {
 				for inner := 0; inner < 3; inner++ {
 					if round == 1 {
-						_, err := tx.Exec(
-							playoffsQuery,
-							uuid.New(),
-							round,
-							i+1,
-							inner+1,
-							pairedteams[i][0].TeamId,
-							pairedteams[i][0].TeamName,
-							pairedteams[i][0].TeamPicUrl,
-							uuid.New(),
-							pairedteams[i][1].TeamId,
-							pairedteams[i][1].TeamName,
-							pairedteams[i][1].TeamPicUrl,
-							uuid.New(),
-							season,
-						)
-						if err != nil {
-							log.Println("failed to INSERT playoffs records: ERROR CASE = 4, INNER LOOP: ", err.Error())
-							return err
-						}
+						plan.addGame(round, strconv.Itoa(i+1), strconv.Itoa(inner+1), &pairedteams[i][0], &pairedteams[i][1])
 					} else {
 
-						playoffsQueryNextRound :=
-							`
-									INSERT INTO playoffs 
-									(playoffs_id, fixture_round, game_count, game_round,  players_in_home_id,  players_in_away_id, season)
-									VALUES($1, $2, $3, $4, $5, $6, $7)
-									`
-						_, err := tx.Exec(
-							playoffsQueryNextRound,
-							uuid.New(),
-							round,
-							i+1+count,
-							inner+1,
-							uuid.New(),
-							uuid.New(),
-							season,
-						)
-						if err != nil {
-							log.Println("failed to INSERT playoffs records: ERROR CASE = 4, INNER LOOP NEXT ROUND: ", err.Error())
-							return err
-						}
+						plan.addGame(round, strconv.Itoa(i+1+count), strconv.Itoa(inner+1), nil, nil)
 					}
 				}
 			}
@@ -557,26 +369,7 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 
 		}
 
-		playoffsQueryFinal :=
-			`
-									INSERT INTO playoffs 
-									(playoffs_id, fixture_round, game_count, game_round,  players_in_home_id,  players_in_away_id, season)
-									VALUES($1, $2, $3, $4, $5, $6, $7)
-									`
-		_, err := tx.Exec(
-			playoffsQueryFinal,
-			uuid.New(),
-			round,
-			"FINAL",
-			"1",
-			uuid.New(),
-			uuid.New(),
-			season,
-		)
-		if err != nil {
-			log.Println("failed to INSERT playoffs records: ERROR CASE = 4, FINAL: ", err.Error())
-			return err
-		}
+		plan.addGame(round, "FINAL", "1", nil, nil)
 
 	case 8:
 		var homeTeams1 []models.StandingsModel
@@ -593,109 +386,109 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 				SELECT *, 
 				RANK() OVER(PARTITION BY conference ORDER BY pts desc) AS position 
 				FROM standings
-				WHERE conference = $1 AND season = $2
+				WHERE conference = $1 AND season = $2 AND league = $4
 				LIMIT $3
 			`
-		errHt1 := tx.Select(&homeTeams1, query, conferences[0], season, limit)
+		errHt1 := tx.Select(&homeTeams1, query, conferences[0], season, limit, league)
 		if errHt1 != nil {
 			log.Println("error SELECTING homeTeams1; CASE = 8 ERROR: ", errHt1)
-			return errHt1
+			return nil, errHt1
 		}
 		if len(homeTeams1) < limit {
-			err := errors.New(conferences[0] + "has less qualified teams of" + fmt.Sprint(len(homeTeams1)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[0]+"has less qualified teams of"+fmt.Sprint(len(homeTeams1))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errAt1 := tx.Select(&awayTeams1, query, conferences[1], season, limit)
+		errAt1 := tx.Select(&awayTeams1, query, conferences[1], season, limit, league)
 		if errAt1 != nil {
 			log.Println("error SELECTING awayTeams1; CASE = 8 ERROR: ", errAt1)
-			return errAt1
+			return nil, errAt1
 		}
 		if len(awayTeams1) < limit {
-			err := errors.New(conferences[1] + "has less qualified teams of" + fmt.Sprint(len(awayTeams1)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[1]+"has less qualified teams of"+fmt.Sprint(len(awayTeams1))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errHt2 := tx.Select(&homeTeams2, query, conferences[2], season, limit)
+		errHt2 := tx.Select(&homeTeams2, query, conferences[2], season, limit, league)
 		if errHt2 != nil {
 			log.Println("error SELECTING homeTeams2; CASE = 8 ERROR: ", errHt2)
-			return errHt2
+			return nil, errHt2
 		}
 		if len(homeTeams2) < limit {
-			err := errors.New(conferences[2] + "has less qualified teams of" + fmt.Sprint(len(homeTeams2)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[2]+"has less qualified teams of"+fmt.Sprint(len(homeTeams2))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errAt2 := tx.Select(&awayTeams2, query, conferences[3], season, limit)
+		errAt2 := tx.Select(&awayTeams2, query, conferences[3], season, limit, league)
 		if errAt2 != nil {
 			log.Println("error SELECTING awayTeams1; CASE = 8 ERROR: ", errAt2)
-			return errAt2
+			return nil, errAt2
 		}
 		if len(awayTeams2) < limit {
-			err := errors.New(conferences[3] + "has less qualified teams of" + fmt.Sprint(len(awayTeams2)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[3]+"has less qualified teams of"+fmt.Sprint(len(awayTeams2))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errHt3 := tx.Select(&homeTeams3, query, conferences[4], season, limit)
+		errHt3 := tx.Select(&homeTeams3, query, conferences[4], season, limit, league)
 		if errHt3 != nil {
 			log.Println("error SELECTING homeTeams2; CASE = 8 ERROR: ", errHt3)
-			return errHt3
+			return nil, errHt3
 		}
 		if len(homeTeams3) < limit {
-			err := errors.New(conferences[4] + "has less qualified teams of" + fmt.Sprint(len(homeTeams3)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[4]+"has less qualified teams of"+fmt.Sprint(len(homeTeams3))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errAt3 := tx.Select(&awayTeams3, query, conferences[5], season, limit)
+		errAt3 := tx.Select(&awayTeams3, query, conferences[5], season, limit, league)
 		if errAt3 != nil {
 			log.Println("error SELECTING awayTeams1; CASE = 8 ERROR: ", errAt3)
-			return errAt3
+			return nil, errAt3
 		}
 		if len(awayTeams3) < limit {
-			err := errors.New(conferences[5] + "has less qualified teams of" + fmt.Sprint(len(awayTeams3)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[5]+"has less qualified teams of"+fmt.Sprint(len(awayTeams3))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errHt4 := tx.Select(&homeTeams4, query, conferences[6], season, limit)
+		errHt4 := tx.Select(&homeTeams4, query, conferences[6], season, limit, league)
 		if errHt4 != nil {
 			log.Println("error SELECTING homeTeams2; CASE = 8 ERROR: ", errHt4)
-			return errHt4
+			return nil, errHt4
 		}
 		if len(homeTeams4) < limit {
-			err := errors.New(conferences[6] + "has less qualified teams of" + fmt.Sprint(len(homeTeams4)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[6]+"has less qualified teams of"+fmt.Sprint(len(homeTeams4))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
-		errAt4 := tx.Select(&awayTeams4, query, conferences[7], season, limit)
+		errAt4 := tx.Select(&awayTeams4, query, conferences[7], season, limit, league)
 		if errAt4 != nil {
 			log.Println("error SELECTING awayTeams1; CASE = 8 ERROR: ", errAt4)
-			return errAt4
+			return nil, errAt4
 		}
 		if len(awayTeams4) < limit {
-			err := errors.New(conferences[7] + "has less qualified teams of" + fmt.Sprint(len(awayTeams4)) + " teams than the required number of " + fmt.Sprint(limit) + "teams")
-			return err
+			err := newError(ErrInvalidInput, conferences[7]+"has less qualified teams of"+fmt.Sprint(len(awayTeams4))+" teams than the required number of "+fmt.Sprint(limit)+"teams")
+			return nil, err
 		}
 
 		if len(homeTeams1) != len(awayTeams1) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[1] + "has " + fmt.Sprint(len(awayTeams1)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[1]+"has "+fmt.Sprint(len(awayTeams1))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		if len(homeTeams1) != len(homeTeams2) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[2] + "has " + fmt.Sprint(len(homeTeams2)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[2]+"has "+fmt.Sprint(len(homeTeams2))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		if len(homeTeams1) != len(awayTeams2) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[3] + "has " + fmt.Sprint(len(awayTeams2)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[3]+"has "+fmt.Sprint(len(awayTeams2))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		if len(homeTeams1) != len(homeTeams3) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[4] + "has " + fmt.Sprint(len(homeTeams3)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[4]+"has "+fmt.Sprint(len(homeTeams3))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		if len(homeTeams1) != len(awayTeams3) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[5] + "has " + fmt.Sprint(len(awayTeams3)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[5]+"has "+fmt.Sprint(len(awayTeams3))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		if len(homeTeams1) != len(homeTeams4) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[6] + "has " + fmt.Sprint(len(homeTeams4)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[6]+"has "+fmt.Sprint(len(homeTeams4))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		if len(homeTeams1) != len(awayTeams4) {
-			err := errors.New("The number of teams in all conferences must be equal. " + conferences[7] + "has " + fmt.Sprint(len(awayTeams4)) + " qualified teams which is not the same as the other conferences.")
-			return err
+			err := newError(ErrInvalidInput, "The number of teams in all conferences must be equal. "+conferences[7]+"has "+fmt.Sprint(len(awayTeams4))+" qualified teams which is not the same as the other conferences.")
+			return nil, err
 		}
 		reversedAwayTeam1 := reverseTeam(awayTeams1)
 		reversedAwayTeam2 := reverseTeam(awayTeams2)
@@ -705,72 +498,15 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 			pairedteams = append(pairedteams, []models.StandingsModel{homeTeams1[i], reversedAwayTeam1[i]}, []models.StandingsModel{homeTeams2[i], reversedAwayTeam2[i]}, []models.StandingsModel{homeTeams3[i], reversedAwayTeam3[i]}, []models.StandingsModel{homeTeams4[i], reversedAwayTeam4[i]})
 		}
 		count := len(pairedteams)
-		playoffsQuery :=
-			`
-			INSERT INTO playoffs 
-			(
-			playoffs_id, 
-			fixture_round, 
-			game_count, 
-			game_round, 
-			home_team_id, 
-			home_team_name, 
-			home_team_url, 
-			players_in_home_id, 
-			away_team_id, 
-			away_team_name, 
-			away_team_url, 
-			players_in_away_id, 
-			season)
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-			`
 		round := 1
 		for len(pairedteams) > 1 {
 			for i := 0; i < len(pairedteams); i++ {
 				for inner := 0; inner < 3; inner++ {
 					if round == 1 {
-						_, err := tx.Exec(
-							playoffsQuery,
-							uuid.New(),
-							round,
-							i+1,
-							inner+1,
-							pairedteams[i][0].TeamId,
-							pairedteams[i][0].TeamName,
-							pairedteams[i][0].TeamPicUrl,
-							uuid.New(),
-							pairedteams[i][1].TeamId,
-							pairedteams[i][1].TeamName,
-							pairedteams[i][1].TeamPicUrl,
-							uuid.New(),
-							season,
-						)
-						if err != nil {
-							log.Println("failed to INSERT playoffs records: ERROR CASE = 8, INNER LOOP: ", err.Error())
-							return err
-						}
+						plan.addGame(round, strconv.Itoa(i+1), strconv.Itoa(inner+1), &pairedteams[i][0], &pairedteams[i][1])
 					} else {
 
-						playoffsQueryNextRound :=
-							`
-									INSERT INTO playoffs 
-									(playoffs_id, fixture_round, game_count, game_round,  players_in_home_id,  players_in_away_id, season)
-									VALUES($1, $2, $3, $4, $5, $6, $7)
-									`
-						_, err := tx.Exec(
-							playoffsQueryNextRound,
-							uuid.New(),
-							round,
-							i+1+count,
-							inner+1,
-							uuid.New(),
-							uuid.New(),
-							season,
-						)
-						if err != nil {
-							log.Println("failed to INSERT playoffs records: ERROR CASE = 8, INNER LOOP NEXT ROUND: ", err.Error())
-							return err
-						}
+						plan.addGame(round, strconv.Itoa(i+1+count), strconv.Itoa(inner+1), nil, nil)
 					}
 				}
 			}
@@ -779,31 +515,41 @@ func (p *PlayoffsDBConnection) CreatePlayoffs(conferences []string, season strin
 
 		}
 
-		playoffsQueryFinal :=
-			`
-									INSERT INTO playoffs 
-									(playoffs_id, fixture_round, game_count, game_round,  players_in_home_id,  players_in_away_id, season)
-									VALUES($1, $2, $3, $4, $5, $6, $7)
-									`
-		_, err := tx.Exec(
-			playoffsQueryFinal,
-			uuid.New(),
-			round,
-			"FINAL",
-			"1",
-			uuid.New(),
-			uuid.New(),
-			season,
-		)
+		plan.addGame(round, "FINAL", "1", nil, nil)
+	}
+	return plan.games, nil
+}
+
+// INSERTS THE GAMES OF A PLAN. THE GAMES WITH TEAMS ARE INSERTED WITH THEIR SLOTS, THE OTHERS WITH EMPTY ONES
+func (p *PlayoffsDBConnection) insertPlayoffs(tx *sqlx.Tx, games []models.PlayoffsModel) error {
+	queryWithTeams :=
+		`
+		INSERT INTO playoffs
+		(playoffs_id, fixture_round, game_count, game_round, home_team_id, home_team_name, home_team_url, players_in_home_id,
+		away_team_id, away_team_name, away_team_url, players_in_away_id, season, league, competition)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`
+	queryWithoutTeams :=
+		`
+		INSERT INTO playoffs
+		(playoffs_id, fixture_round, game_count, game_round, players_in_home_id, players_in_away_id, season, league, competition)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`
+	for _, g := range games {
+		var err error
+		if g.HomeTeamName != nil || g.AwayTeamName != nil {
+			_, err = tx.Exec(queryWithTeams, g.PlayoffsId, *g.FixtureRound, *g.GameCount, g.GameRound, g.HomeTeamId, g.HomeTeamName,
+				g.HomeTeamURL, g.PlayersInHomeId, g.AwayTeamId, g.AwayTeamName, g.AwayTeamURL, g.PlayersInAwayId, g.Season, g.League,
+				g.Competition)
+		} else {
+			_, err = tx.Exec(queryWithoutTeams, g.PlayoffsId, *g.FixtureRound, *g.GameCount, g.GameRound, g.PlayersInHomeId,
+				g.PlayersInAwayId, g.Season, g.League, g.Competition)
+		}
 		if err != nil {
-			log.Println("failed to INSERT playoffs records: ERROR CASE = 8, FINAL: ", err.Error())
+			log.Println("failed to INSERT playoffs records: ", err.Error())
 			return err
 		}
 	}
-	if err := tx.Commit(); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -822,15 +568,17 @@ type playCount struct {
 	GameCount    string `db:"game_count"`
 }
 
-func (p *PlayoffsDBConnection) ListPlayoffs(season string) ([][][]models.PlayoffsModel, error) {
+func (p *PlayoffsDBConnection) ListPlayoffs(season string, competition string) ([][][]models.PlayoffsModel, error) {
 	var playCount []playCount
 	var playoffsInner []models.PlayoffsModel
 	var rounds []rounds
 	queryCount :=
 		`
-	SELECT fixture_round FROM playoffs WHERE season = $1 GROUP BY fixture_round ORDER BY fixture_round ASC
+	SELECT fixture_round FROM playoffs WHERE season = $1 AND league = $2 AND competition = $3 AND archived_at IS NULL GROUP BY fixture_round ORDER BY fixture_round ASC
 	`
-	errC := p.DB.Select(&rounds, queryCount, season)
+	league := p.LeagueOrDefault()
+	competition = competitionOrDefault(competition)
+	errC := p.DB.Select(&rounds, queryCount, season, league, competition)
 	if errC != nil {
 		log.Println("error counting fixture_round in playoffs: ", string(errC.Error()))
 		if errC.Error() == "sql: no rows in result set" {
@@ -845,6 +593,8 @@ func (p *PlayoffsDBConnection) ListPlayoffs(season string) ([][][]models.Playoff
 		FROM playoffs 
 		WHERE season = $1
 		AND fixture_round = $2
+		AND league = $3
+		AND competition = $4
 		GROUP BY fixture_round, game_count
 		ORDER BY fixture_round, 
  		 CASE
@@ -855,11 +605,11 @@ func (p *PlayoffsDBConnection) ListPlayoffs(season string) ([][][]models.Playoff
 		`
 	queryInner :=
 		`
-			SELECT * FROM playoffs WHERE season = $1 AND fixture_round = $2 AND game_count = $3
+			SELECT * FROM playoffs WHERE season = $1 AND fixture_round = $2 AND game_count = $3 AND league = $4 AND competition = $5
 		`
 	roundsList := make([][][]models.PlayoffsModel, len(rounds))
 	for i := 0; i < len(rounds); i++ {
-		err := p.DB.Select(&playCount, query, season, rounds[i].FixtureRound)
+		err := p.DB.Select(&playCount, query, season, rounds[i].FixtureRound, league, competition)
 		if err != nil {
 			if err.Error() == "sql: no rows in result set" {
 				return [][][]models.PlayoffsModel{}, nil
@@ -874,7 +624,7 @@ func (p *PlayoffsDBConnection) ListPlayoffs(season string) ([][][]models.Playoff
 		}
 
 		for inner := 0; inner < len(roundsList[i]); inner++ {
-			err := p.DB.Select(&playoffsInner, queryInner, season, rounds[i].FixtureRound, playCount[inner].GameCount)
+			err := p.DB.Select(&playoffsInner, queryInner, season, rounds[i].FixtureRound, playCount[inner].GameCount, league, competition)
 			if err != nil {
 				if err.Error() == "sql: no rows in result set" {
 					return [][][]models.PlayoffsModel{}, nil
@@ -890,6 +640,26 @@ func (p *PlayoffsDBConnection) ListPlayoffs(season string) ([][][]models.Playoff
 	return roundsList, nil
 }
 
+// LISTS THE SEASONS OF THE LEAGUE WITH A BRACKET THAT IS NOT ARCHIVED, NEWEST SEASON FIRST
+func (p *PlayoffsDBConnection) ListSeasons() ([]models.SeasonModel, error) {
+	seasons := []models.SeasonModel{}
+	query :=
+		`
+	SELECT season, competition, COUNT(*) AS games
+	FROM playoffs
+	WHERE league = $1
+	AND archived_at IS NULL
+	GROUP BY season, competition
+	ORDER BY season DESC, competition ASC
+	`
+	err := p.DB.Select(&seasons, query, p.LeagueOrDefault())
+	if err != nil {
+		log.Println("error SELECTING seasons: ", err.Error())
+		return []models.SeasonModel{}, err
+	}
+	return seasons, nil
+}
+
 type PlayoffsModelReqQuery struct {
 	PlayoffsId      uuid.UUID `db:"playoffs_id" json:"playoffsId"`
 	FixtureRound    int       `db:"fixture_round" json:"fixtureRound"`
@@ -905,19 +675,118 @@ type PlayoffsModelReqQuery struct {
 	Winner          uuid.UUID `db:"winner" json:"winner"`
 	HomeTeamURL     string    `db:"home_team_url" json:"homeTeamURL"`
 	AwayTeamURL     string    `db:"away_team_url" json:"awayTeamURL"`
+	Version         int       `db:"version" json:"version"`
+	Competition     string    `db:"competition" json:"competition"`
 }
+
+// BUILDS THE UpdatePlayoffs REQUEST OF A GAME AS RETURNED BY ListPlayoffs. EMPTY SLOTS GIVE ZERO VALUES
+func PlayoffsReqFromModel(game models.PlayoffsModel) PlayoffsModelReqQuery {
+	req := PlayoffsModelReqQuery{
+		PlayoffsId:      game.PlayoffsId,
+		GameRound:       game.GameRound,
+		PlayersInHomeId: game.PlayersInHomeId,
+		PlayersInAwayId: game.PlayersInAwayId,
+		Season:          game.Season,
+		Version:         game.Version,
+		Competition:     game.Competition,
+	}
+	if game.FixtureRound != nil {
+		req.FixtureRound = *game.FixtureRound
+	}
+	if game.GameCount != nil {
+		req.GameCount = *game.GameCount
+	}
+	if game.HomeTeamId != nil {
+		req.HomeTeamId = *game.HomeTeamId
+	}
+	if game.HomeTeamName != nil {
+		req.HomeTeamName = *game.HomeTeamName
+	}
+	if game.HomeTeamURL != nil {
+		req.HomeTeamURL = *game.HomeTeamURL
+	}
+	if game.AwayTeamId != nil {
+		req.AwayTeamId = *game.AwayTeamId
+	}
+	if game.AwayTeamName != nil {
+		req.AwayTeamName = *game.AwayTeamName
+	}
+	if game.AwayTeamURL != nil {
+		req.AwayTeamURL = *game.AwayTeamURL
+	}
+	if game.Winner != nil {
+		req.Winner = *game.Winner
+	}
+	return req
+}
+
 type WinnerRes struct {
 	Winner uuid.UUID `db:"winner"`
 }
 
-func (p *PlayoffsDBConnection) UpdatePlayoffsToNull(playoffsId uuid.UUID, round int, teamId uuid.UUID, season string) error {
+// LOCKS EVERY GAME OF THE SERIES (SAME SEASON, FIXTURE ROUND AND GAME COUNT) THE GIVEN GAME BELONGS TO.
+// CONCURRENT RESULT UPDATES FOR THE SAME SERIES WAIT FOR EACH OTHER HERE, SO THE WINNER COUNTS
+// READ AFTERWARDS ALWAYS INCLUDE THE RESULTS COMMITTED BY THE OTHER SCOREKEEPER
+func lockFixture(tx *sqlx.Tx, league string, competition string, playoffsId uuid.UUID) ([]models.PlayoffsModel, error) {
+	var locked []models.PlayoffsModel
+	query :=
+		`
+	SELECT p.*
+	FROM playoffs p
+	JOIN playoffs t
+	ON t.season = p.season
+	AND t.fixture_round = p.fixture_round
+	AND t.game_count = p.game_count
+	AND t.league = p.league
+	AND t.competition = p.competition
+	WHERE t.playoffs_id = $1
+	AND t.league = $2
+	AND t.competition = $3
+	ORDER BY p.playoffs_id
+	FOR UPDATE OF p
+	`
+	err := tx.Select(&locked, query, playoffsId, league, competition)
+	if err != nil {
+		log.Println("error locking playoffs fixture: ", err.Error())
+		return nil, err
+	}
+	return locked, nil
+}
+
+// RETURNS THE GAME WITH THE GIVEN ID FROM A LIST OF ROWS, NIL WHEN IT IS NOT IN THE LIST
+func findGame(games []models.PlayoffsModel, playoffsId uuid.UUID) *models.PlayoffsModel {
+	for i := range games {
+		if games[i].PlayoffsId == playoffsId {
+			return &games[i]
+		}
+	}
+	return nil
+}
+
+func selectGame(tx *sqlx.Tx, league string, competition string, playoffsId uuid.UUID) (*models.PlayoffsModel, error) {
+	var games []models.PlayoffsModel
+	query :=
+		`
+	SELECT * FROM playoffs WHERE playoffs_id = $1 AND league = $2 AND competition = $3
+	`
+	err := tx.Select(&games, query, playoffsId, league, competition)
+	if err != nil {
+		return nil, err
+	}
+	return findGame(games, playoffsId), nil
+}
+
+func (p *PlayoffsDBConnection) UpdatePlayoffsToNull(playoffsId uuid.UUID, round int, teamId uuid.UUID, season string, competition string) error {
 	playoffsListWinnerHome := []WinnerRes{}
 	playoffsListWinnerAway := []WinnerRes{}
 	query :=
 		`
 	UPDATE playoffs
-	SET winner = $1
+	SET winner = $1, version = version + 1
 	WHERE playoffs_id = $2
+	AND league = $3
+	AND competition = $4
+	AND archived_at IS NULL
 	`
 	querySelectHomeTeam :=
 		`
@@ -926,6 +795,8 @@ func (p *PlayoffsDBConnection) UpdatePlayoffsToNull(playoffsId uuid.UUID, round
 	WHERE winner = $1
 	AND season = $2
 	AND fixture_round = $3
+	AND league = $4
+	AND competition = $5
 	`
 	querySelectAwayTeam :=
 		`
@@ -934,22 +805,28 @@ func (p *PlayoffsDBConnection) UpdatePlayoffsToNull(playoffsId uuid.UUID, round
 	WHERE winner = $1
 	AND season = $2
 	AND fixture_round = $3
+	AND league = $4
+	AND competition = $5
 	`
 	queryUpdateNextRoundHome :=
 		`
 	UPDATE playoffs
-	SET home_team_id = $1, home_team_name = $2, home_team_url = $3, winner = $4
+	SET home_team_id = $1, home_team_name = $2, home_team_url = $3, winner = $4, version = version + 1
 	WHERE home_team_id = $5
 	AND fixture_round = $6 
 	AND season = $7
+	AND league = $8
+	AND competition = $9
 	`
 	queryUpdateNextRoundAway :=
 		`
 	UPDATE playoffs
-	SET away_team_id = $1, away_team_name = $2, away_team_url = $3, winner = $4
+	SET away_team_id = $1, away_team_name = $2, away_team_url = $3, winner = $4, version = version + 1
 	WHERE away_team_id = $5
 	AND fixture_round = $6
 	AND season = $7 
+	AND league = $8
+	AND competition = $9
 	`
 	tx, errTx := p.DB.Beginx()
 	if errTx != nil {
@@ -960,7 +837,17 @@ func (p *PlayoffsDBConnection) UpdatePlayoffsToNull(playoffsId uuid.UUID, round
 		_ = tx.Rollback()
 	}()
 
-	sqlRow, err := tx.Exec(query, nil, playoffsId)
+	league := p.LeagueOrDefault()
+	competition = competitionOrDefault(competition)
+	snapshot, errS := snapshotSeason(tx, league, competition, season, round)
+	if errS != nil {
+		return errS
+	}
+	locked, errL := lockFixture(tx, league, competition, playoffsId)
+	if errL != nil {
+		return errL
+	}
+	sqlRow, err := tx.Exec(query, nil, playoffsId, league, competition)
 	if err != nil {
 		return err
 	}
@@ -969,20 +856,20 @@ func (p *PlayoffsDBConnection) UpdatePlayoffsToNull(playoffsId uuid.UUID, round
 		return errR
 	}
 	if row == 0 {
-		return errors.New("could not update the requested record")
+		return newError(ErrNotFound, "could not update the requested record")
 	}
-	errSHome := tx.Select(&playoffsListWinnerHome, querySelectHomeTeam, teamId, season, round)
+	errSHome := tx.Select(&playoffsListWinnerHome, querySelectHomeTeam, teamId, season, round, league, competition)
 	if errSHome != nil {
 		return errSHome
 	}
-	errSAway := tx.Select(&playoffsListWinnerAway, querySelectAwayTeam, teamId, season, round)
+	errSAway := tx.Select(&playoffsListWinnerAway, querySelectAwayTeam, teamId, season, round, league, competition)
 	if errSAway != nil {
 		return errSAway
 	}
 	if len(playoffsListWinnerHome) < 2 {
 		if len(playoffsListWinnerHome) != 0 {
 			if playoffsListWinnerHome[0].Winner == teamId {
-				_, errUh := tx.Exec(queryUpdateNextRoundHome, nil, nil, nil, nil, teamId, round+1, season)
+				_, errUh := tx.Exec(queryUpdateNextRoundHome, nil, nil, nil, nil, teamId, round+1, season, league, competition)
 				if errUh != nil {
 					return errUh
 				}
@@ -994,7 +881,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffsToNull(playoffsId uuid.UUID, round
 	if len(playoffsListWinnerAway) < 2 {
 		if len(playoffsListWinnerAway) != 0 {
 			if playoffsListWinnerAway[0].Winner == teamId {
-				_, errUa := tx.Exec(queryUpdateNextRoundAway, nil, nil, nil, nil, teamId, round+1, season)
+				_, errUa := tx.Exec(queryUpdateNextRoundAway, nil, nil, nil, nil, teamId, round+1, season, league, competition)
 				if errUa != nil {
 					return errUa
 				}
@@ -1002,14 +889,38 @@ func (p *PlayoffsDBConnection) UpdatePlayoffsToNull(playoffsId uuid.UUID, round
 			}
 		}
 	}
+	changed, errS := snapshotSeason(tx, league, competition, season, round)
+	if errS != nil {
+		return errS
+	}
+	games, errCh := recordChanges(tx, league, competition, season, AuditUpdatePlayoffsToNull, snapshot, changed)
+	if errCh != nil {
+		return errCh
+	}
+	after, errA := selectGame(tx, league, competition, playoffsId)
+	if errA != nil {
+		return errA
+	}
+	if errAudit := p.writeAudit(tx, season, competition, &playoffsId, AuditUpdatePlayoffsToNull, findGame(locked, playoffsId), after); errAudit != nil {
+		return errAudit
+	}
+	if errAudit := p.writeCascadeAudit(tx, season, competition, playoffsId, AuditUpdatePlayoffsToNull, games); errAudit != nil {
+		return errAudit
+	}
+	change := models.BracketChangeModel{Season: season, Competition: competition, Operation: AuditUpdatePlayoffsToNull, PlayoffsId: &playoffsId, Games: games}
+	if errO := p.writeOutbox(tx, &change); errO != nil {
+		return errO
+	}
 	errC := tx.Commit()
 	if errC != nil {
 		return errC
 	}
+	p.notify(change)
 	return nil
 }
 
 func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs PlayoffsModelReqQuery) error {
+	competition := competitionOrDefault(playoffs.Competition)
 	var playCountInit []playCount
 	var playCountNextRound []playCount
 	var playoffsWinnerHome []models.PlayoffsModel
@@ -1020,6 +931,8 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 		FROM playoffs
 		WHERE season = $1
 		AND fixture_round = $2
+		AND league = $3
+		AND competition = $4
 		GROUP BY fixture_round, game_count
 		ORDER BY fixture_round,
 		 CASE
@@ -1031,8 +944,12 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 	query :=
 		`
 	UPDATE playoffs
-	SET winner = $1
+	SET winner = $1, version = version + 1
 	WHERE playoffs_id = $2
+	AND version = $3
+	AND league = $4
+	AND competition = $5
+	AND archived_at IS NULL
 	`
 	queryWinner :=
 		`
@@ -1041,26 +958,33 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 	WHERE winner = $1
 	AND fixture_round = $2
 	AND game_count = $3
+	AND season = $4
+	AND league = $5
+	AND competition = $6
 	`
 	queryUpdateNextRoundHome :=
 		`
 	UPDATE playoffs
 	SET
 	home_team_id = $1, home_team_name = $2,
-	home_team_url = $3
+	home_team_url = $3, version = version + 1
 	WHERE season = $4
 	AND fixture_round = $5
 	AND game_count = $6
+	AND league = $7
+	AND competition = $8
 	`
 	queryUpdateNextRoundAway :=
 		`
 	UPDATE playoffs
 	SET
 	away_team_id = $1, away_team_name = $2,
-	away_team_url = $3
+	away_team_url = $3, version = version + 1
 	WHERE season = $4
 	AND fixture_round = $5
 	AND game_count = $6
+	AND league = $7
+	AND competition = $8
 	`
 	tx, errTx := p.DB.Beginx()
 	if errTx != nil {
@@ -1069,7 +993,23 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 	defer func() {
 		_ = tx.Rollback()
 	}()
-	sqlRow, errU := tx.Exec(query, playoffs.Winner, playoffsId)
+
+	// LOCKING THE ROWS THE ENTRY CAN TOUCH AND THE SERIES BEFORE COUNTING ITS WINNERS, THEN REJECTING THE UPDATE IF
+	// THE CALLER READ A STALE VERSION
+	league := p.LeagueOrDefault()
+	snapshot, errS := snapshotSeason(tx, league, competition, playoffs.Season, playoffs.FixtureRound)
+	if errS != nil {
+		return errS
+	}
+	locked, errL := lockFixture(tx, league, competition, playoffsId)
+	if errL != nil {
+		return errL
+	}
+	before := findGame(locked, playoffsId)
+	if before != nil && before.Version != playoffs.Version {
+		return ErrPlayoffsConflict
+	}
+	sqlRow, errU := tx.Exec(query, playoffs.Winner, playoffsId, playoffs.Version, league, competition)
 	if errU != nil {
 		return errU
 	}
@@ -1078,21 +1018,21 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 		return errR
 	}
 	if row == 0 {
-		return errors.New("failed to update the requested row")
+		return newError(ErrNotFound, "failed to update the requested row")
 	}
-	errSH := tx.Select(&playoffsWinnerHome, queryWinner, playoffs.HomeTeamId, playoffs.FixtureRound, playoffs.GameCount)
+	errSH := tx.Select(&playoffsWinnerHome, queryWinner, playoffs.HomeTeamId, playoffs.FixtureRound, playoffs.GameCount, playoffs.Season, league, competition)
 	if errSH != nil {
 		return errSH
 	}
 
-	errSA := tx.Select(&playoffsWinnerAway, queryWinner, playoffs.AwayTeamId, playoffs.FixtureRound, playoffs.GameCount)
+	errSA := tx.Select(&playoffsWinnerAway, queryWinner, playoffs.AwayTeamId, playoffs.FixtureRound, playoffs.GameCount, playoffs.Season, league, competition)
 	if errSA != nil {
 		return errSA
 	}
 
 	// CONDITION IF THE LIST OF WINNER HAS TWO IDS OF TEAM IN THE HOME SIDE WHICH IS THE WINNING TEAM
 	if len(playoffsWinnerHome) == 2 {
-		errCount := tx.Select(&playCountInit, queryCount, playoffs.Season, playoffs.FixtureRound)
+		errCount := tx.Select(&playCountInit, queryCount, playoffs.Season, playoffs.FixtureRound, league, competition)
 		if errCount != nil {
 			return errCount
 		}
@@ -1128,7 +1068,9 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 					rowList[0].HomeTeamURL,
 					rowList[0].Season,
 					rowList[0].FixtureRound+1,
-					"FINAL")
+					"FINAL",
+					league,
+					competition)
 				if errUpdateFinal != nil {
 					return errUpdateFinal
 				}
@@ -1137,7 +1079,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 					return errU
 				}
 				if row == 0 {
-					return errors.New("failed to update the requested record, record does not exists")
+					return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 				}
 			}
 
@@ -1149,7 +1091,9 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 					rowList[1].HomeTeamURL,
 					rowList[1].Season,
 					rowList[1].FixtureRound+1,
-					"FINAL")
+					"FINAL",
+					league,
+					competition)
 				if errUpdateFinal != nil {
 					return errUpdateFinal
 				}
@@ -1158,13 +1102,13 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 					return errU
 				}
 				if row == 0 {
-					return errors.New("failed to update the requested record, record does not exists")
+					return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 				}
 			}
 
 			// NOW EXECUTING THE NEXT ROUND SINCE IT IS NOT THE FINALS
 		} else {
-			errCountNext := tx.Select(&playCountNextRound, queryCount, playoffs.Season, playoffs.FixtureRound+1)
+			errCountNext := tx.Select(&playCountNextRound, queryCount, playoffs.Season, playoffs.FixtureRound+1, league, competition)
 			if errCountNext != nil {
 				return errCountNext
 			}
@@ -1186,6 +1130,8 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 							newListFinal[index][0][0].Season,
 							playCountNextRound[index].FixtureRound,
 							playCountNextRoundFinal[index][0].GameCount,
+							league,
+							competition,
 						)
 						if errUpdateNextRound != nil {
 							return errUpdateNextRound
@@ -1196,7 +1142,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 							return errU
 						}
 						if row == 0 {
-							return errors.New("failed to update the requested record, record does not exists")
+							return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 						}
 					} else if newListFinal[index][0][1].HomeTeamId == playoffs.Winner && newListFinal[index][0][1].HomeTeamId.String() != "00000000-0000-0000-0000-000000000000" {
 						sqlRow, errUpdateNextRound := tx.Exec(
@@ -1206,7 +1152,9 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 							newListFinal[index][0][1].HomeTeamURL,
 							newListFinal[index][0][1].Season,
 							playCountNextRound[index].FixtureRound,
-							playCountNextRoundFinal[index][0].GameCount)
+							playCountNextRoundFinal[index][0].GameCount,
+							league,
+							competition)
 						if errUpdateNextRound != nil {
 							return errUpdateNextRound
 						}
@@ -1216,7 +1164,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 							return errU
 						}
 						if row == 0 {
-							return errors.New("failed to update the requested record, record does not exists")
+							return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 						}
 					} else if newListFinal[index][1][0].HomeTeamId == playoffs.Winner && newListFinal[index][1][0].HomeTeamId.String() != "00000000-0000-0000-0000-000000000000" {
 						sqlRow, errUpdateNextRound := tx.Exec(
@@ -1226,7 +1174,9 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 							newListFinal[index][1][0].HomeTeamURL,
 							newListFinal[index][1][0].Season,
 							playCountNextRound[index].FixtureRound,
-							playCountNextRoundFinal[index][1].GameCount)
+							playCountNextRoundFinal[index][1].GameCount,
+							league,
+							competition)
 						if errUpdateNextRound != nil {
 							return errUpdateNextRound
 						}
@@ -1235,7 +1185,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 							return errU
 						}
 						if row == 0 {
-							return errors.New("failed to update the requested record, record does not exists")
+							return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 						}
 					} else if newListFinal[index][1][1].HomeTeamId == playoffs.Winner && newListFinal[index][1][1].HomeTeamId.String() != "00000000-0000-0000-0000-000000000000" {
 						sqlRow, errUpdateNextRound := tx.Exec(
@@ -1246,6 +1196,8 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 							newListFinal[index][1][1].Season,
 							playCountNextRound[index].FixtureRound,
 							playCountNextRoundFinal[index][1].GameCount,
+							league,
+							competition,
 						)
 						if errUpdateNextRound != nil {
 							return errUpdateNextRound
@@ -1255,7 +1207,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 							return errU
 						}
 						if row == 0 {
-							return errors.New("failed to update the requested record, record does not exists")
+							return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 						}
 					}
 
@@ -1266,7 +1218,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 
 		// CONDITION IF THE LIST OF WINNER HAS TWO OR MORE IDS OF TEAM IN THE AWAY SIDE
 	} else if len(playoffsWinnerAway) == 2 {
-		errCount := tx.Select(&playCountInit, queryCount, playoffs.Season, playoffs.FixtureRound)
+		errCount := tx.Select(&playCountInit, queryCount, playoffs.Season, playoffs.FixtureRound, league, competition)
 		if errCount != nil {
 			return errCount
 		}
@@ -1299,7 +1251,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 					rowList[0].AwayTeamName,
 					rowList[0].AwayTeamURL,
 					rowList[0].Season,
-					rowList[0].FixtureRound+1, "FINAL")
+					rowList[0].FixtureRound+1, "FINAL", league, competition)
 				if errUpdateFinal != nil {
 					return errUpdateFinal
 				}
@@ -1308,7 +1260,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 					return errU
 				}
 				if row == 0 {
-					return errors.New("failed to update the requested record, record does not exists")
+					return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 				}
 			}
 
@@ -1319,7 +1271,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 					rowList[1].AwayTeamName,
 					rowList[1].AwayTeamURL,
 					rowList[1].Season,
-					rowList[1].FixtureRound+1, "FINAL")
+					rowList[1].FixtureRound+1, "FINAL", league, competition)
 				if errUpdateFinal != nil {
 					return errUpdateFinal
 				}
@@ -1328,13 +1280,13 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 					return errU
 				}
 				if row == 0 {
-					return errors.New("failed to update the requested record, record does not exists")
+					return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 				}
 			}
 
 		} else {
 
-			errCountNext := tx.Select(&playCountNextRound, queryCount, playoffs.Season, playoffs.FixtureRound+1)
+			errCountNext := tx.Select(&playCountNextRound, queryCount, playoffs.Season, playoffs.FixtureRound+1, league, competition)
 			if errCountNext != nil {
 				return errCountNext
 			}
@@ -1353,7 +1305,9 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 						newListFinal[index][0][0].AwayTeamURL,
 						newListFinal[index][0][0].Season,
 						playCountNextRound[index].FixtureRound,
-						playCountNextRoundFinal[index][0].GameCount)
+						playCountNextRoundFinal[index][0].GameCount,
+						league,
+						competition)
 
 					if errUpdateNextRound != nil {
 						return errUpdateNextRound
@@ -1363,7 +1317,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 						return errU
 					}
 					if row == 0 {
-						return errors.New("failed to update the requested record, record does not exists")
+						return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 					}
 				} else if newListFinal[index][0][1].AwayTeamId == playoffs.Winner && newListFinal[index][0][1].AwayTeamId.String() != "00000000-0000-0000-0000-000000000000" {
 					sqlRow, errUpdateNextRound := tx.Exec(
@@ -1373,7 +1327,9 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 						newListFinal[index][0][1].AwayTeamURL,
 						newListFinal[index][0][1].Season,
 						playCountNextRound[index].FixtureRound,
-						playCountNextRoundFinal[index][0].GameCount)
+						playCountNextRoundFinal[index][0].GameCount,
+						league,
+						competition)
 
 					if errUpdateNextRound != nil {
 						return errUpdateNextRound
@@ -1383,7 +1339,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 						return errU
 					}
 					if row == 0 {
-						return errors.New("failed to update the requested record, record does not exists")
+						return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 					}
 				} else if newListFinal[index][1][0].AwayTeamId == playoffs.Winner && newListFinal[index][1][0].AwayTeamId.String() != "00000000-0000-0000-0000-000000000000" {
 					sqlRow, errUpdateNextRound := tx.Exec(
@@ -1393,7 +1349,9 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 						newListFinal[index][1][0].AwayTeamURL,
 						newListFinal[index][1][0].Season,
 						playCountNextRound[index].FixtureRound,
-						playCountNextRoundFinal[index][1].GameCount)
+						playCountNextRoundFinal[index][1].GameCount,
+						league,
+						competition)
 
 					if errUpdateNextRound != nil {
 						return errUpdateNextRound
@@ -1404,7 +1362,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 						return errU
 					}
 					if row == 0 {
-						return errors.New("failed to update the requested record, record does not exists")
+						return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 					}
 				} else if newListFinal[index][1][1].AwayTeamId == playoffs.Winner && newListFinal[index][1][1].AwayTeamId.String() != "00000000-0000-0000-0000-000000000000" {
 					sqlRow, errUpdateNextRound := tx.Exec(
@@ -1414,7 +1372,9 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 						newListFinal[index][1][1].AwayTeamURL,
 						newListFinal[index][1][1].Season,
 						playCountNextRound[index].FixtureRound,
-						playCountNextRoundFinal[index][1].GameCount)
+						playCountNextRoundFinal[index][1].GameCount,
+						league,
+						competition)
 					if errUpdateNextRound != nil {
 						return errUpdateNextRound
 					}
@@ -1423,7 +1383,7 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 						return errU
 					}
 					if row == 0 {
-						return errors.New("failed to update the requested record, record does not exists")
+						return newError(ErrNotFound, "failed to update the requested record, record does not exists")
 					}
 				}
 
@@ -1431,27 +1391,47 @@ func (p *PlayoffsDBConnection) UpdatePlayoffs(playoffsId uuid.UUID, playoffs Pla
 		}
 
 	}
+	changed, errS := snapshotSeason(tx, league, competition, playoffs.Season, playoffs.FixtureRound)
+	if errS != nil {
+		return errS
+	}
+	games, errCh := recordChanges(tx, league, competition, playoffs.Season, AuditUpdatePlayoffs, snapshot, changed)
+	if errCh != nil {
+		return errCh
+	}
+	after, errA := selectGame(tx, league, competition, playoffsId)
+	if errA != nil {
+		return errA
+	}
+	if errAudit := p.writeAudit(tx, playoffs.Season, competition, &playoffsId, AuditUpdatePlayoffs, before, after); errAudit != nil {
+		return errAudit
+	}
+	if errAudit := p.writeCascadeAudit(tx, playoffs.Season, competition, playoffsId, AuditUpdatePlayoffs, games); errAudit != nil {
+		return errAudit
+	}
+	change := models.BracketChangeModel{Season: playoffs.Season, Competition: competition, Operation: AuditUpdatePlayoffs, PlayoffsId: &playoffsId, Games: games}
+	if errO := p.writeOutbox(tx, &change); errO != nil {
+		return errO
+	}
 	errC := tx.Commit()
 	if errC != nil {
 		log.Println("failed to commit playoffs tx: ", errC.Error())
 		return errC
 	}
+	p.notify(change)
 
 	return nil
 }
 
-func (p *PlayoffsDBConnection) DeletePlayoffs(season string) error {
-	query :=
-		`
-	DELETE FROM playoffs WHERE season = $1
-	`
-	sqlRow, err := p.Exec(query, season)
+// DELETING A SEASON ONLY ARCHIVES IT, THE ROWS ARE HIDDEN FROM ListPlayoffs BUT CAN BE BROUGHT BACK WITH
+// RestorePlayoffs. PurgePlayoffs REMOVES THEM PERMANENTLY
+func (p *PlayoffsDBConnection) DeletePlayoffs(season string, competition string) error {
+	archived, err := p.archivePlayoffs(season, competition, AuditDeletePlayoffs)
 	if err != nil {
 		return err
 	}
-	row, _ := sqlRow.RowsAffected()
-	if row == 0 {
-		return errors.New("could not delete the requested records. Records of season" + season + " do not exists")
+	if archived == 0 {
+		return newError(ErrNotFound, "could not delete the requested records. Records of season"+season+" do not exists")
 	}
 	return nil
 }
@@ -0,0 +1,68 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+)
+
+// RUNS THE SELECTION AND PAIRING OF CreatePlayoffs AGAINST THE CURRENT STANDINGS AND RETURNS THE BRACKET IT WOULD
+// CREATE, WITHOUT WRITING IT. THE SAME INPUT ERRORS ARE RETURNED, SO A PREVIEW THAT SUCCEEDS CAN BE CREATED AS LONG
+// AS THE STANDINGS DO NOT CHANGE
+func (p *PlayoffsDBConnection) PreviewPlayoffs(conferences []string, season string, competition string, limit int) (models.PlayoffsPreviewModel, error) {
+	competition = competitionOrDefault(competition)
+	tx, errTx := p.DB.BeginTxx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if errTx != nil {
+		log.Println("error creating playoffs preview tx: ", errTx.Error())
+		return models.PlayoffsPreviewModel{}, errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	games, err := p.planPlayoffs(tx, conferences, season, competition, limit)
+	if err != nil {
+		return models.PlayoffsPreviewModel{}, err
+	}
+	bracket := groupPlayoffs(games)
+	return models.PlayoffsPreviewModel{
+		Season:      season,
+		Competition: competition,
+		Conferences: conferences,
+		Limit:       limit,
+		Qualified:   QualifiedTeams(bracket),
+		Rows:        len(games),
+		Bracket:     bracket,
+	}, nil
+}
+
+// GROUPS GAMES INTO ROUNDS AND SERIES LIKE ListPlayoffs: ROUNDS BY FIXTURE ROUND, THE SERIES OF A ROUND AND THEIR
+// GAMES IN THE ORDER THEY COME. EVERY GAME MUST HAVE A fixture_round AND A game_count, checkGames LEAVES OUT THE
+// OTHERS
+func groupPlayoffs(games []models.PlayoffsModel) [][][]models.PlayoffsModel {
+	type seriesKey struct {
+		round int
+		count string
+	}
+	var order []seriesKey
+	series := map[seriesKey][]models.PlayoffsModel{}
+	for _, g := range games {
+		k := seriesKey{*g.FixtureRound, *g.GameCount}
+		if _, ok := series[k]; !ok {
+			order = append(order, k)
+		}
+		series[k] = append(series[k], g)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].round < order[j].round })
+	playoffs := [][][]models.PlayoffsModel{}
+	for i, k := range order {
+		if i == 0 || order[i-1].round != k.round {
+			playoffs = append(playoffs, [][]models.PlayoffsModel{})
+		}
+		last := len(playoffs) - 1
+		playoffs[last] = append(playoffs[last], series[k])
+	}
+	return playoffs
+}
@@ -0,0 +1,74 @@
+package queries
+
+import (
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPreviewPlayoffs_Success tests that the preview pairs the teams like CreatePlayoffs and inserts nothing
+func (suite *PlayoffsTestSuite) TestPreviewPlayoffs_Success() {
+	season := "2023-2024"
+	teams := formatTeams("Team1", "Team2", "Team3", "Team4")
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	standings := sqlmock.NewRows([]string{"team_id", "team_name", "conference", "season", "pts", "position"})
+	for i, t := range teams {
+		standings.AddRow(t.id, t.name, "Main", season, 100-i, i+1)
+	}
+	suite.mock.ExpectQuery(`SELECT \*, RANK\(\)`).
+		WithArgs("Main", season, 4, DefaultLeague).
+		WillReturnRows(standings)
+	suite.mock.ExpectRollback()
+
+	preview, err := suite.conn.PreviewPlayoffs([]string{"Main"}, season, "", 4)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2*SeriesGames+FinalGames, preview.Rows)
+	if assert.Len(suite.T(), preview.Bracket, 2) {
+		assert.Len(suite.T(), preview.Bracket[0], 2)
+		assert.Len(suite.T(), preview.Bracket[0][0], SeriesGames)
+		assert.Equal(suite.T(), "FINAL", *preview.Bracket[1][0][0].GameCount)
+		assert.Nil(suite.T(), preview.Bracket[1][0][0].HomeTeamId)
+	}
+	var seeds []string
+	for _, t := range preview.Qualified {
+		seeds = append(seeds, t.TeamName)
+	}
+	assert.Equal(suite.T(), []string{"Team1", "Team2", "Team3", "Team4"}, seeds)
+	assert.Equal(suite.T(), "Team4", *preview.Bracket[0][0][0].AwayTeamName)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestPreviewPlayoffs_AlreadyExists tests that the preview refuses what CreatePlayoffs would refuse
+func (suite *PlayoffsTestSuite) TestPreviewPlayoffs_AlreadyExists() {
+	season := "2023-2024"
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE season = \$1`).
+		WithArgs(season, DefaultLeague, "cup").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	suite.mock.ExpectRollback()
+
+	_, err := suite.conn.PreviewPlayoffs([]string{"Main"}, season, "cup", 4)
+
+	assert.True(suite.T(), errors.Is(err, ErrAlreadyExists))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestGroupPlayoffs_FinalOnly tests that a final of round 0 comes before the empty final CreatePlayoffs adds
+func (suite *PlayoffsTestSuite) TestGroupPlayoffs_FinalOnly() {
+	zero, one, final := 0, 1, "FINAL"
+	games := append(importedGames("2023-2024"), importedGames("2023-2024")...)
+	games[0].FixtureRound, games[0].GameCount = &one, &final
+	games[1].FixtureRound, games[1].GameCount = &zero, &final
+
+	playoffs := groupPlayoffs(games)
+
+	assert.Len(suite.T(), playoffs, 2)
+	assert.Equal(suite.T(), 0, *playoffs[0][0][0].FixtureRound)
+}
@@ -0,0 +1,94 @@
+package queries
+
+import (
+	"log"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// OPERATION RECORDED IN THE AUDIT LOG WHEN THE DATE OF A GAME IS SET OR CLEARED
+const AuditSchedulePlayoffs = "SCHEDULE_PLAYOFFS"
+
+// SETS THE DATE A GAME IS PLAYED, OR CLEARS IT WHEN scheduledAt IS NIL. THE VERSION OF THE GAME IS INCREASED SO
+// CLIENTS HOLDING THE GAME SEE IT CHANGED
+func (p *PlayoffsDBConnection) SchedulePlayoffs(playoffsId uuid.UUID, scheduledAt *time.Time) (models.PlayoffsModel, error) {
+	var games []models.PlayoffsModel
+	querySelect :=
+		`
+	SELECT * FROM playoffs WHERE playoffs_id = $1 AND league = $2 AND archived_at IS NULL FOR UPDATE
+	`
+	query :=
+		`
+	UPDATE playoffs
+	SET scheduled_at = $1, version = version + 1
+	WHERE playoffs_id = $2
+	AND league = $3
+	RETURNING *
+	`
+	if scheduledAt != nil {
+		utc := scheduledAt.UTC()
+		scheduledAt = &utc
+	}
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return models.PlayoffsModel{}, errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if err := tx.Select(&games, querySelect, playoffsId, p.LeagueOrDefault()); err != nil {
+		log.Println("error SELECTING the game to schedule: ", err.Error())
+		return models.PlayoffsModel{}, err
+	}
+	if len(games) == 0 {
+		return models.PlayoffsModel{}, newError(ErrNotFound, "game "+playoffsId.String()+" does not exist or is archived")
+	}
+	before := games[0]
+	var after models.PlayoffsModel
+	if err := tx.Get(&after, query, scheduledAt, playoffsId, p.LeagueOrDefault()); err != nil {
+		log.Println("failed to schedule the game: ", err.Error())
+		return models.PlayoffsModel{}, err
+	}
+	if errAudit := p.writeAudit(tx, after.Season, after.Competition, &playoffsId, AuditSchedulePlayoffs, before, after); errAudit != nil {
+		return models.PlayoffsModel{}, errAudit
+	}
+	change := models.BracketChangeModel{
+		Season:      after.Season,
+		Competition: after.Competition,
+		Operation:   AuditSchedulePlayoffs,
+		PlayoffsId:  &playoffsId,
+		Games:       []models.GameChangeModel{{Before: before, After: after}},
+	}
+	if errO := p.writeOutbox(tx, &change); errO != nil {
+		return models.PlayoffsModel{}, errO
+	}
+	if errC := tx.Commit(); errC != nil {
+		return models.PlayoffsModel{}, errC
+	}
+	p.notify(change)
+	return after, nil
+}
+
+// LISTS THE GAMES A TEAM PLAYS OR IS SCHEDULED TO PLAY IN THE BRACKETS OF THE LEAGUE THAT ARE NOT ARCHIVED, BY
+// DATE. AN EMPTY season LISTS EVERY SEASON
+func (p *PlayoffsDBConnection) ListTeamGames(teamId uuid.UUID, season string) ([]models.PlayoffsModel, error) {
+	games := []models.PlayoffsModel{}
+	query :=
+		`
+	SELECT * FROM playoffs
+	WHERE league = $1
+	AND (home_team_id = $2 OR away_team_id = $2)
+	AND ($3 = '' OR season = $3)
+	AND archived_at IS NULL
+	ORDER BY scheduled_at ASC NULLS LAST, season ASC, fixture_round ASC, game_round ASC
+	`
+	err := p.DB.Select(&games, query, p.LeagueOrDefault(), teamId, season)
+	if err != nil {
+		log.Println("error SELECTING the games of team ", teamId.String(), ": ", err.Error())
+		return []models.PlayoffsModel{}, err
+	}
+	return games, nil
+}
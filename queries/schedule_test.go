@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchedulePlayoffs_Success tests setting the date of a game in UTC
+func (suite *PlayoffsTestSuite) TestSchedulePlayoffs_Success() {
+	season := "2023-2024"
+	playoffsID := uuid.New()
+	at := time.Date(2024, 4, 20, 21, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	columns := []string{"playoffs_id", "season", "competition", "version", "scheduled_at"}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE playoffs_id = \$1 AND league = \$2 AND archived_at IS NULL FOR UPDATE`).
+		WithArgs(playoffsID, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(playoffsID, season, DefaultCompetition, 2, nil))
+	suite.mock.ExpectQuery(`UPDATE playoffs SET scheduled_at = \$1, version = version \+ 1 WHERE playoffs_id = \$2 AND league = \$3 RETURNING \*`).
+		WithArgs(at.UTC(), playoffsID, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(playoffsID, season, DefaultCompetition, 3, at.UTC()))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).
+		WithArgs(sqlmock.AnyArg(), season, DefaultLeague, DefaultCompetition, playoffsID, AuditSchedulePlayoffs, "unknown", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.expectOutbox(season, AuditSchedulePlayoffs)
+	suite.mock.ExpectCommit()
+
+	var notified []models.BracketChangeModel
+	suite.conn.OnChange = func(change models.BracketChangeModel) {
+		notified = append(notified, change)
+	}
+	game, err := suite.conn.SchedulePlayoffs(playoffsID, &at)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, game.Version)
+	require.NotNil(suite.T(), game.ScheduledAt)
+	assert.True(suite.T(), at.Equal(*game.ScheduledAt))
+	require.Len(suite.T(), notified, 1)
+	assert.Nil(suite.T(), notified[0].Games[0].Before.ScheduledAt)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestSchedulePlayoffs_NotFound tests scheduling a game that does not exist
+func (suite *PlayoffsTestSuite) TestSchedulePlayoffs_NotFound() {
+	playoffsID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE playoffs_id = \$1`).
+		WithArgs(playoffsID, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectRollback()
+
+	_, err := suite.conn.SchedulePlayoffs(playoffsID, nil)
+
+	assert.ErrorIs(suite.T(), err, ErrNotFound)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestListTeamGames_Success tests listing the games of a team in one season
+func (suite *PlayoffsTestSuite) TestListTeamGames_Success() {
+	teamID := uuid.New()
+
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE league = \$1 AND \(home_team_id = \$2 OR away_team_id = \$2\) AND \(\$3 = '' OR season = \$3\) AND archived_at IS NULL ORDER BY scheduled_at ASC NULLS LAST`).
+		WithArgs(DefaultLeague, teamID, "2023-2024").
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "home_team_id"}).AddRow(uuid.New(), teamID).AddRow(uuid.New(), teamID))
+
+	games, err := suite.conn.ListTeamGames(teamID, "2023-2024")
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), games, 2)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,216 @@
+package queries
+
+import (
+	"log"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// CHECKS THE FIELDS EVERY STANDINGS ROW NEEDS TO BE PICKED UP BY CreatePlayoffs. THE ERROR IS AN ErrInvalidInput
+func ValidateStandings(standings models.StandingsModel) error {
+	if standings.TeamName == "" || standings.Conference == "" || standings.Season == "" {
+		return newError(ErrInvalidInput, "invalid standings record. teamName, conference and season are required")
+	}
+	if standings.Gp < 0 || standings.W < 0 || standings.L < 0 || standings.Gf < 0 {
+		return newError(ErrInvalidInput, "invalid standings record of team "+standings.TeamName+". gp, w, l and gf can not be negative")
+	}
+	return nil
+}
+
+func (p *PlayoffsDBConnection) CreateStandings(standings models.StandingsModel) (models.StandingsModel, error) {
+	query :=
+		`
+	INSERT INTO standings
+	(standings_id, team_id, position, team_name, acronym, team_pic_url, gp, w, l, win_percentage, gf, pts, conference, season, league)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	if err := ValidateStandings(standings); err != nil {
+		return models.StandingsModel{}, err
+	}
+	standings.StandingsId = uuid.New()
+	standings.League = p.LeagueOrDefault()
+	_, err := p.DB.Exec(
+		query,
+		standings.StandingsId,
+		standings.TeamId,
+		standings.Position,
+		standings.TeamName,
+		standings.Acronym,
+		standings.TeamPicUrl,
+		standings.Gp,
+		standings.W,
+		standings.L,
+		standings.WinPercentage,
+		standings.Gf,
+		standings.Pts,
+		standings.Conference,
+		standings.Season,
+		standings.League,
+	)
+	if err != nil {
+		log.Println("failed to INSERT standings record: ", err.Error())
+		return models.StandingsModel{}, err
+	}
+	return standings, nil
+}
+
+// INSERTS SEVERAL STANDINGS RECORDS IN ONE TRANSACTION. EVERY RECORD IS VALIDATED FIRST, SO A FILE WITH ONE
+// BAD ROW IMPORTS NOTHING
+func (p *PlayoffsDBConnection) ImportStandings(standings []models.StandingsModel) ([]models.StandingsModel, error) {
+	query :=
+		`
+	INSERT INTO standings
+	(standings_id, team_id, position, team_name, acronym, team_pic_url, gp, w, l, win_percentage, gf, pts, conference, season, league)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	if len(standings) == 0 {
+		return []models.StandingsModel{}, newError(ErrInvalidInput, "no standings records to import")
+	}
+	for i, s := range standings {
+		if err := ValidateStandings(s); err != nil {
+			return []models.StandingsModel{}, newError(ErrInvalidInput, "record "+strconv.Itoa(i+1)+": "+err.Error())
+		}
+	}
+	tx, errTx := p.DB.Beginx()
+	if errTx != nil {
+		return []models.StandingsModel{}, errTx
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	imported := make([]models.StandingsModel, 0, len(standings))
+	for _, s := range standings {
+		s.StandingsId = uuid.New()
+		s.League = p.LeagueOrDefault()
+		_, err := tx.Exec(
+			query,
+			s.StandingsId,
+			s.TeamId,
+			s.Position,
+			s.TeamName,
+			s.Acronym,
+			s.TeamPicUrl,
+			s.Gp,
+			s.W,
+			s.L,
+			s.WinPercentage,
+			s.Gf,
+			s.Pts,
+			s.Conference,
+			s.Season,
+			s.League,
+		)
+		if err != nil {
+			log.Println("failed to INSERT standings record: ", err.Error())
+			return []models.StandingsModel{}, err
+		}
+		imported = append(imported, s)
+	}
+	if errC := tx.Commit(); errC != nil {
+		return []models.StandingsModel{}, errC
+	}
+	return imported, nil
+}
+
+// LISTS THE STANDINGS OF A SEASON BY CONFERENCE AND POINTS. AN EMPTY conference RETURNS EVERY CONFERENCE
+func (p *PlayoffsDBConnection) ListStandings(season string, conference string) ([]models.StandingsModel, error) {
+	standings := []models.StandingsModel{}
+	query :=
+		`
+	SELECT * FROM standings
+	WHERE season = $1
+	AND league = $2
+	AND ($3 = '' OR conference = $3)
+	ORDER BY conference ASC, pts DESC
+	`
+	err := p.DB.Select(&standings, query, season, p.LeagueOrDefault(), conference)
+	if err != nil {
+		log.Println("error SELECTING standings: ", err.Error())
+		return []models.StandingsModel{}, err
+	}
+	return standings, nil
+}
+
+func (p *PlayoffsDBConnection) GetStandings(standingsId uuid.UUID) (models.StandingsModel, error) {
+	var standings []models.StandingsModel
+	query :=
+		`
+	SELECT * FROM standings WHERE standings_id = $1 AND league = $2
+	`
+	err := p.DB.Select(&standings, query, standingsId, p.LeagueOrDefault())
+	if err != nil {
+		log.Println("error SELECTING standings record: ", err.Error())
+		return models.StandingsModel{}, err
+	}
+	if len(standings) == 0 {
+		return models.StandingsModel{}, newError(ErrNotFound, "standings record "+standingsId.String()+" does not exists")
+	}
+	return standings[0], nil
+}
+
+func (p *PlayoffsDBConnection) UpdateStandings(standingsId uuid.UUID, standings models.StandingsModel) error {
+	query :=
+		`
+	UPDATE standings
+	SET team_id = $1, position = $2, team_name = $3, acronym = $4, team_pic_url = $5,
+	gp = $6, w = $7, l = $8, win_percentage = $9, gf = $10, pts = $11, conference = $12, season = $13
+	WHERE standings_id = $14
+	AND league = $15
+	`
+	if err := ValidateStandings(standings); err != nil {
+		return err
+	}
+	sqlRow, err := p.DB.Exec(
+		query,
+		standings.TeamId,
+		standings.Position,
+		standings.TeamName,
+		standings.Acronym,
+		standings.TeamPicUrl,
+		standings.Gp,
+		standings.W,
+		standings.L,
+		standings.WinPercentage,
+		standings.Gf,
+		standings.Pts,
+		standings.Conference,
+		standings.Season,
+		standingsId,
+		p.LeagueOrDefault(),
+	)
+	if err != nil {
+		log.Println("failed to UPDATE standings record: ", err.Error())
+		return err
+	}
+	row, errR := sqlRow.RowsAffected()
+	if errR != nil {
+		return errR
+	}
+	if row == 0 {
+		return newError(ErrNotFound, "could not update the requested record. standings record "+standingsId.String()+" does not exists")
+	}
+	return nil
+}
+
+func (p *PlayoffsDBConnection) DeleteStandings(standingsId uuid.UUID) error {
+	query :=
+		`
+	DELETE FROM standings WHERE standings_id = $1 AND league = $2
+	`
+	sqlRow, err := p.DB.Exec(query, standingsId, p.LeagueOrDefault())
+	if err != nil {
+		log.Println("failed to DELETE standings record: ", err.Error())
+		return err
+	}
+	row, errR := sqlRow.RowsAffected()
+	if errR != nil {
+		return errR
+	}
+	if row == 0 {
+		return newError(ErrNotFound, "could not delete the requested record. standings record "+standingsId.String()+" does not exists")
+	}
+	return nil
+}
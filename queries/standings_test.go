@@ -0,0 +1,134 @@
+package queries
+
+import (
+	"errors"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateStandings_Success tests inserting a standings record in the connection's league
+func (suite *PlayoffsTestSuite) TestCreateStandings_Success() {
+	standings := models.StandingsModel{TeamName: "Lions", Conference: "East", Season: "2023-2024", Gp: 10, W: 7, L: 3, Pts: 21}
+
+	suite.mock.ExpectExec(`INSERT INTO standings`).
+		WithArgs(sqlmock.AnyArg(), nil, 0, "Lions", "", nil, 10, 7, 3, 0.0, 0, 21, "East", "2023-2024", DefaultLeague).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	created, err := suite.conn.CreateStandings(standings)
+
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), uuid.Nil, created.StandingsId)
+	assert.Equal(suite.T(), DefaultLeague, created.League)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreateStandings_InvalidInput tests that a record without a conference is rejected before reaching the database
+func (suite *PlayoffsTestSuite) TestCreateStandings_InvalidInput() {
+	_, err := suite.conn.CreateStandings(models.StandingsModel{TeamName: "Lions", Season: "2023-2024"})
+
+	assert.Error(suite.T(), err)
+	assert.True(suite.T(), errors.Is(err, ErrInvalidInput))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportStandings_Success tests inserting several standings records in one transaction
+func (suite *PlayoffsTestSuite) TestImportStandings_Success() {
+	standings := []models.StandingsModel{
+		{TeamName: "Lions", Conference: "East", Season: "2023-2024", Pts: 21},
+		{TeamName: "Tigers", Conference: "East", Season: "2023-2024", Pts: 18},
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`INSERT INTO standings`).
+		WithArgs(sqlmock.AnyArg(), nil, 0, "Lions", "", nil, 0, 0, 0, 0.0, 0, 21, "East", "2023-2024", DefaultLeague).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO standings`).
+		WithArgs(sqlmock.AnyArg(), nil, 0, "Tigers", "", nil, 0, 0, 0, 0.0, 0, 18, "East", "2023-2024", DefaultLeague).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+
+	imported, err := suite.conn.ImportStandings(standings)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), imported, 2)
+	assert.NotEqual(suite.T(), imported[0].StandingsId, imported[1].StandingsId)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportStandings_InvalidRecord tests that one invalid record rejects the whole import before reaching the database
+func (suite *PlayoffsTestSuite) TestImportStandings_InvalidRecord() {
+	standings := []models.StandingsModel{
+		{TeamName: "Lions", Conference: "East", Season: "2023-2024"},
+		{TeamName: "Tigers", Season: "2023-2024"},
+	}
+
+	_, err := suite.conn.ImportStandings(standings)
+
+	assert.True(suite.T(), errors.Is(err, ErrInvalidInput))
+	assert.Contains(suite.T(), err.Error(), "record 2")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestListStandings_Success tests listing the standings of one conference
+func (suite *PlayoffsTestSuite) TestListStandings_Success() {
+	suite.mock.ExpectQuery(`SELECT \* FROM standings WHERE season = \$1 AND league = \$2 AND \(\$3 = '' OR conference = \$3\) ORDER BY conference ASC, pts DESC`).
+		WithArgs("2023-2024", DefaultLeague, "East").
+		WillReturnRows(sqlmock.NewRows([]string{"standings_id", "team_name", "conference", "season", "pts"}).
+			AddRow(uuid.New(), "Lions", "East", "2023-2024", 21).
+			AddRow(uuid.New(), "Tigers", "East", "2023-2024", 18))
+
+	result, err := suite.conn.ListStandings("2023-2024", "East")
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), "Lions", result[0].TeamName)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestGetStandings_NotFound tests reading a standings record of another league or that does not exist
+func (suite *PlayoffsTestSuite) TestGetStandings_NotFound() {
+	standingsID := uuid.New()
+
+	suite.mock.ExpectQuery(`SELECT \* FROM standings WHERE standings_id = \$1 AND league = \$2`).
+		WithArgs(standingsID, DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"standings_id"}))
+
+	_, err := suite.conn.GetStandings(standingsID)
+
+	assert.True(suite.T(), errors.Is(err, ErrNotFound))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestUpdateStandings_Success tests updating a standings record
+func (suite *PlayoffsTestSuite) TestUpdateStandings_Success() {
+	standingsID := uuid.New()
+	standings := models.StandingsModel{TeamName: "Lions", Conference: "East", Season: "2023-2024", Pts: 24}
+
+	suite.mock.ExpectExec(`UPDATE standings SET team_id = \$1`).
+		WithArgs(nil, 0, "Lions", "", nil, 0, 0, 0, 0.0, 0, 24, "East", "2023-2024", standingsID, DefaultLeague).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.conn.UpdateStandings(standingsID, standings)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestDeleteStandings_NotFound tests deleting a standings record that does not exist
+func (suite *PlayoffsTestSuite) TestDeleteStandings_NotFound() {
+	standingsID := uuid.New()
+
+	suite.mock.ExpectExec(`DELETE FROM standings WHERE standings_id = \$1 AND league = \$2`).
+		WithArgs(standingsID, DefaultLeague).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.conn.DeleteStandings(standingsID)
+
+	assert.True(suite.T(), errors.Is(err, ErrNotFound))
+	assert.Contains(suite.T(), err.Error(), "does not exists")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
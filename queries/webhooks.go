@@ -0,0 +1,198 @@
+package queries
+
+import (
+	"log"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// SUBSCRIBES url TO THE GIVEN EVENT TYPES OF THE CONNECTION'S LEAGUE. NO EVENT TYPE SUBSCRIBES TO ALL OF THEM.
+// THE SECRET SIGNS EVERY PAYLOAD SO THE RECEIVER CAN CHECK IT CAME FROM US
+func (p *PlayoffsDBConnection) CreateWebhook(webhookURL string, secret string, eventTypes []string) (models.WebhookModel, error) {
+	query :=
+		`
+	INSERT INTO webhooks
+	(webhook_id, league, url, secret, event_types)
+	VALUES($1, $2, $3, $4, $5)
+	RETURNING *
+	`
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return models.WebhookModel{}, newError(ErrInvalidInput, "invalid webhook url "+webhookURL+". An absolute http or https url is required")
+	}
+	if !publicHost(parsed.Hostname()) {
+		return models.WebhookModel{}, newError(ErrInvalidInput, "invalid webhook url "+webhookURL+". The host must be public, not loopback or private")
+	}
+	if secret == "" {
+		return models.WebhookModel{}, newError(ErrInvalidInput, "invalid webhook. A secret is required to sign the payloads")
+	}
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	var created []models.WebhookModel
+	errI := p.DB.Select(&created, query, uuid.New(), p.LeagueOrDefault(), webhookURL, secret, pq.StringArray(eventTypes))
+	if errI != nil {
+		log.Println("failed to INSERT webhooks record: ", errI.Error())
+		return models.WebhookModel{}, errI
+	}
+	return created[0], nil
+}
+
+// RANGES THAT ARE NOT ROUTED ON THE INTERNET BESIDES THE ONES netip.Addr REPORTS (PRIVATE, LOOPBACK, LINK-LOCAL...)
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// REPORTS WHETHER A WEBHOOK MAY BE SENT TO addr. THE DISPATCHER CHECKS EVERY ADDRESS IT DIALS WITH IT SO A WEBHOOK
+// CAN NOT REACH THE SERVER ITSELF OR ITS PRIVATE NETWORK, WHATEVER ITS HOST NAME RESOLVES TO
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// REJECTS THE HOSTS KNOWN TO BE LOCAL WITHOUT RESOLVING THEM: LITERAL ADDRESSES THAT ARE NOT PUBLIC AND localhost.
+// OTHER NAMES ARE CHECKED ON EVERY DELIVERY, ONCE RESOLVED
+func publicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return PublicAddress(addr)
+	}
+	return true
+}
+
+// LISTS THE WEBHOOKS OF THE CONNECTION'S LEAGUE WITHOUT THEIR SECRETS
+func (p *PlayoffsDBConnection) ListWebhooks() ([]models.WebhookModel, error) {
+	webhooks := []models.WebhookModel{}
+	query :=
+		`
+	SELECT webhook_id, league, url, event_types, created_at FROM webhooks WHERE league = $1 ORDER BY created_at ASC
+	`
+	err := p.DB.Select(&webhooks, query, p.LeagueOrDefault())
+	if err != nil {
+		log.Println("error SELECTING webhooks: ", err.Error())
+		return []models.WebhookModel{}, err
+	}
+	return webhooks, nil
+}
+
+// LISTS THE WEBHOOKS OF THE CONNECTION'S LEAGUE SUBSCRIBED TO eventType, WITH THEIR SECRETS FOR SIGNING
+func (p *PlayoffsDBConnection) ListWebhooksForEvent(eventType string) ([]models.WebhookModel, error) {
+	webhooks := []models.WebhookModel{}
+	query :=
+		`
+	SELECT * FROM webhooks
+	WHERE league = $1
+	AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))
+	`
+	err := p.DB.Select(&webhooks, query, p.LeagueOrDefault(), eventType)
+	if err != nil {
+		log.Println("error SELECTING webhooks for event: ", err.Error())
+		return []models.WebhookModel{}, err
+	}
+	return webhooks, nil
+}
+
+// DELETES THE WEBHOOK AND ITS DELIVERY LOG
+func (p *PlayoffsDBConnection) DeleteWebhook(webhookId uuid.UUID) error {
+	query :=
+		`
+	DELETE FROM webhooks WHERE webhook_id = $1 AND league = $2
+	`
+	sqlRow, err := p.DB.Exec(query, webhookId, p.LeagueOrDefault())
+	if err != nil {
+		log.Println("failed to DELETE webhooks record: ", err.Error())
+		return err
+	}
+	row, errR := sqlRow.RowsAffected()
+	if errR != nil {
+		return errR
+	}
+	if row == 0 {
+		return newError(ErrNotFound, "could not delete the requested record. webhook "+webhookId.String()+" does not exists")
+	}
+	return nil
+}
+
+// APPENDS AN ATTEMPT TO THE DELIVERY LOG
+func (p *PlayoffsDBConnection) RecordWebhookDelivery(delivery models.WebhookDeliveryModel) error {
+	query :=
+		`
+	INSERT INTO webhook_deliveries
+	(delivery_id, webhook_id, event_id, event_type, payload, attempt, status_code, error, delivered)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := p.DB.Exec(
+		query,
+		uuid.New(),
+		delivery.WebhookId,
+		delivery.EventId,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.Delivered,
+	)
+	if err != nil {
+		log.Println("failed to INSERT webhook_deliveries record: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// REPORTS WHETHER THE WEBHOOK ALREADY ACCEPTED THE EVENT, SO A REDELIVERED CHANGE IS NOT POSTED TWICE
+func (p *PlayoffsDBConnection) IsWebhookDelivered(webhookId uuid.UUID, eventId uuid.UUID) (bool, error) {
+	var delivered []bool
+	query :=
+		`
+	SELECT EXISTS (SELECT 1 FROM webhook_deliveries WHERE webhook_id = $1 AND event_id = $2 AND delivered) AS delivered
+	`
+	err := p.DB.Select(&delivered, query, webhookId, eventId)
+	if err != nil {
+		log.Println("error SELECTING webhook_deliveries: ", err.Error())
+		return false, err
+	}
+	return len(delivered) > 0 && delivered[0], nil
+}
+
+// LISTS THE DELIVERY ATTEMPTS OF A WEBHOOK OF THE CONNECTION'S LEAGUE, MOST RECENT FIRST
+func (p *PlayoffsDBConnection) ListWebhookDeliveries(webhookId uuid.UUID, limit int) ([]models.WebhookDeliveryModel, error) {
+	deliveries := []models.WebhookDeliveryModel{}
+	query :=
+		`
+	SELECT d.*
+	FROM webhook_deliveries d
+	JOIN webhooks w ON w.webhook_id = d.webhook_id
+	WHERE d.webhook_id = $1
+	AND w.league = $2
+	ORDER BY d.created_at DESC
+	LIMIT $3
+	`
+	err := p.DB.Select(&deliveries, query, webhookId, p.LeagueOrDefault(), limit)
+	if err != nil {
+		log.Println("error SELECTING webhook_deliveries: ", err.Error())
+		return []models.WebhookDeliveryModel{}, err
+	}
+	return deliveries, nil
+}
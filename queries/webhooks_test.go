@@ -0,0 +1,103 @@
+package queries
+
+import (
+	"errors"
+	"net/netip"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateWebhook_Success tests subscribing a url to some event types
+func (suite *PlayoffsTestSuite) TestCreateWebhook_Success() {
+	webhookID := uuid.New()
+
+	suite.mock.ExpectQuery(`INSERT INTO webhooks \(webhook_id, league, url, secret, event_types\) VALUES\(\$1, \$2, \$3, \$4, \$5\) RETURNING \*`).
+		WithArgs(sqlmock.AnyArg(), DefaultLeague, "https://partner.test/hook", "s3cret", pq.StringArray{"series.decided"}).
+		WillReturnRows(sqlmock.NewRows([]string{"webhook_id", "league", "url", "secret", "event_types", "created_at"}).
+			AddRow(webhookID, DefaultLeague, "https://partner.test/hook", "s3cret", "{series.decided}", time.Now()))
+
+	webhook, err := suite.conn.CreateWebhook("https://partner.test/hook", "s3cret", []string{"series.decided"})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), webhookID, webhook.WebhookId)
+	assert.Equal(suite.T(), pq.StringArray{"series.decided"}, webhook.EventTypes)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreateWebhook_InvalidURL tests that a relative url is rejected before reaching the database
+func (suite *PlayoffsTestSuite) TestCreateWebhook_InvalidURL() {
+	_, err := suite.conn.CreateWebhook("/hook", "s3cret", nil)
+
+	assert.True(suite.T(), errors.Is(err, ErrInvalidInput))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreateWebhook_NonPublicHost tests that loopback and private hosts are rejected before reaching the database
+func (suite *PlayoffsTestSuite) TestCreateWebhook_NonPublicHost() {
+	for _, target := range []string{"http://localhost:8080/hook", "http://127.0.0.1/hook", "https://10.1.2.3/hook", "http://[::1]/hook", "http://169.254.169.254/latest", "http://[::ffff:192.168.0.1]/hook"} {
+		_, err := suite.conn.CreateWebhook(target, "s3cret", nil)
+
+		assert.True(suite.T(), errors.Is(err, ErrInvalidInput), target)
+	}
+	assert.True(suite.T(), PublicAddress(netip.MustParseAddr("93.184.216.34")))
+	assert.False(suite.T(), PublicAddress(netip.MustParseAddr("100.64.0.1")))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestListWebhooksForEvent_Success tests selecting the webhooks of the league subscribed to an event type
+func (suite *PlayoffsTestSuite) TestListWebhooksForEvent_Success() {
+	suite.mock.ExpectQuery(`SELECT \* FROM webhooks WHERE league = \$1 AND \(cardinality\(event_types\) = 0 OR \$2 = ANY\(event_types\)\)`).
+		WithArgs("north", "champion.crowned").
+		WillReturnRows(sqlmock.NewRows([]string{"webhook_id", "league", "url", "secret", "event_types", "created_at"}).
+			AddRow(uuid.New(), "north", "https://partner.test/hook", "s3cret", "{}", time.Now()))
+
+	webhooks, err := suite.conn.ForLeague("north").ListWebhooksForEvent("champion.crowned")
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), webhooks, 1)
+	assert.Equal(suite.T(), "s3cret", webhooks[0].Secret)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestDeleteWebhook_NotFound tests deleting a webhook of another league
+func (suite *PlayoffsTestSuite) TestDeleteWebhook_NotFound() {
+	webhookID := uuid.New()
+
+	suite.mock.ExpectExec(`DELETE FROM webhooks WHERE webhook_id = \$1 AND league = \$2`).
+		WithArgs(webhookID, DefaultLeague).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.conn.DeleteWebhook(webhookID)
+
+	assert.True(suite.T(), errors.Is(err, ErrNotFound))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRecordWebhookDelivery_Success tests appending a delivery attempt to the log
+func (suite *PlayoffsTestSuite) TestRecordWebhookDelivery_Success() {
+	status := 503
+	delivery := models.WebhookDeliveryModel{
+		WebhookId:  uuid.New(),
+		EventId:    uuid.New(),
+		EventType:  "series.decided",
+		Payload:    types.JSONText(`{"type":"series.decided"}`),
+		Attempt:    1,
+		StatusCode: &status,
+	}
+
+	suite.mock.ExpectExec(`INSERT INTO webhook_deliveries`).
+		WithArgs(sqlmock.AnyArg(), delivery.WebhookId, delivery.EventId, "series.decided", delivery.Payload, 1, &status, nil, false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := suite.conn.RecordWebhookDelivery(delivery)
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+)
+
+// THE LEAGUE AN API TOKEN READS AND WRITES AND THE ACTOR ITS MUTATIONS ARE AUDITED UNDER
+type Credential struct {
+	League string `json:"league"`
+	Actor  string `json:"actor"`
+}
+
+type credentialKey struct{}
+
+// ROUTES SERVED WITHOUT A TOKEN, THEY ONLY DESCRIBE THE API
+var publicRoutes = map[string]bool{
+	"GET /openapi.json":         true,
+	"GET /schemas/bracket.json": true,
+}
+
+// READS THE API TOKENS OF THE SERVER FROM A JSON OBJECT OF TOKEN TO CREDENTIAL, E.G.
+// {"s3cr3t": {"league": "north", "actor": "scorekeeper-1"}}. A MISSING LEAGUE IS THE DEFAULT LEAGUE, THE ACTOR IS
+// REQUIRED SO EVERY MUTATION IS AUDITED UNDER A NAME
+func ReadCredentials(r io.Reader) (map[string]Credential, error) {
+	credentials := map[string]Credential{}
+	if err := json.NewDecoder(r).Decode(&credentials); err != nil {
+		return nil, err
+	}
+	for token, c := range credentials {
+		if strings.TrimSpace(token) == "" {
+			return nil, errors.New("an API token is empty")
+		}
+		if strings.TrimSpace(c.Actor) == "" {
+			return nil, errors.New("the API token of league " + c.League + " has no actor")
+		}
+		if c.League == "" {
+			c.League = queries.DefaultLeague
+			credentials[token] = c
+		}
+	}
+	return credentials, nil
+}
+
+// THE TOKEN OF THE Authorization: Bearer HEADER. BROWSERS CAN NOT SET HEADERS ON AN EventSource SO GET /events
+// ALSO TAKES IT AS ?access_token=
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	if r.Method == http.MethodGet && r.URL.Path == "/events" {
+		return r.URL.Query().Get("access_token")
+	}
+	return ""
+}
+
+// COMPARES THE TOKEN WITH EVERY CONFIGURED TOKEN IN CONSTANT TIME SO THE RESPONSE TIME DOES NOT LEAK A PREFIX
+func (s *Server) authenticate(r *http.Request) (Credential, bool) {
+	token := requestToken(r)
+	if token == "" {
+		return Credential{}, false
+	}
+	var found Credential
+	ok := false
+	for t, c := range s.credentials {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			found, ok = c, true
+		}
+	}
+	return found, ok
+}
+
+// REJECTS EVERY REQUEST BUT THE PUBLIC ROUTES WITHOUT A KNOWN TOKEN AND PASSES THE CREDENTIAL OF THE TOKEN TO THE
+// HANDLERS, WHICH TAKE THE LEAGUE AND ACTOR FROM IT ONLY
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if publicRoutes[r.Method+" "+r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		credential, ok := s.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="bracketd"`)
+			writeJSON(w, http.StatusUnauthorized, errorRes{Error: "missing or unknown API token"})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), credentialKey{}, credential)))
+	})
+}
+
+// THE CREDENTIAL requireToken AUTHENTICATED THE REQUEST WITH
+func requestCredential(r *http.Request) Credential {
+	credential, _ := r.Context().Value(credentialKey{}).(Credential)
+	return credential
+}
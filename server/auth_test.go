@@ -0,0 +1,69 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequireToken_MissingOrUnknown tests that requests without a known token are answered with 401 before any query
+func (suite *ServerTestSuite) TestRequireToken_MissingOrUnknown() {
+	for _, authorization := range []string{"", "Bearer wrong-token", "Basic " + defaultToken, "Bearer "} {
+		rec := suite.do(http.MethodGet, "/seasons/2023-2024/playoffs", "", map[string]string{"Authorization": authorization})
+
+		assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code, authorization)
+		assert.Equal(suite.T(), `Bearer realm="bracketd"`, rec.Header().Get("WWW-Authenticate"))
+		assert.JSONEq(suite.T(), `{"error":"missing or unknown API token"}`, rec.Body.String())
+	}
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRequireToken_IgnoresLeagueHeader tests that a client can not switch to another league with the old X-League header
+func (suite *ServerTestSuite) TestRequireToken_IgnoresLeagueHeader() {
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}))
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/playoffs", "", map[string]string{"X-League": "north"})
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRequireToken_AccessTokenOnlyForEvents tests that the ?access_token= fallback is not accepted outside GET /events
+func (suite *ServerTestSuite) TestRequireToken_AccessTokenOnlyForEvents() {
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/playoffs?access_token="+defaultToken, "", map[string]string{"Authorization": ""})
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRequireToken_PublicRoutes tests that the API descriptions are served without a token
+func (suite *ServerTestSuite) TestRequireToken_PublicRoutes() {
+	for _, target := range []string{"/openapi.json", "/schemas/bracket.json"} {
+		rec := suite.do(http.MethodGet, target, "", map[string]string{"Authorization": ""})
+
+		assert.Equal(suite.T(), http.StatusOK, rec.Code, target)
+	}
+}
+
+// TestReadCredentials tests that a missing league is the default league and that a token needs an actor
+func (suite *ServerTestSuite) TestReadCredentials() {
+	credentials, err := ReadCredentials(strings.NewReader(`{"a": {"actor": "scorekeeper"}, "b": {"league": "north", "actor": "admin"}}`))
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]Credential{
+		"a": {League: queries.DefaultLeague, Actor: "scorekeeper"},
+		"b": {League: "north", Actor: "admin"},
+	}, credentials)
+
+	_, err = ReadCredentials(strings.NewReader(`{"a": {"league": "north"}}`))
+	assert.Error(suite.T(), err)
+	_, err = ReadCredentials(strings.NewReader(`{" ": {"actor": "admin"}}`))
+	assert.Error(suite.T(), err)
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/backup"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+)
+
+type restoreSeasonRes struct {
+	League    string `json:"league"`
+	Season    string `json:"season"`
+	Standings int    `json:"standings"`
+	Playoffs  int    `json:"playoffs"`
+	Audit     int    `json:"audit"`
+	Changes   int    `json:"changes"`
+}
+
+// THE ARCHIVE OF EVERY ROW OF A SEASON, AS WRITTEN BY bracketctl backup
+func (s *Server) backupSeason(w http.ResponseWriter, r *http.Request) {
+	conn := s.connection(r)
+	b, err := conn.BackupSeason(r.PathValue("season"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var archive bytes.Buffer
+	if errW := backup.Write(&archive, b); errW != nil {
+		writeError(w, errW)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+backup.FileName(conn.LeagueOrDefault(), b.Season)+`"`)
+	if _, errW := w.Write(archive.Bytes()); errW != nil {
+		log.Println("failed to write the backup: ", errW.Error())
+	}
+}
+
+// THE LARGEST ARCHIVE BODY POST /backups/restore READS, backup.Read ALSO LIMITS ITS DECOMPRESSED SIZE
+const maxBackupBody = 32 << 20
+
+// RECREATES THE SEASON OF AN ARCHIVE BODY IN THE LEAGUE OF THE REQUEST. A SEASON THAT ALREADY HAS RECORDS IS
+// ANSWERED WITH 409 UNLESS ?replace=true. THE AUDIT ENTRIES OF THE ARCHIVE ARE RESTAMPED AS RESTORE ENTRIES OF THE
+// ACTOR OF THE REQUEST SINCE ANYONE WITH A TOKEN CAN UPLOAD ONE
+func (s *Server) restoreSeason(w http.ResponseWriter, r *http.Request) {
+	replace := false
+	if v := r.URL.Query().Get("replace"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			writeBadRequest(w, "invalid replace: "+v)
+			return
+		}
+		replace = parsed
+	}
+	b, err := backup.Read(http.MaxBytesReader(w, r.Body, maxBackupBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorRes{Error: "the backup is larger than " + strconv.Itoa(maxBackupBody>>20) + " MiB"})
+			return
+		}
+		if errors.Is(err, backup.ErrInvalidArchive) {
+			writeBadRequest(w, err.Error())
+			return
+		}
+		writeError(w, err)
+		return
+	}
+	conn := s.connection(r)
+	if errR := conn.RestoreSeason(b, replace, queries.RestoreAuditRestamped); errR != nil {
+		writeError(w, errR)
+		return
+	}
+	writeJSON(w, http.StatusCreated, restoreSeasonRes{
+		League:    conn.LeagueOrDefault(),
+		Season:    b.Season,
+		Standings: len(b.Standings),
+		Playoffs:  len(b.Playoffs),
+		Audit:     len(b.Audit),
+		Changes:   len(b.Changes),
+	})
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+)
+
+// SIZE OF THE PNG WHEN ?width= IS NOT GIVEN, FITTING THE LINK PREVIEWS OF SOCIAL NETWORKS
+const (
+	defaultPNGWidth  = 1200
+	defaultPNGHeight = 675
+)
+
+// LARGEST SIDE OF A PNG DRAWN BY THE SERVER, FAR BELOW bracket.MaxPNGSize SINCE EVERY REQUEST HOLDS ITS OWN IMAGE
+// (16MB AT THIS SIZE)
+const maxPNGSize = 2048
+
+// DRAWS THE BRACKET AS AN SVG IMAGE THAT CAN BE EMBEDDED IN A PAGE OR PUBLISHED AS IS
+func (s *Server) bracketSVG(w http.ResponseWriter, r *http.Request) {
+	s.renderBracket(w, r, "image/svg+xml", bracket.WriteSVG)
+}
+
+// DRAWS THE BRACKET AS A PNG OF ?width= x ?height= PIXELS. A WIDTH WITHOUT HEIGHT KEEPS THE ASPECT RATIO OF THE BRACKET.
+// THE LOGOS OF LOCAL FILES ARE NOT DRAWN (bracket.DefaultOptions), SINCE THE CLIENTS WRITE THE URLS
+func (s *Server) bracketPNG(w http.ResponseWriter, r *http.Request) {
+	width, height := defaultPNGWidth, defaultPNGHeight
+	if v := r.URL.Query().Get("width"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxPNGSize {
+			writeBadRequest(w, "invalid width: "+v+" (1 to "+strconv.Itoa(maxPNGSize)+" pixels)")
+			return
+		}
+		width, height = parsed, 0
+	}
+	if v := r.URL.Query().Get("height"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxPNGSize {
+			writeBadRequest(w, "invalid height: "+v+" (1 to "+strconv.Itoa(maxPNGSize)+" pixels)")
+			return
+		}
+		height = parsed
+	}
+	s.renderBracket(w, r, "image/png", func(out io.Writer, l bracket.Layout, title string) error {
+		// A HEIGHT KEEPING THE ASPECT RATIO OF A TALL BRACKET CAN STILL GO OVER THE LIMIT
+		h := height
+		if h == 0 {
+			h = int(math.Ceil(float64(width) * l.Height / l.Width))
+		}
+		if h > maxPNGSize {
+			return fmt.Errorf("%w: the bracket is %d pixels high at a width of %d, the server draws at most %d", bracket.ErrInvalidSize, h, width, maxPNGSize)
+		}
+		return bracket.WritePNG(out, l, title, width, h)
+	})
+}
+
+// DRAWS THE BRACKET AS A PDF FOR PRINTING ON PAGES OF ?pageSize= (A4 BY DEFAULT)
+func (s *Server) bracketPDF(w http.ResponseWriter, r *http.Request) {
+	pageSize := r.URL.Query().Get("pageSize")
+	s.renderBracket(w, r, "application/pdf", func(out io.Writer, l bracket.Layout, title string) error {
+		return bracket.WritePDF(out, l, title, pageSize)
+	})
+}
+
+// LAYS OUT THE BRACKET OF THE SEASON AND ANSWERS WITH THE DOCUMENT write DRAWS. THE DOCUMENT IS BUFFERED SO A
+// FAILED DRAWING IS STILL ANSWERED WITH AN ERROR STATUS
+func (s *Server) renderBracket(w http.ResponseWriter, r *http.Request, contentType string, write func(io.Writer, bracket.Layout, string) error) {
+	season, competition := r.PathValue("season"), r.URL.Query().Get("competition")
+	playoffs, err := s.connection(r).ListPlayoffs(season, competition)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(playoffs) == 0 {
+		writeJSON(w, http.StatusNotFound, errorRes{Error: "season " + season + " has no bracket"})
+		return
+	}
+	var doc bytes.Buffer
+	if errW := write(&doc, bracket.NewLayout(playoffs, bracket.DefaultOptions), bracket.Title(season, competition)); errW != nil {
+		if errors.Is(errW, bracket.ErrInvalidSize) || errors.Is(errW, bracket.ErrInvalidPageSize) {
+			writeBadRequest(w, errW.Error())
+			return
+		}
+		writeError(w, errW)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if _, errW := w.Write(doc.Bytes()); errW != nil {
+		log.Println("failed to write the bracket document: ", errW.Error())
+	}
+}
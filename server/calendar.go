@@ -0,0 +1,97 @@
+package server
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/calendar"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+type schedulePlayoffsReq struct {
+	ScheduledAt *time.Time `json:"scheduledAt"`
+}
+
+// SETS THE DATE OF A GAME, OR CLEARS IT WHEN scheduledAt IS NULL, AND ANSWERS WITH THE GAME
+func (s *Server) schedulePlayoffs(w http.ResponseWriter, r *http.Request) {
+	playoffsId, err := uuid.Parse(r.PathValue("playoffsId"))
+	if err != nil {
+		writeBadRequest(w, "invalid playoffs id: "+r.PathValue("playoffsId"))
+		return
+	}
+	var req schedulePlayoffsReq
+	if errD := decodeBody(w, r, &req); errD != nil {
+		writeBodyError(w, errD)
+		return
+	}
+	game, errS := s.connection(r).SchedulePlayoffs(playoffsId, req.ScheduledAt)
+	if errS != nil {
+		writeError(w, errS)
+		return
+	}
+	writeJSON(w, http.StatusOK, game)
+}
+
+// THE SCHEDULED GAMES OF THE BRACKET OF A SEASON AS AN iCalendar FEED
+func (s *Server) seasonCalendar(w http.ResponseWriter, r *http.Request) {
+	season, competition := r.PathValue("season"), r.URL.Query().Get("competition")
+	playoffs, err := s.connection(r).ListPlayoffs(season, competition)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var games []models.PlayoffsModel
+	for _, round := range playoffs {
+		for _, series := range round {
+			games = append(games, series...)
+		}
+	}
+	writeCalendar(w, bracket.Title(season, competition), games)
+}
+
+// THE SCHEDULED GAMES OF A TEAM AS AN iCalendar FEED, OF ?season= OR OF EVERY SEASON
+func (s *Server) teamCalendar(w http.ResponseWriter, r *http.Request) {
+	teamId, err := uuid.Parse(r.PathValue("teamId"))
+	if err != nil {
+		writeBadRequest(w, "invalid team id: "+r.PathValue("teamId"))
+		return
+	}
+	games, errL := s.connection(r).ListTeamGames(teamId, r.URL.Query().Get("season"))
+	if errL != nil {
+		writeError(w, errL)
+		return
+	}
+	writeCalendar(w, teamName(teamId, games)+" playoffs", games)
+}
+
+// THE NAME OF THE TEAM IN ITS NEWEST GAME
+func teamName(teamId uuid.UUID, games []models.PlayoffsModel) string {
+	name := "Team"
+	for _, g := range games {
+		switch {
+		case g.HomeTeamId != nil && *g.HomeTeamId == teamId && g.HomeTeamName != nil:
+			name = *g.HomeTeamName
+		case g.AwayTeamId != nil && *g.AwayTeamId == teamId && g.AwayTeamName != nil:
+			name = *g.AwayTeamName
+		}
+	}
+	return name
+}
+
+// A CALENDAR WITHOUT GAMES IS STILL A VALID FEED, SO SUBSCRIPTIONS MADE BEFORE THE GAMES ARE SCHEDULED KEEP WORKING
+func writeCalendar(w http.ResponseWriter, name string, games []models.PlayoffsModel) {
+	var feed bytes.Buffer
+	if err := calendar.WriteICS(&feed, name, games, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if _, err := w.Write(feed.Bytes()); err != nil {
+		log.Println("failed to write the calendar: ", err.Error())
+	}
+}
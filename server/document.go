@@ -0,0 +1,59 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/document"
+)
+
+// THE BRACKET OF A SEASON AS A VERSIONED JSON DOCUMENT, SEE GET /schemas/bracket.json
+func (s *Server) bracketDocument(w http.ResponseWriter, r *http.Request) {
+	season, competition := r.PathValue("season"), r.URL.Query().Get("competition")
+	conn := s.connection(r)
+	playoffs, err := conn.ListPlayoffs(season, competition)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(playoffs) == 0 {
+		writeJSON(w, http.StatusNotFound, errorRes{Error: "season " + season + " has no bracket"})
+		return
+	}
+	writeJSON(w, http.StatusOK, document.New(conn.LeagueOrDefault(), season, competition, playoffs))
+}
+
+// RESTORES THE BRACKET OF A DOCUMENT IN THE LEAGUE OF THE REQUEST, WHATEVER LEAGUE IT WAS EXPORTED FROM, AND
+// ANSWERS WITH THE DOCUMENT OF THE STORED BRACKET
+func (s *Server) importBracket(w http.ResponseWriter, r *http.Request) {
+	doc, err := document.Decode(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		if errors.Is(err, document.ErrInvalidDocument) {
+			writeBadRequest(w, err.Error())
+			return
+		}
+		writeBodyError(w, err)
+		return
+	}
+	conn := s.connection(r)
+	if errI := conn.ImportPlayoffs(doc.Games()); errI != nil {
+		writeError(w, errI)
+		return
+	}
+	playoffs, errL := conn.ListPlayoffs(doc.Season, doc.Competition)
+	if errL != nil {
+		writeError(w, errL)
+		return
+	}
+	writeJSON(w, http.StatusCreated, document.New(conn.LeagueOrDefault(), doc.Season, doc.Competition, playoffs))
+}
+
+// THE JSON SCHEMA OF THE BRACKET DOCUMENTS
+func (s *Server) bracketSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(document.Schema); err != nil {
+		log.Println("failed to write the bracket schema: ", err.Error())
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/events"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+)
+
+// NUMBER OF EVENTS KEPT FOR CLIENTS RESUMING WITH Last-Event-ID
+const eventHistory = 1024
+
+// INTERVAL OF THE COMMENT LINES KEEPING IDLE STREAMS OPEN THROUGH PROXIES
+const eventHeartbeat = 15 * time.Second
+
+// CHAINS THE HOOK ALREADY SET ON THE CONNECTION (IF ANY) WITH THE ONE OF THE SERVER
+func chainOnChange(first func(models.BracketChangeModel), second func(models.BracketChangeModel)) func(models.BracketChangeModel) {
+	if first == nil {
+		return second
+	}
+	return func(change models.BracketChangeModel) {
+		first(change)
+		second(change)
+	}
+}
+
+// STREAMS THE BRACKET DELTAS OF THE LEAGUE OF THE TOKEN AS SERVER-SENT EVENTS, OPTIONALLY FILTERED BY ?season= AND
+// ?competition=. A CLIENT RESUMES WITH THE Last-Event-ID HEADER (SENT BY EventSource ON RECONNECT) OR ?lastEventId=
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, errorRes{Error: "streaming is not supported"})
+		return
+	}
+	query := r.URL.Query()
+	league := requestCredential(r).League
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = query.Get("lastEventId")
+	}
+	var last uint64
+	if lastEventId != "" {
+		parsed, err := strconv.ParseUint(lastEventId, 10, 64)
+		if err != nil {
+			writeBadRequest(w, "invalid last event id: "+lastEventId)
+			return
+		}
+		last = parsed
+	}
+
+	sub := s.events.Subscribe(events.Filter{League: league, Season: query.Get("season"), Competition: query.Get("competition")}, last)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if sub.Reload {
+		fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+	}
+	for _, e := range sub.Replay {
+		if err := writeEvent(w, e); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, open := <-sub.C:
+			if !open {
+				// TOO FAR BEHIND, THE CLIENT RECONNECTS AND RESUMES FROM ITS LAST EVENT ID
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e events.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Println("failed to encode bracket event: ", err.Error())
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: bracket\ndata: %s\n\n", e.Id, data)
+	return err
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/events"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bracketChange(season string) models.BracketChangeModel {
+	teamID := uuid.New()
+	game := models.PlayoffsModel{PlayoffsId: uuid.New(), HomeTeamId: &teamID}
+	gameWon := game
+	gameWon.Winner = &teamID
+	return models.BracketChangeModel{
+		League:      queries.DefaultLeague,
+		Season:      season,
+		Competition: queries.DefaultCompetition,
+		Operation:   queries.AuditUpdatePlayoffs,
+		Games:       []models.GameChangeModel{{Before: game, After: gameWon}},
+	}
+}
+
+// TestStreamEvents_ResumesFromLastEventId tests that a reconnecting EventSource client, authenticated by
+// ?access_token=, receives the events it missed
+func (suite *ServerTestSuite) TestStreamEvents_ResumesFromLastEventId() {
+	probe := suite.server.events.Subscribe(events.Filter{League: queries.DefaultLeague}, 0)
+	suite.server.conn.OnChange(bracketChange("2022-2023"))
+	suite.server.conn.OnChange(bracketChange("2023-2024"))
+	seen := <-probe.C
+	probe.Close()
+
+	httpServer := httptest.NewServer(suite.server)
+	defer httpServer.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/events?season=2023-2024&access_token="+defaultToken, nil)
+	require.NoError(suite.T(), err)
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(seen.Id, 10))
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(suite.T(), err)
+	defer res.Body.Close()
+
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+	assert.Equal(suite.T(), "text/event-stream", res.Header.Get("Content-Type"))
+	reader := bufio.NewReader(res.Body)
+	var frame []string
+	for {
+		line, errR := reader.ReadString('\n')
+		require.NoError(suite.T(), errR)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			break
+		}
+		frame = append(frame, line)
+	}
+	require.Len(suite.T(), frame, 3)
+	assert.Equal(suite.T(), "id: "+strconv.FormatUint(seen.Id+1, 10), frame[0])
+	assert.Equal(suite.T(), "event: bracket", frame[1])
+	var e events.Event
+	require.NoError(suite.T(), json.Unmarshal([]byte(strings.TrimPrefix(frame[2], "data: ")), &e))
+	assert.Equal(suite.T(), "2023-2024", e.Season)
+	assert.Equal(suite.T(), events.DeltaWinnerSet, e.Deltas[0].Kind)
+}
+
+// TestStreamEvents_InvalidLastEventId tests that a malformed last event id is rejected
+func (suite *ServerTestSuite) TestStreamEvents_InvalidLastEventId() {
+	rec := suite.do(http.MethodGet, "/events?lastEventId=abc", "", nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
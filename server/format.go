@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+)
+
+type clonePlayoffsReq struct {
+	FromSeason  string `json:"fromSeason"`
+	Competition string `json:"competition"`
+}
+
+// THE CONFERENCES, LIMIT AND SERIES THE BRACKET OF A SEASON WAS CREATED WITH
+func (s *Server) playoffsFormat(w http.ResponseWriter, r *http.Request) {
+	format, err := s.connection(r).GetPlayoffsFormat(r.PathValue("season"), r.URL.Query().Get("competition"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, format)
+}
+
+// CREATES THE BRACKET OF THE SEASON WITH THE FORMAT OF fromSeason AND ANSWERS WITH THE TEAMS THAT QUALIFIED AGAIN,
+// NEWLY OR NO LONGER
+func (s *Server) clonePlayoffs(w http.ResponseWriter, r *http.Request) {
+	var req clonePlayoffsReq
+	if err := decodeBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	if req.FromSeason == "" {
+		writeBadRequest(w, "fromSeason is required")
+		return
+	}
+	report, err := s.connection(r).ClonePlayoffs(req.FromSeason, r.PathValue("season"), req.Competition)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, report)
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net/http"
+)
+
+// CHECKS THE BRACKET OF THE SEASON AGAINST THE ADVANCEMENT RULES. AN INCONSISTENT BRACKET IS STILL A 200,
+// THE REPORT SAYS WHETHER IT IS valid
+func (s *Server) validatePlayoffs(w http.ResponseWriter, r *http.Request) {
+	report, err := s.connection(r).ValidatePlayoffs(r.PathValue("season"), r.URL.Query().Get("competition"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// RECOMPUTES THE NEXT ROUND SLOTS FROM THE RECORDED WINNERS AND ANSWERS WITH WHAT WAS REPAIRED AND
+// WHAT STILL NEEDS A MANUAL FIX
+func (s *Server) repairPlayoffs(w http.ResponseWriter, r *http.Request) {
+	report, err := s.connection(r).RepairPlayoffs(r.PathValue("season"), r.URL.Query().Get("competition"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// REBUILDS THE ROUNDS AFTER THE FIRST FROM THE FIRST ROUND PAIRINGS AND THE RECORDED WINNERS ONLY, E.G. AFTER
+// RESULTS WERE IMPORTED WITHOUT ADVANCING THE WINNERS
+func (s *Server) recomputePlayoffs(w http.ResponseWriter, r *http.Request) {
+	report, err := s.connection(r).RecomputePlayoffs(r.PathValue("season"), r.URL.Query().Get("competition"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/events"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/spreadsheet"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPIProperty struct {
+	Type     string `json:"type"`
+	Format   string `json:"format"`
+	Nullable bool   `json:"nullable"`
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]openAPIProperty `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+// GO TYPE DESCRIBED BY EVERY OBJECT SCHEMA OF THE DOCUMENT
+var openAPISchemaTypes = map[string]reflect.Type{
+	"Playoffs":                reflect.TypeOf(models.PlayoffsModel{}),
+	"PlayoffsUpdate":          reflect.TypeOf(queries.PlayoffsModelReqQuery{}),
+	"CreatePlayoffsRequest":   reflect.TypeOf(createPlayoffsReq{}),
+	"RevertPlayoffsRequest":   reflect.TypeOf(revertPlayoffsReq{}),
+	"SchedulePlayoffsRequest": reflect.TypeOf(schedulePlayoffsReq{}),
+	"Standings":               reflect.TypeOf(models.StandingsModel{}),
+	"ImportStandingsResult":   reflect.TypeOf(importStandingsRes{}),
+	"ImportProblem":           reflect.TypeOf(spreadsheet.Problem{}),
+	"RestoreSeasonResult":     reflect.TypeOf(restoreSeasonRes{}),
+	"PlayoffsFormat":          reflect.TypeOf(models.PlayoffsFormatModel{}),
+	"ClonePlayoffsRequest":    reflect.TypeOf(clonePlayoffsReq{}),
+	"QualifiedTeam":           reflect.TypeOf(models.QualifiedTeamModel{}),
+	"CloneReport":             reflect.TypeOf(models.CloneReportModel{}),
+	"PlayoffsPreview":         reflect.TypeOf(models.PlayoffsPreviewModel{}),
+	"BracketViolation":        reflect.TypeOf(models.BracketViolationModel{}),
+	"BracketIntegrity":        reflect.TypeOf(models.BracketIntegrityModel{}),
+	"Error":                   reflect.TypeOf(errorRes{}),
+	"Event":                   reflect.TypeOf(events.Event{}),
+	"Delta":                   reflect.TypeOf(events.Delta{}),
+	"CreateWebhookRequest":    reflect.TypeOf(createWebhookReq{}),
+	"Webhook":                 reflect.TypeOf(models.WebhookModel{}),
+	"WebhookDelivery":         reflect.TypeOf(models.WebhookDeliveryModel{}),
+}
+
+func loadOpenAPI() (openAPIDocument, error) {
+	var doc openAPIDocument
+	err := json.Unmarshal(openAPISpec, &doc)
+	return doc, err
+}
+
+// RETURNS THE OpenAPI TYPE, FORMAT AND NULLABILITY THE JSON ENCODING OF A GO TYPE HAS
+func expectedProperty(t reflect.Type) openAPIProperty {
+	p := openAPIProperty{}
+	if t.Kind() == reflect.Pointer {
+		p.Nullable = true
+		t = t.Elem()
+	}
+	switch {
+	case t == reflect.TypeOf(uuid.UUID{}):
+		p.Type, p.Format = "string", "uuid"
+	case t == reflect.TypeOf(types.JSONText{}):
+		p.Type = "object"
+	case t == reflect.TypeOf(time.Time{}):
+		p.Type, p.Format = "string", "date-time"
+	case t.Kind() == reflect.String:
+		p.Type = "string"
+	case t.Kind() == reflect.Int || t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64:
+		p.Type = "integer"
+	case t.Kind() == reflect.Float64:
+		p.Type, p.Format = "number", "double"
+	case t.Kind() == reflect.Bool:
+		p.Type = "boolean"
+	case t.Kind() == reflect.Slice:
+		p.Type = "array"
+	default:
+		p.Type = "unsupported " + t.String()
+	}
+	return p
+}
+
+// TestOpenAPI_Served tests that the document is served by the API
+func (suite *ServerTestSuite) TestOpenAPI_Served() {
+	rec := suite.do(http.MethodGet, "/openapi.json", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "application/json", rec.Header().Get("Content-Type"))
+	var doc openAPIDocument
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.True(suite.T(), strings.HasPrefix(doc.OpenAPI, "3."))
+}
+
+// TestOpenAPI_PathsMatchRoutes tests that the document describes every route and nothing else
+func (suite *ServerTestSuite) TestOpenAPI_PathsMatchRoutes() {
+	doc, err := loadOpenAPI()
+	require.NoError(suite.T(), err)
+
+	var documented []string
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	var routed []string
+	for _, r := range suite.server.routeTable() {
+		routed = append(routed, r.pattern)
+	}
+	sort.Strings(documented)
+	sort.Strings(routed)
+
+	assert.Equal(suite.T(), routed, documented)
+}
+
+// TestOpenAPI_SchemasMatchModels tests that every schema lists exactly the JSON fields of its Go type with the same types
+func (suite *ServerTestSuite) TestOpenAPI_SchemasMatchModels() {
+	doc, err := loadOpenAPI()
+	require.NoError(suite.T(), err)
+
+	for name, goType := range openAPISchemaTypes {
+		schema, ok := doc.Components.Schemas[name]
+		if !assert.True(suite.T(), ok, "schema %s is missing", name) {
+			continue
+		}
+		fields := map[string]bool{}
+		for i := 0; i < goType.NumField(); i++ {
+			field := goType.Field(i)
+			tag := strings.Split(field.Tag.Get("json"), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			fields[tag] = true
+			property, documented := schema.Properties[tag]
+			if !assert.True(suite.T(), documented, "%s.%s is not documented in schema %s", goType.Name(), tag, name) {
+				continue
+			}
+			assert.Equal(suite.T(), expectedProperty(field.Type), property, "schema %s property %s", name, tag)
+		}
+		for property := range schema.Properties {
+			assert.True(suite.T(), fields[property], "schema %s documents %s which %s does not have", name, property, goType.Name())
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+)
+
+type createPlayoffsReq struct {
+	Conferences []string `json:"conferences"`
+	Limit       int      `json:"limit"`
+	Competition string   `json:"competition"`
+}
+
+type revertPlayoffsReq struct {
+	FixtureRound int       `json:"fixtureRound"`
+	TeamId       uuid.UUID `json:"teamId"`
+	Season       string    `json:"season"`
+	Competition  string    `json:"competition"`
+}
+
+// CREATES THE BRACKET AND ANSWERS WITH IT, IN THE SAME SHAPE AS GET /seasons/{season}/playoffs; ?dryRun=true ONLY
+// ANSWERS WITH THE QUALIFIED TEAMS AND THE BRACKET IT WOULD CREATE
+func (s *Server) createPlayoffs(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			writeBadRequest(w, "invalid dryRun: "+v)
+			return
+		}
+		dryRun = parsed
+	}
+	var req createPlayoffsReq
+	if err := decodeBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	season := r.PathValue("season")
+	conn := s.connection(r)
+	if dryRun {
+		preview, err := conn.PreviewPlayoffs(req.Conferences, season, req.Competition, req.Limit)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, preview)
+		return
+	}
+	if err := conn.CreatePlayoffs(req.Conferences, season, req.Competition, req.Limit); err != nil {
+		writeError(w, err)
+		return
+	}
+	playoffs, err := conn.ListPlayoffs(season, req.Competition)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, playoffs)
+}
+
+func (s *Server) listPlayoffs(w http.ResponseWriter, r *http.Request) {
+	playoffs, err := s.connection(r).ListPlayoffs(r.PathValue("season"), r.URL.Query().Get("competition"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, playoffs)
+}
+
+func (s *Server) deletePlayoffs(w http.ResponseWriter, r *http.Request) {
+	if err := s.connection(r).DeletePlayoffs(r.PathValue("season"), r.URL.Query().Get("competition")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RECORDS THE WINNER OF A GAME. THE BODY IS THE GAME AS RETURNED BY THE LIST ENDPOINT WITH ITS winner SET,
+// INCLUDING THE version THE CLIENT READ SO A CONCURRENT UPDATE IS ANSWERED WITH 409
+func (s *Server) updatePlayoffs(w http.ResponseWriter, r *http.Request) {
+	playoffsId, err := uuid.Parse(r.PathValue("playoffsId"))
+	if err != nil {
+		writeBadRequest(w, "invalid playoffs id: "+r.PathValue("playoffsId"))
+		return
+	}
+	var req queries.PlayoffsModelReqQuery
+	if errD := decodeBody(w, r, &req); errD != nil {
+		writeBodyError(w, errD)
+		return
+	}
+	if errU := s.connection(r).UpdatePlayoffs(playoffsId, req); errU != nil {
+		writeError(w, errU)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CLEARS THE WINNER OF A GAME AND THE NEXT ROUND SLOT THE TEAM WAS ADVANCED TO (UpdatePlayoffsToNull)
+func (s *Server) revertPlayoffs(w http.ResponseWriter, r *http.Request) {
+	playoffsId, err := uuid.Parse(r.PathValue("playoffsId"))
+	if err != nil {
+		writeBadRequest(w, "invalid playoffs id: "+r.PathValue("playoffsId"))
+		return
+	}
+	var req revertPlayoffsReq
+	if errD := decodeBody(w, r, &req); errD != nil {
+		writeBodyError(w, errD)
+		return
+	}
+	if errU := s.connection(r).UpdatePlayoffsToNull(playoffsId, req.FixtureRound, req.TeamId, req.Season, req.Competition); errU != nil {
+		writeError(w, errU)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,150 @@
+// PACKAGE server EXPOSES THE PLAYOFFS AND STANDINGS QUERIES AS A JSON HTTP API.
+// EVERY REQUEST BUT THE API DESCRIPTIONS CARRIES AN API TOKEN (Authorization: Bearer) AND IS SCOPED TO THE LEAGUE
+// OF THAT TOKEN, AND ITS MUTATIONS ARE RECORDED IN THE AUDIT LOG UNDER THE ACTOR OF THE TOKEN. COMMITTED RESULT
+// ENTRIES ARE STREAMED TO GET /events SUBSCRIBERS
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/events"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+)
+
+type Server struct {
+	conn        *queries.PlayoffsDBConnection
+	mux         *http.ServeMux
+	handler     http.Handler
+	events      *events.Hub
+	credentials map[string]Credential
+}
+
+type errorRes struct {
+	Error string `json:"error"`
+}
+
+// THE SERVER WORKS ON A COPY OF conn WHOSE OnChange HOOK ALSO PUBLISHES TO THE EVENT STREAM. credentials MAPS
+// EVERY ACCEPTED API TOKEN TO ITS LEAGUE AND ACTOR (SEE ReadCredentials); WITHOUT ANY ONLY THE PUBLIC ROUTES ANSWER
+func New(conn *queries.PlayoffsDBConnection, credentials map[string]Credential) *Server {
+	s := &Server{mux: http.NewServeMux(), events: events.NewHub(eventHistory), credentials: credentials}
+	c := *conn
+	c.OnChange = chainOnChange(conn.OnChange, s.events.Publish)
+	s.conn = &c
+	s.routes()
+	s.handler = s.requireToken(s.mux)
+	return s
+}
+
+type route struct {
+	pattern string
+	handler http.HandlerFunc
+}
+
+// EVERY ENDPOINT OF THE API. THE OpenAPI DOCUMENT MUST DESCRIBE EXACTLY THESE PATTERNS (SEE openapi_test.go)
+func (s *Server) routeTable() []route {
+	return []route{
+		{"GET /openapi.json", s.openAPI},
+		{"GET /events", s.streamEvents},
+
+		{"POST /seasons/{season}/playoffs", s.createPlayoffs},
+		{"GET /seasons/{season}/playoffs", s.listPlayoffs},
+		{"DELETE /seasons/{season}/playoffs", s.deletePlayoffs},
+		{"GET /seasons/{season}/playoffs/format", s.playoffsFormat},
+		{"POST /seasons/{season}/playoffs/clone", s.clonePlayoffs},
+		{"GET /seasons/{season}/playoffs/validate", s.validatePlayoffs},
+		{"POST /seasons/{season}/playoffs/repair", s.repairPlayoffs},
+		{"POST /seasons/{season}/playoffs/recompute", s.recomputePlayoffs},
+		{"PUT /playoffs/{playoffsId}", s.updatePlayoffs},
+		{"POST /playoffs/{playoffsId}/revert", s.revertPlayoffs},
+		{"PUT /playoffs/{playoffsId}/schedule", s.schedulePlayoffs},
+		{"GET /seasons/{season}/playoffs.csv", s.playoffsCSV},
+		{"GET /seasons/{season}/bracket.svg", s.bracketSVG},
+		{"GET /seasons/{season}/bracket.png", s.bracketPNG},
+		{"GET /seasons/{season}/bracket.pdf", s.bracketPDF},
+		{"GET /seasons/{season}/calendar.ics", s.seasonCalendar},
+		{"GET /seasons/{season}/bracket.json", s.bracketDocument},
+		{"POST /brackets", s.importBracket},
+		{"GET /schemas/bracket.json", s.bracketSchema},
+		{"GET /seasons/{season}/backup", s.backupSeason},
+		{"POST /backups/restore", s.restoreSeason},
+		{"GET /teams/{teamId}/calendar.ics", s.teamCalendar},
+
+		{"POST /standings", s.createStandings},
+		{"GET /standings", s.listStandings},
+		{"GET /standings.csv", s.standingsCSV},
+		{"POST /standings/import", s.importStandings},
+		{"GET /standings/{standingsId}", s.getStandings},
+		{"PUT /standings/{standingsId}", s.updateStandings},
+		{"DELETE /standings/{standingsId}", s.deleteStandings},
+
+		{"POST /webhooks", s.createWebhook},
+		{"GET /webhooks", s.listWebhooks},
+		{"DELETE /webhooks/{webhookId}", s.deleteWebhook},
+		{"GET /webhooks/{webhookId}/deliveries", s.listWebhookDeliveries},
+	}
+}
+
+func (s *Server) routes() {
+	for _, r := range s.routeTable() {
+		s.mux.HandleFunc(r.pattern, r.handler)
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// RETURNS THE CONNECTION SCOPED TO THE LEAGUE AND ACTOR OF THE TOKEN OF THE REQUEST
+func (s *Server) connection(r *http.Request) *queries.PlayoffsDBConnection {
+	credential := requestCredential(r)
+	return s.conn.ForLeague(credential.League).WithActor(credential.Actor)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Println("failed to encode response: ", err.Error())
+	}
+}
+
+// MAPS THE ERROR KINDS OF THE queries PACKAGE TO STATUS CODES. UNKNOWN ERRORS ARE LOGGED AND
+// ANSWERED WITH A GENERIC MESSAGE SO DATABASE DETAILS DO NOT LEAK TO THE CLIENT
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, queries.ErrInvalidInput):
+		writeJSON(w, http.StatusBadRequest, errorRes{Error: err.Error()})
+	case errors.Is(err, queries.ErrNotFound):
+		writeJSON(w, http.StatusNotFound, errorRes{Error: err.Error()})
+	case errors.Is(err, queries.ErrAlreadyExists), errors.Is(err, queries.ErrPlayoffsConflict):
+		writeJSON(w, http.StatusConflict, errorRes{Error: err.Error()})
+	default:
+		log.Println("request failed: ", err.Error())
+		writeJSON(w, http.StatusInternalServerError, errorRes{Error: "internal server error"})
+	}
+}
+
+func writeBadRequest(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusBadRequest, errorRes{Error: message})
+}
+
+// THE LARGEST REQUEST BODY READ, EXCEPT THE ARCHIVES OF POST /backups/restore (maxBackupBody)
+const maxBody = 4 << 20
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(v)
+}
+
+// ANSWERS A REQUEST BODY THAT COULD NOT BE READ: 413 WHEN IT IS LARGER THAN maxBody, 400 OTHERWISE
+func writeBodyError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorRes{Error: "the request body is larger than " + strconv.FormatInt(tooLarge.Limit>>20, 10) + " MiB"})
+		return
+	}
+	writeBadRequest(w, "invalid request body: "+err.Error())
+}
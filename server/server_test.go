@@ -0,0 +1,737 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/backup"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/document"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// API TOKENS OF THE TEST SERVER, do SENDS defaultToken UNLESS THE TEST SETS ITS OWN Authorization HEADER
+const (
+	defaultToken = "default-token"
+	northToken   = "north-token"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	db     *sqlx.DB
+	mock   sqlmock.Sqlmock
+	server *Server
+}
+
+// RUNS BEFORE EACH TEST
+func (suite *ServerTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = sqlx.NewDb(mockDB, "sqlmock")
+	suite.mock = mock
+	suite.server = New(&queries.PlayoffsDBConnection{DB: suite.db}, map[string]Credential{
+		defaultToken: {League: queries.DefaultLeague, Actor: "admin"},
+		northToken:   {League: "north", Actor: "north-scorekeeper"},
+	})
+}
+
+// runs after each test
+func (suite *ServerTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *ServerTestSuite) do(method string, target string, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+defaultToken)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	suite.server.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestListPlayoffs_ScopedToLeagueAndCompetition tests that the league of the token and the competition parameter reach the query
+func (suite *ServerTestSuite) TestListPlayoffs_ScopedToLeagueAndCompetition() {
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3`).
+		WithArgs("2023-2024", "north", "cup").
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}))
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/playoffs?competition=cup", "", map[string]string{"Authorization": "Bearer " + northToken})
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(suite.T(), `[]`, rec.Body.String())
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreatePlayoffs_AlreadyExists tests that an existing bracket is answered with 409
+func (suite *ServerTestSuite) TestCreatePlayoffs_AlreadyExists() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodPost, "/seasons/2023-2024/playoffs", `{"conferences":["East","West"],"limit":8}`, nil)
+
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "this season already exists")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreatePlayoffs_DryRun tests that a dry run answers with the rows CreatePlayoffs would insert, the final of the
+// two conference leaders and the empty final it always adds, and writes nothing
+func (suite *ServerTestSuite) TestCreatePlayoffs_DryRun() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	for _, conference := range []string{"East", "West"} {
+		suite.mock.ExpectQuery(`SELECT \*, RANK\(\)`).
+			WithArgs(conference, "2023-2024", 1, queries.DefaultLeague).
+			WillReturnRows(sqlmock.NewRows([]string{"team_id", "team_name", "conference"}).AddRow(uuid.New(), conference+" Lions", conference))
+	}
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodPost, "/seasons/2023-2024/playoffs?dryRun=true", `{"conferences":["East","West"],"limit":1}`, nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var preview models.PlayoffsPreviewModel
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &preview))
+	assert.Equal(suite.T(), 2, preview.Rows)
+	if assert.Len(suite.T(), preview.Qualified, 2) {
+		assert.Equal(suite.T(), "West Lions", preview.Qualified[1].TeamName)
+	}
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreatePlayoffs_InvalidBody tests that a malformed body is rejected before reaching the database
+func (suite *ServerTestSuite) TestCreatePlayoffs_InvalidBody() {
+	rec := suite.do(http.MethodPost, "/seasons/2023-2024/playoffs", `{"conferences":`, nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestUpdatePlayoffs_StaleVersion tests that a concurrent update is answered with 409
+func (suite *ServerTestSuite) TestUpdatePlayoffs_StaleVersion() {
+	playoffsID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round >= \$2`).
+		WithArgs("2023-2024", 1, queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT p\.\* FROM playoffs p`).
+		WithArgs(playoffsID, queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "version"}).AddRow(playoffsID, 3))
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodPut, "/playoffs/"+playoffsID.String(), `{"season":"2023-2024","fixtureRound":1,"version":2}`, nil)
+
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestUpdatePlayoffs_InvalidId tests that a malformed playoffs id is answered with 400
+func (suite *ServerTestSuite) TestUpdatePlayoffs_InvalidId() {
+	rec := suite.do(http.MethodPut, "/playoffs/not-a-uuid", `{}`, nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+// TestDeletePlayoffs_NotFound tests that deleting a missing bracket is answered with 404 and records the actor
+func (suite *ServerTestSuite) TestDeletePlayoffs_NotFound() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`UPDATE playoffs SET archived_at = NOW\(\)`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodDelete, "/seasons/2023-2024/playoffs", "", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// EXPECTS THE QUERIES OF ListPlayoffs FOR A SEASON WHOSE BRACKET IS A SINGLE FINAL
+func (suite *ServerTestSuite) expectFinalOnlyBracket(season string) {
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs`).
+		WithArgs(season, queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}).AddRow(1))
+	suite.mock.ExpectQuery(`SELECT fixture_round, game_count FROM playoffs`).
+		WithArgs(season, 1, queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round", "game_count"}).AddRow(1, "FINAL"))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round = \$2 AND game_count = \$3`).
+		WithArgs(season, 1, "FINAL", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "home_team_name"}).AddRow(uuid.New(), 1, "FINAL", "Lions"))
+}
+
+// TestBracketSVG_Success tests drawing a bracket as an SVG image
+func (suite *ServerTestSuite) TestBracketSVG_Success() {
+	suite.expectFinalOnlyBracket("2023-2024")
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/bracket.svg", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "image/svg+xml", rec.Header().Get("Content-Type"))
+	assert.Contains(suite.T(), rec.Body.String(), "<svg")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBracketSVG_NoBracket tests that a season without a bracket is answered with 404
+func (suite *ServerTestSuite) TestBracketSVG_NoBracket() {
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs`).
+		WithArgs("1999-2000", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}))
+
+	rec := suite.do(http.MethodGet, "/seasons/1999-2000/bracket.svg", "", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBracketPNG_Width tests that a PNG of the requested width keeps the aspect ratio of the bracket
+func (suite *ServerTestSuite) TestBracketPNG_Width() {
+	suite.expectFinalOnlyBracket("2023-2024")
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/bracket.png?width=300", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "image/png", rec.Header().Get("Content-Type"))
+	cfg, err := png.DecodeConfig(rec.Body)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 300, cfg.Width)
+	assert.Less(suite.T(), cfg.Height, 300)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBracketPNG_InvalidSize tests that sizes are checked before the bracket is loaded
+func (suite *ServerTestSuite) TestBracketPNG_InvalidSize() {
+	for _, query := range []string{"width=0", "width=wide", "height=-4", "width=8192&height=8192", "height=2049"} {
+		rec := suite.do(http.MethodGet, "/seasons/2023-2024/bracket.png?"+query, "", nil)
+
+		assert.Equal(suite.T(), http.StatusBadRequest, rec.Code, query)
+	}
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBracketPNG_TooHigh tests that a height kept from the aspect ratio of the bracket is held to the limit too
+func (suite *ServerTestSuite) TestBracketPNG_TooHigh() {
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}).AddRow(1))
+	rounds := sqlmock.NewRows([]string{"fixture_round", "game_count"})
+	for i := 1; i <= 32; i++ {
+		rounds.AddRow(1, strconv.Itoa(i))
+	}
+	suite.mock.ExpectQuery(`SELECT fixture_round, game_count FROM playoffs`).
+		WithArgs("2023-2024", 1, queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(rounds)
+	for i := 1; i <= 32; i++ {
+		suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round = \$2 AND game_count = \$3`).
+			WithArgs("2023-2024", 1, strconv.Itoa(i), queries.DefaultLeague, queries.DefaultCompetition).
+			WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count"}).AddRow(uuid.New(), 1, strconv.Itoa(i)))
+	}
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/bracket.png?width=2048", "", nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "the server draws at most 2048")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBracketPDF_Success tests drawing a bracket as a printable PDF
+func (suite *ServerTestSuite) TestBracketPDF_Success() {
+	suite.expectFinalOnlyBracket("2023-2024")
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/bracket.pdf?pageSize=Letter", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "application/pdf", rec.Header().Get("Content-Type"))
+	assert.True(suite.T(), strings.HasPrefix(rec.Body.String(), "%PDF-"))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBracketPDF_InvalidPageSize tests that unknown page sizes are answered with 400
+func (suite *ServerTestSuite) TestBracketPDF_InvalidPageSize() {
+	suite.expectFinalOnlyBracket("2023-2024")
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/bracket.pdf?pageSize=B9", "", nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestSeasonCalendar_Success tests the iCalendar feed of a season
+func (suite *ServerTestSuite) TestSeasonCalendar_Success() {
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}).AddRow(1))
+	suite.mock.ExpectQuery(`SELECT fixture_round, game_count FROM playoffs`).
+		WithArgs("2023-2024", 1, queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round", "game_count"}).AddRow(1, "FINAL"))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round = \$2 AND game_count = \$3`).
+		WithArgs("2023-2024", 1, "FINAL", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "home_team_id", "home_team_name", "season", "scheduled_at"}).
+			AddRow(uuid.New(), 1, "FINAL", uuid.New(), "Lions", "2023-2024", time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC)))
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/calendar.ics", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(suite.T(), rec.Body.String(), "SUMMARY:Lions vs TBD\r\n")
+	assert.Contains(suite.T(), rec.Body.String(), "DTSTART:20240601T190000Z\r\n")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestTeamCalendar_InvalidId tests that a malformed team id is answered with 400
+func (suite *ServerTestSuite) TestTeamCalendar_InvalidId() {
+	rec := suite.do(http.MethodGet, "/teams/not-a-uuid/calendar.ics", "", nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+// TestSchedulePlayoffs_NotFound tests that scheduling a missing game is answered with 404
+func (suite *ServerTestSuite) TestSchedulePlayoffs_NotFound() {
+	playoffsID := uuid.New()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE playoffs_id = \$1`).
+		WithArgs(playoffsID, queries.DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodPut, "/playoffs/"+playoffsID.String()+"/schedule", `{"scheduledAt":"2024-06-01T21:00:00+02:00"}`, nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBracketDocument_Success tests exporting the bracket of a season as a document
+func (suite *ServerTestSuite) TestBracketDocument_Success() {
+	suite.expectFinalOnlyBracket("2023-2024")
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/bracket.json", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var doc document.Document
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(suite.T(), document.Version, doc.Version)
+	assert.Equal(suite.T(), queries.DefaultLeague, doc.League)
+	require.Len(suite.T(), doc.Rounds, 1)
+	assert.Equal(suite.T(), "Final", doc.Rounds[0].Name)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportBracket_Success tests restoring a document in the league of the request
+func (suite *ServerTestSuite) TestImportBracket_Success() {
+	lions, gameId := uuid.New(), uuid.New()
+	body := `{"version":1,"league":"south","season":"2023-2024","competition":"main","status":"complete","champion":null,
+		"teams":[{"id":"` + lions.String() + `","name":"Lions","logoUrl":null,"seed":1}],
+		"rounds":[{"number":0,"name":"Final","series":[{"label":"FINAL","final":true,"bestOf":1,"status":"decided",
+		"home":{"teamId":"` + lions.String() + `","wins":1},"away":{"teamId":null,"wins":0},"winner":null,
+		"games":[{"id":"` + gameId.String() + `","number":"1","homeTeamId":"` + lions.String() + `","awayTeamId":null,
+		"playersInHomeId":"` + uuid.Nil.String() + `","playersInAwayId":"` + uuid.Nil.String() + `",
+		"winner":"` + lions.String() + `","version":3,"scheduledAt":null}]}]}]}`
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs`).
+		WithArgs("2023-2024", "north", queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(`SELECT COUNT\(\*\) AS count FROM playoffs WHERE playoffs_id = ANY`).
+		WithArgs(pq.StringArray{gameId.String()}).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectExec(`INSERT INTO playoffs`).
+		WithArgs(gameId, 0, "FINAL", "1", lions, "Lions", nil, uuid.Nil, nil, nil, nil, uuid.Nil, "2023-2024", "north", queries.DefaultCompetition, lions, 3, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO playoffs_audit`).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO outbox`).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+	suite.mock.ExpectQuery(`SELECT fixture_round FROM playoffs`).
+		WithArgs("2023-2024", "north", queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round"}).AddRow(0))
+	suite.mock.ExpectQuery(`SELECT fixture_round, game_count FROM playoffs`).
+		WithArgs("2023-2024", 0, "north", queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"fixture_round", "game_count"}).AddRow(0, "FINAL"))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND fixture_round = \$2 AND game_count = \$3`).
+		WithArgs("2023-2024", 0, "FINAL", "north", queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "home_team_id", "home_team_name", "winner", "competition", "version"}).
+			AddRow(gameId, 0, "FINAL", lions, "Lions", lions, queries.DefaultCompetition, 3))
+
+	rec := suite.do(http.MethodPost, "/brackets", body, map[string]string{"Authorization": "Bearer " + northToken})
+
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code, rec.Body.String())
+	var doc document.Document
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(suite.T(), "north", doc.League)
+	assert.Equal(suite.T(), document.StatusComplete, doc.Status)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportBracket_UnsupportedVersion tests that a document of another version is answered with 400
+func (suite *ServerTestSuite) TestImportBracket_UnsupportedVersion() {
+	rec := suite.do(http.MethodPost, "/brackets", `{"version":2,"season":"2023-2024"}`, nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "version 2 is not supported")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportBracket_TooLarge tests that a document over the limit is answered with 413
+func (suite *ServerTestSuite) TestImportBracket_TooLarge() {
+	rec := suite.do(http.MethodPost, "/brackets", `{"version":1,"season":"`+strings.Repeat("a", maxBody)+`"}`, nil)
+
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestBracketSchema_Served tests that the JSON Schema of the documents is served
+func (suite *ServerTestSuite) TestBracketSchema_Served() {
+	rec := suite.do(http.MethodGet, "/schemas/bracket.json", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "application/schema+json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(suite.T(), string(document.Schema), rec.Body.String())
+}
+
+// TestBackupSeason_Archive tests downloading the backup archive of a season
+func (suite *ServerTestSuite) TestBackupSeason_Archive() {
+	standingsID := uuid.New()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \* FROM standings`).
+		WithArgs("2023-2024", "north").
+		WillReturnRows(sqlmock.NewRows([]string{"standings_id", "team_name", "season"}).AddRow(standingsID, "Lions", "2023-2024"))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season`).WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs_audit`).WillReturnRows(sqlmock.NewRows([]string{"audit_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs_changes`).WillReturnRows(sqlmock.NewRows([]string{"change_seq"}))
+	suite.mock.ExpectQuery(`SELECT NOW\(\)`).WillReturnRows(sqlmock.NewRows([]string{"now"}).AddRow(time.Now()))
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/backup", "", map[string]string{"Authorization": "Bearer " + northToken})
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "application/gzip", rec.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), `attachment; filename="north-2023-2024.json.gz"`, rec.Header().Get("Content-Disposition"))
+	b, err := backup.Read(rec.Body)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), b.Standings, 1)
+	assert.Equal(suite.T(), standingsID, b.Standings[0].StandingsId)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRestoreSeason_Conflict tests that restoring over an existing season without replace is answered with 409
+func (suite *ServerTestSuite) TestRestoreSeason_Conflict() {
+	var archive bytes.Buffer
+	require.NoError(suite.T(), backup.Write(&archive, models.SeasonBackupModel{
+		Season:    "2023-2024",
+		Standings: []models.StandingsModel{{StandingsId: uuid.New(), TeamName: "Lions", Conference: "East", Season: "2023-2024"}},
+	}))
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM standings`).
+		WithArgs("2023-2024", queries.DefaultLeague).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodPost, "/backups/restore", archive.String(), nil)
+
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "replace")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRestoreSeason_InvalidArchive tests that a body that is not an archive is answered with 400
+func (suite *ServerTestSuite) TestRestoreSeason_InvalidArchive() {
+	rec := suite.do(http.MethodPost, "/backups/restore?replace=true", `{"season":"2023-2024"}`, nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "invalid backup archive")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRestoreSeason_TooLarge tests that a body over the limit is answered with 413 without being read whole
+func (suite *ServerTestSuite) TestRestoreSeason_TooLarge() {
+	var archive bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&archive, gzip.NoCompression)
+	require.NoError(suite.T(), err)
+	_, _ = zw.Write([]byte(`{"season":"`))
+	_, _ = zw.Write(bytes.Repeat([]byte("a"), maxBackupBody))
+	require.NoError(suite.T(), zw.Close())
+
+	rec := suite.do(http.MethodPost, "/backups/restore", archive.String(), nil)
+
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestPlayoffsFormat_Success tests answering with the format recorded when the bracket was created
+func (suite *ServerTestSuite) TestPlayoffsFormat_Success() {
+	suite.mock.ExpectQuery(`SELECT after_value FROM playoffs_audit`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition, queries.AuditCreatePlayoffs).
+		WillReturnRows(sqlmock.NewRows([]string{"after_value"}).AddRow([]byte(`{"competition":"main","conferences":["East"],"limit":1}`)))
+	suite.expectFinalOnlyBracket("2023-2024")
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/playoffs/format", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var format models.PlayoffsFormatModel
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &format))
+	assert.Equal(suite.T(), []string{"East"}, format.Conferences)
+	assert.Equal(suite.T(), queries.SeedingPoints, format.Seeding)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestClonePlayoffs_UnknownFormat tests that cloning a season without a recorded format is answered with 404
+func (suite *ServerTestSuite) TestClonePlayoffs_UnknownFormat() {
+	suite.mock.ExpectQuery(`SELECT after_value FROM playoffs_audit`).
+		WithArgs("2022-2023", queries.DefaultLeague, "cup", queries.AuditCreatePlayoffs).
+		WillReturnRows(sqlmock.NewRows([]string{"after_value"}))
+
+	rec := suite.do(http.MethodPost, "/seasons/2023-2024/playoffs/clone", `{"fromSeason":"2022-2023","competition":"cup"}`, nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestClonePlayoffs_MissingFromSeason tests that a clone without the season to copy is answered with 400
+func (suite *ServerTestSuite) TestClonePlayoffs_MissingFromSeason() {
+	rec := suite.do(http.MethodPost, "/seasons/2023-2024/playoffs/clone", `{}`, nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "fromSeason is required")
+}
+
+// TestValidatePlayoffs_Valid tests the report of a bracket without violations
+func (suite *ServerTestSuite) TestValidatePlayoffs_Valid() {
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs WHERE season = \$1 AND league = \$2 AND competition = \$3 AND archived_at IS NULL`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "game_round", "home_team_name"}).AddRow(uuid.New(), 1, "FINAL", "1", "Lions"))
+
+	rec := suite.do(http.MethodGet, "/seasons/2023-2024/playoffs/validate", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var report models.BracketIntegrityModel
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(suite.T(), "2023-2024", report.Season)
+	assert.Empty(suite.T(), report.Violations)
+	assert.True(suite.T(), report.Valid)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRepairPlayoffs_NotFound tests that repairing a season without a bracket is answered with 404
+func (suite *ServerTestSuite) TestRepairPlayoffs_NotFound() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs`).
+		WithArgs("1999-2000", queries.DefaultLeague, "cup").
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs`).
+		WithArgs("1999-2000", queries.DefaultLeague, "cup").
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodPost, "/seasons/1999-2000/playoffs/repair?competition=cup", "", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestRecomputePlayoffs_NoFinal tests that a bracket without a final is answered with 400
+func (suite *ServerTestSuite) TestRecomputePlayoffs_NoFinal() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`SELECT playoffs_id FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id"}))
+	suite.mock.ExpectQuery(`SELECT \* FROM playoffs`).
+		WithArgs("2023-2024", queries.DefaultLeague, queries.DefaultCompetition).
+		WillReturnRows(sqlmock.NewRows([]string{"playoffs_id", "fixture_round", "game_count", "game_round"}).AddRow(uuid.New(), 1, "1", "1"))
+	suite.mock.ExpectRollback()
+
+	rec := suite.do(http.MethodPost, "/seasons/2023-2024/playoffs/recompute", "", nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "the rounds can not be rebuilt")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreateStandings_Success tests creating a standings record
+func (suite *ServerTestSuite) TestCreateStandings_Success() {
+	suite.mock.ExpectExec(`INSERT INTO standings`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	rec := suite.do(http.MethodPost, "/standings", `{"teamName":"Lions","conference":"East","season":"2023-2024","pts":21}`, nil)
+
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	var created models.StandingsModel
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.NotEqual(suite.T(), uuid.Nil, created.StandingsId)
+	assert.Equal(suite.T(), 21, created.Pts)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreateStandings_InvalidInput tests that a validation error is answered with 400
+func (suite *ServerTestSuite) TestCreateStandings_InvalidInput() {
+	rec := suite.do(http.MethodPost, "/standings", `{"teamName":"Lions"}`, nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "conference and season are required")
+}
+
+// TestCreateStandings_TooLarge tests that a JSON body over the limit is answered with 413
+func (suite *ServerTestSuite) TestCreateStandings_TooLarge() {
+	rec := suite.do(http.MethodPost, "/standings", `{"teamName":"`+strings.Repeat("a", maxBody)+`"}`, nil)
+
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, rec.Code)
+	assert.JSONEq(suite.T(), `{"error":"the request body is larger than 4 MiB"}`, rec.Body.String())
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestListStandings_MissingSeason tests that the season parameter is required
+func (suite *ServerTestSuite) TestListStandings_MissingSeason() {
+	rec := suite.do(http.MethodGet, "/standings", "", nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+// TestStandingsCSV_Success tests downloading the standings of a season as a spreadsheet
+func (suite *ServerTestSuite) TestStandingsCSV_Success() {
+	suite.mock.ExpectQuery(`SELECT \* FROM standings`).
+		WithArgs("2023-2024", queries.DefaultLeague, "").
+		WillReturnRows(sqlmock.NewRows([]string{"standings_id", "team_name", "conference", "season", "pts"}).AddRow(uuid.New(), "Lions", "East", "2023-2024", 21))
+
+	rec := suite.do(http.MethodGet, "/standings.csv?season=2023-2024", "", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), `attachment; filename="standings-2023-2024.csv"`, rec.Header().Get("Content-Disposition"))
+	assert.Contains(suite.T(), rec.Body.String(), "position,team_name,acronym,conference,season,")
+	assert.Contains(suite.T(), rec.Body.String(), ",Lions,,East,2023-2024,")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportStandings_DryRun tests that a dry run checks the file without writing it
+func (suite *ServerTestSuite) TestImportStandings_DryRun() {
+	rec := suite.do(http.MethodPost, "/standings/import?dryRun=true&season=2023-2024", "team_name,conference,pts\nLions,East,21\n", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var res importStandingsRes
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.True(suite.T(), res.DryRun)
+	assert.Equal(suite.T(), 1, res.Rows)
+	assert.Empty(suite.T(), res.Problems)
+	require.Len(suite.T(), res.Standings, 1)
+	assert.Equal(suite.T(), "2023-2024", res.Standings[0].Season)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportStandings_Problems tests that a file with problems imports nothing and reports every problem
+func (suite *ServerTestSuite) TestImportStandings_Problems() {
+	rec := suite.do(http.MethodPost, "/standings/import", "team_name,conference,season,w\nLions,East,2023-2024,eight\n,East,2023-2024,3\n", nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	var res importStandingsRes
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(suite.T(), 2, res.Rows)
+	require.Len(suite.T(), res.Problems, 2)
+	assert.Equal(suite.T(), "w", res.Problems[0].Column)
+	assert.Equal(suite.T(), 3, res.Problems[1].Line)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportStandings_TooLarge tests that a CSV body over the limit is answered with 413
+func (suite *ServerTestSuite) TestImportStandings_TooLarge() {
+	rec := suite.do(http.MethodPost, "/standings/import?season=2023-2024", "team_name,conference,pts\n"+strings.Repeat("a", maxBody)+",East,21\n", nil)
+
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestImportStandings_Success tests importing the standings of a CSV file
+func (suite *ServerTestSuite) TestImportStandings_Success() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(`INSERT INTO standings`).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(`INSERT INTO standings`).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+
+	rec := suite.do(http.MethodPost, "/standings/import", "team_name;conference;season\r\nLions;East;2023-2024\r\nTigers;East;2023-2024\r\n", nil)
+
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	var res importStandingsRes
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Len(suite.T(), res.Standings, 2)
+	assert.NotEqual(suite.T(), uuid.Nil, res.Standings[0].StandingsId)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestGetStandings_DatabaseError tests that unknown errors are answered with 500 without leaking the details
+func (suite *ServerTestSuite) TestGetStandings_DatabaseError() {
+	standingsID := uuid.New()
+
+	suite.mock.ExpectQuery(`SELECT \* FROM standings WHERE standings_id = \$1`).
+		WithArgs(standingsID, queries.DefaultLeague).
+		WillReturnError(errors.New("connection reset by peer"))
+
+	rec := suite.do(http.MethodGet, "/standings/"+standingsID.String(), "", nil)
+
+	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
+	assert.NotContains(suite.T(), rec.Body.String(), "connection reset")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestDeleteStandings_Success tests deleting a standings record
+func (suite *ServerTestSuite) TestDeleteStandings_Success() {
+	standingsID := uuid.New()
+
+	suite.mock.ExpectExec(`DELETE FROM standings WHERE standings_id = \$1 AND league = \$2`).
+		WithArgs(standingsID, "north").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	rec := suite.do(http.MethodDelete, "/standings/"+standingsID.String(), "", map[string]string{"Authorization": "Bearer " + northToken})
+
+	assert.Equal(suite.T(), http.StatusNoContent, rec.Code)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestCreateWebhook_InvalidURL tests that a relative webhook url is answered with 400
+func (suite *ServerTestSuite) TestCreateWebhook_InvalidURL() {
+	rec := suite.do(http.MethodPost, "/webhooks", `{"url":"/hook","secret":"s3cret"}`, nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "invalid webhook url")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// TestListWebhooks_WithoutSecrets tests that listing the webhooks never returns their secrets
+func (suite *ServerTestSuite) TestListWebhooks_WithoutSecrets() {
+	suite.mock.ExpectQuery(`SELECT webhook_id, league, url, event_types, created_at FROM webhooks WHERE league = \$1`).
+		WithArgs("north").
+		WillReturnRows(sqlmock.NewRows([]string{"webhook_id", "league", "url", "event_types", "created_at"}).
+			AddRow(uuid.New(), "north", "https://partner.test/hook", "{series.decided}", time.Now()))
+
+	rec := suite.do(http.MethodGet, "/webhooks", "", map[string]string{"Authorization": "Bearer " + northToken})
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "series.decided")
+	assert.NotContains(suite.T(), rec.Body.String(), "secret")
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/spreadsheet"
+)
+
+const csvContentType = "text/csv; charset=utf-8"
+
+type importStandingsRes struct {
+	DryRun    bool                    `json:"dryRun"`
+	Rows      int                     `json:"rows"`
+	Problems  []spreadsheet.Problem   `json:"problems"`
+	Standings []models.StandingsModel `json:"standings"`
+}
+
+// THE BRACKET OF A SEASON AS A CSV FILE WITH A ROW PER GAME
+func (s *Server) playoffsCSV(w http.ResponseWriter, r *http.Request) {
+	season, competition := r.PathValue("season"), r.URL.Query().Get("competition")
+	playoffs, err := s.connection(r).ListPlayoffs(season, competition)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(playoffs) == 0 {
+		writeJSON(w, http.StatusNotFound, errorRes{Error: "season " + season + " has no bracket"})
+		return
+	}
+	writeCSV(w, "playoffs-"+season+".csv", func(out io.Writer) error {
+		return spreadsheet.WritePlayoffs(out, playoffs)
+	})
+}
+
+// THE STANDINGS OF ?season= AS A CSV FILE, OPTIONALLY FILTERED BY ?conference=
+func (s *Server) standingsCSV(w http.ResponseWriter, r *http.Request) {
+	season := r.URL.Query().Get("season")
+	if season == "" {
+		writeBadRequest(w, "the season query parameter is required")
+		return
+	}
+	standings, err := s.connection(r).ListStandings(season, r.URL.Query().Get("conference"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeCSV(w, "standings-"+season+".csv", func(out io.Writer) error {
+		return spreadsheet.WriteStandings(out, standings)
+	})
+}
+
+// IMPORTS THE STANDINGS OF A CSV BODY, ALL OR NOTHING. A FILE WITH PROBLEMS IS ANSWERED WITH 400 AND EVERY PROBLEM
+// FOUND; ?dryRun=true ONLY CHECKS THE FILE AND ANSWERS WITH THE RECORDS IT WOULD IMPORT
+func (s *Server) importStandings(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			writeBadRequest(w, "invalid dryRun: "+v)
+			return
+		}
+		dryRun = parsed
+	}
+	standings, report, err := spreadsheet.ReadStandings(http.MaxBytesReader(w, r.Body, maxBody), r.URL.Query().Get("season"))
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	res := importStandingsRes{DryRun: dryRun, Rows: report.Rows, Problems: report.Problems, Standings: standings}
+	if res.Problems == nil {
+		res.Problems = []spreadsheet.Problem{}
+	}
+	if !report.OK() {
+		writeJSON(w, http.StatusBadRequest, res)
+		return
+	}
+	if dryRun {
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+	imported, errI := s.connection(r).ImportStandings(standings)
+	if errI != nil {
+		writeError(w, errI)
+		return
+	}
+	res.Standings = imported
+	writeJSON(w, http.StatusCreated, res)
+}
+
+// ANSWERS WITH THE FILE write PRODUCES, OFFERED FOR DOWNLOAD AS name
+func writeCSV(w http.ResponseWriter, name string, write func(io.Writer) error) {
+	var file bytes.Buffer
+	if err := write(&file); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", csvContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+spreadsheetFileName(name)+`"`)
+	if _, err := w.Write(file.Bytes()); err != nil {
+		log.Println("failed to write the csv file: ", err.Error())
+	}
+}
+
+// KEEPS THE CHARACTERS OF A FILE NAME THAT ARE SAFE IN A HEADER
+func spreadsheetFileName(name string) string {
+	safe := []byte(name)
+	for i, c := range safe {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			safe[i] = '_'
+		}
+	}
+	return string(safe)
+}
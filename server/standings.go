@@ -0,0 +1,83 @@
+package server
+
+import (
+	"net/http"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+func (s *Server) createStandings(w http.ResponseWriter, r *http.Request) {
+	var req models.StandingsModel
+	if err := decodeBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	standings, err := s.connection(r).CreateStandings(req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, standings)
+}
+
+// LISTS THE STANDINGS OF ?season=, OPTIONALLY FILTERED BY ?conference=
+func (s *Server) listStandings(w http.ResponseWriter, r *http.Request) {
+	season := r.URL.Query().Get("season")
+	if season == "" {
+		writeBadRequest(w, "the season query parameter is required")
+		return
+	}
+	standings, err := s.connection(r).ListStandings(season, r.URL.Query().Get("conference"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, standings)
+}
+
+func (s *Server) getStandings(w http.ResponseWriter, r *http.Request) {
+	standingsId, err := uuid.Parse(r.PathValue("standingsId"))
+	if err != nil {
+		writeBadRequest(w, "invalid standings id: "+r.PathValue("standingsId"))
+		return
+	}
+	standings, errG := s.connection(r).GetStandings(standingsId)
+	if errG != nil {
+		writeError(w, errG)
+		return
+	}
+	writeJSON(w, http.StatusOK, standings)
+}
+
+func (s *Server) updateStandings(w http.ResponseWriter, r *http.Request) {
+	standingsId, err := uuid.Parse(r.PathValue("standingsId"))
+	if err != nil {
+		writeBadRequest(w, "invalid standings id: "+r.PathValue("standingsId"))
+		return
+	}
+	var req models.StandingsModel
+	if errD := decodeBody(w, r, &req); errD != nil {
+		writeBodyError(w, errD)
+		return
+	}
+	if errU := s.connection(r).UpdateStandings(standingsId, req); errU != nil {
+		writeError(w, errU)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteStandings(w http.ResponseWriter, r *http.Request) {
+	standingsId, err := uuid.Parse(r.PathValue("standingsId"))
+	if err != nil {
+		writeBadRequest(w, "invalid standings id: "+r.PathValue("standingsId"))
+		return
+	}
+	if errD := s.connection(r).DeleteStandings(standingsId); errD != nil {
+		writeError(w, errD)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// NUMBER OF DELIVERY ATTEMPTS RETURNED WHEN ?limit= IS NOT GIVEN
+const defaultDeliveriesLimit = 100
+
+type createWebhookReq struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req createWebhookReq
+	if err := decodeBody(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	webhook, err := s.connection(r).CreateWebhook(req.URL, req.Secret, req.EventTypes)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, webhook)
+}
+
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := s.connection(r).ListWebhooks()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, webhooks)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookId, err := uuid.Parse(r.PathValue("webhookId"))
+	if err != nil {
+		writeBadRequest(w, "invalid webhook id: "+r.PathValue("webhookId"))
+		return
+	}
+	if errD := s.connection(r).DeleteWebhook(webhookId); errD != nil {
+		writeError(w, errD)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookId, err := uuid.Parse(r.PathValue("webhookId"))
+	if err != nil {
+		writeBadRequest(w, "invalid webhook id: "+r.PathValue("webhookId"))
+		return
+	}
+	limit := defaultDeliveriesLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, errL := strconv.Atoi(l)
+		if errL != nil || parsed < 1 {
+			writeBadRequest(w, "invalid limit: "+l)
+			return
+		}
+		limit = parsed
+	}
+	deliveries, errD := s.connection(r).ListWebhookDeliveries(webhookId, limit)
+	if errD != nil {
+		writeError(w, errD)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
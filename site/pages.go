@@ -0,0 +1,245 @@
+package site
+
+import (
+	"bytes"
+	"html/template"
+	"sort"
+	"strconv"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// A TEAM AS SHOWN ON A PAGE. Href IS EMPTY FOR A SLOT THAT IS NOT FILLED YET
+type teamRef struct {
+	Name    string
+	Href    string
+	LogoURL string
+	Wins    int
+	Winner  bool
+}
+
+type gameView struct {
+	Game   string
+	Home   teamRef
+	Away   teamRef
+	Winner *teamRef
+}
+
+type seriesView struct {
+	Title       string
+	Label       string
+	Round       string
+	Href        string
+	SeasonTitle string
+	SeasonHref  string
+	Home        teamRef
+	Away        teamRef
+	Decided     bool
+	Games       []gameView
+}
+
+type roundView struct {
+	Title  string
+	Series []seriesView
+}
+
+type seasonView struct {
+	Season      string
+	Competition string
+	Title       string
+	Href        string
+	Games       int
+	Champion    *teamRef
+	RunnerUp    *teamRef
+	// "4-2" WHEN THE FINAL IS A SERIES
+	FinalScore string
+	SVG        template.HTML
+	Rounds     []roundView
+}
+
+// A SERIES FROM THE POINT OF VIEW OF ONE OF ITS TEAMS
+type teamSeries struct {
+	SeasonTitle string
+	SeasonHref  string
+	Round       string
+	SeriesHref  string
+	Opponent    teamRef
+	Result      string
+}
+
+type teamView struct {
+	Name    string
+	Href    string
+	LogoURL string
+	Titles  int
+	Series  []teamSeries
+}
+
+type site struct {
+	title   string
+	seasons []seasonView
+	teams   map[uuid.UUID]*teamView
+}
+
+func newSite(title string) *site {
+	return &site{title: title, teams: map[uuid.UUID]*teamView{}}
+}
+
+func teamHref(id uuid.UUID) string {
+	return "teams/" + id.String() + ".html"
+}
+
+func refOf(t bracket.Team) teamRef {
+	if t.Id == nil {
+		return teamRef{Name: "TBD"}
+	}
+	name := t.Name
+	if name == "" {
+		name = "TBD"
+	}
+	return teamRef{Name: name, Href: teamHref(*t.Id), LogoURL: t.LogoURL, Wins: t.Wins, Winner: t.Winner}
+}
+
+// THE TEAM PAGE OF t, CREATED THE FIRST TIME THE TEAM APPEARS. THE NAME AND LOGO OF THE NEWEST SEASON WIN
+// SINCE SEASONS ARE ADDED NEWEST FIRST
+func (s *site) team(t bracket.Team) *teamView {
+	view, ok := s.teams[*t.Id]
+	if !ok {
+		view = &teamView{Name: t.Name, Href: teamHref(*t.Id), LogoURL: t.LogoURL}
+		s.teams[*t.Id] = view
+	}
+	return view
+}
+
+// ADDS THE PAGES OF THE BRACKET OF A SEASON AND ITS SERIES TO THE PAGES OF THE TEAMS THAT PLAYED THEM
+func (s *site) addSeason(season models.SeasonModel, playoffs [][][]models.PlayoffsModel) error {
+	layout := bracket.NewLayout(playoffs, bracket.DefaultOptions)
+	view := seasonView{
+		Season:      season.Season,
+		Competition: season.Competition,
+		Title:       bracket.Title(season.Season, season.Competition),
+		Href:        seasonDir(season) + "/index.html",
+		Games:       season.Games,
+	}
+	var svg bytes.Buffer
+	if err := bracket.WriteSVG(&svg, layout, view.Title); err != nil {
+		return err
+	}
+	// WriteSVG ESCAPES EVERY TEXT AND URL OF THE BRACKET
+	view.SVG = template.HTML(svg.String())
+
+	rounds := map[int]int{}
+	for _, b := range layout.Boxes {
+		if _, ok := rounds[b.Round]; !ok {
+			rounds[b.Round] = len(view.Rounds)
+			view.Rounds = append(view.Rounds, roundView{Title: layout.Rounds[b.Round].Text})
+		}
+		series := seriesView{
+			Label:       b.Label,
+			Round:       layout.Rounds[b.Round].Text,
+			Href:        seasonDir(season) + "/" + seriesFile(b.Round+1, b.Series),
+			SeasonTitle: view.Title,
+			SeasonHref:  view.Href,
+			Home:        refOf(b.Home),
+			Away:        refOf(b.Away),
+			Decided:     b.Decided,
+		}
+		series.Title = view.Title + " · " + seriesName(b.Series)
+		for i, g := range b.Games {
+			game := gameView{Game: g.GameRound, Home: series.Home, Away: series.Away}
+			if game.Game == "" {
+				game.Game = strconv.Itoa(i + 1)
+			}
+			switch {
+			case g.Winner == nil:
+			case b.Home.Id != nil && *g.Winner == *b.Home.Id:
+				game.Winner = &series.Home
+			case b.Away.Id != nil && *g.Winner == *b.Away.Id:
+				game.Winner = &series.Away
+			}
+			series.Games = append(series.Games, game)
+		}
+		r := &view.Rounds[rounds[b.Round]]
+		r.Series = append(r.Series, series)
+
+		s.addTeamSeries(b.Home, b.Away, series)
+		s.addTeamSeries(b.Away, b.Home, series)
+		if b.Final && b.Decided {
+			champion, runnerUp := series.Home, series.Away
+			if b.Away.Winner {
+				champion, runnerUp = series.Away, series.Home
+			}
+			view.Champion, view.RunnerUp = &champion, &runnerUp
+			if len(b.Games) > 1 {
+				view.FinalScore = strconv.Itoa(champion.Wins) + "-" + strconv.Itoa(runnerUp.Wins)
+			}
+			if b.Home.Winner {
+				s.team(b.Home).Titles++
+			} else {
+				s.team(b.Away).Titles++
+			}
+		}
+	}
+	s.seasons = append(s.seasons, view)
+	return nil
+}
+
+func seriesName(s bracket.Series) string {
+	if s.Final {
+		return "Final"
+	}
+	return "Series " + s.Label
+}
+
+func (s *site) addTeamSeries(t bracket.Team, opponent bracket.Team, series seriesView) {
+	if t.Id == nil {
+		return
+	}
+	result := strconv.Itoa(t.Wins) + "-" + strconv.Itoa(opponent.Wins)
+	switch {
+	case t.Winner:
+		result = "Won " + result
+	case opponent.Winner:
+		result = "Lost " + result
+	default:
+		result = "In progress " + result
+	}
+	view := s.team(t)
+	view.Series = append(view.Series, teamSeries{
+		SeasonTitle: series.SeasonTitle,
+		SeasonHref:  series.SeasonHref,
+		Round:       series.Round,
+		SeriesHref:  series.Href,
+		Opponent:    refOf(opponent),
+		Result:      result,
+	})
+}
+
+// THE SEASONS WHOSE FINAL IS DECIDED, NEWEST FIRST
+func (s *site) champions() []seasonView {
+	var champions []seasonView
+	for _, season := range s.seasons {
+		if season.Champion != nil {
+			champions = append(champions, season)
+		}
+	}
+	return champions
+}
+
+// THE TEAMS BY NAME
+func (s *site) teamList() []*teamView {
+	teams := make([]*teamView, 0, len(s.teams))
+	for _, t := range s.teams {
+		teams = append(teams, t)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		if teams[i].Name != teams[j].Name {
+			return teams[i].Name < teams[j].Name
+		}
+		return teams[i].Href < teams[j].Href
+	})
+	return teams
+}
@@ -0,0 +1,34 @@
+package site
+
+import (
+	"path/filepath"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+)
+
+// REGENERATES THE SITE OF A LEAGUE AFTER EVERY COMMITTED CHANGE OF ITS BRACKETS. PASS Handle TO AN outbox.Relay
+// SO A FAILED REGENERATION IS RETRIED
+type Publisher struct {
+	conn  *queries.PlayoffsDBConnection
+	dir   string
+	title string
+}
+
+// THE SITE OF EACH LEAGUE IS WRITTEN TO dir/<league>
+func NewPublisher(conn *queries.PlayoffsDBConnection, dir string, title string) *Publisher {
+	return &Publisher{conn: conn, dir: dir, title: title}
+}
+
+func (p *Publisher) Handle(change models.BracketChangeModel, attempt int) error {
+	league := p.conn.ForLeague(change.League)
+	return Generate(league, p.Dir(change.League), p.title)
+}
+
+// THE DIRECTORY OF THE SITE OF A LEAGUE
+func (p *Publisher) Dir(league string) string {
+	if league == "" {
+		league = queries.DefaultLeague
+	}
+	return filepath.Join(p.dir, slug(league))
+}
@@ -0,0 +1,205 @@
+// PACKAGE site WRITES THE BRACKETS OF A LEAGUE AS A SELF-CONTAINED STATIC WEBSITE: AN INDEX OF THE SEASONS, A
+// BRACKET PAGE PER SEASON, A PAGE PER SERIES, A PAGE PER TEAM AND THE HISTORY OF THE CHAMPIONS. LINKS ARE RELATIVE,
+// SO THE DIRECTORY CAN BE HOSTED UNDER ANY PATH OF ANY STATIC HOST
+package site
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/bracket"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+)
+
+//go:embed templates/*.html templates/style.css
+var templates embed.FS
+
+// THE QUERIES THE SITE IS BUILT FROM, SCOPED TO ONE LEAGUE. *queries.PlayoffsDBConnection IMPLEMENTS IT
+type Store interface {
+	ListSeasons() ([]models.SeasonModel, error)
+	ListPlayoffs(season string, competition string) ([][][]models.PlayoffsModel, error)
+}
+
+// TITLE OF THE SITE WHEN NONE IS GIVEN
+const DefaultTitle = "Playoffs"
+
+// EVERY PAGE IS RENDERED WITH THE SHARED LAYOUT
+var pageNames = []string{"index.html", "season.html", "series.html", "team.html", "champions.html"}
+
+var (
+	pagesOnce sync.Once
+	pages     map[string]*template.Template
+)
+
+func loadPages() map[string]*template.Template {
+	pagesOnce.Do(func() {
+		pages = map[string]*template.Template{}
+		for _, name := range pageNames {
+			pages[name] = template.Must(template.ParseFS(templates, "templates/layout.html", "templates/"+name))
+		}
+	})
+	return pages
+}
+
+// WHAT EVERY TEMPLATE RECEIVES. Root LEADS FROM THE PAGE BACK TO THE ROOT OF THE SITE
+type page struct {
+	Site  string
+	Title string
+	Root  string
+	Data  any
+}
+
+// WRITES THE SITE OF THE SEASONS OF store TO dir. THE SITE IS BUILT NEXT TO dir AND THEN SWAPPED IN, SO A HOST
+// SERVING dir NEVER SEES HALF OF A REGENERATION
+func Generate(store Store, dir string, title string) error {
+	if title == "" {
+		title = DefaultTitle
+	}
+	seasons, err := store.ListSeasons()
+	if err != nil {
+		return err
+	}
+	s := newSite(title)
+	for _, season := range seasons {
+		playoffs, errL := store.ListPlayoffs(season.Season, season.Competition)
+		if errL != nil {
+			return errL
+		}
+		if len(playoffs) == 0 {
+			continue
+		}
+		if errA := s.addSeason(season, playoffs); errA != nil {
+			return errA
+		}
+	}
+
+	dir = filepath.Clean(dir)
+	if errM := os.MkdirAll(filepath.Dir(dir), 0o755); errM != nil {
+		return errM
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	if errW := s.write(tmp); errW != nil {
+		return errW
+	}
+	if errC := os.Chmod(tmp, 0o755); errC != nil {
+		return errC
+	}
+	return swap(tmp, dir)
+}
+
+// REPLACES dir WITH THE DIRECTORY next, KEEPING THE PREVIOUS SITE UNTIL next IS IN PLACE
+func swap(next string, dir string) error {
+	old := next + ".old"
+	if err := os.Rename(dir, old); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Rename(next, dir); err != nil {
+		if errR := os.Rename(old, dir); errR != nil && !os.IsNotExist(errR) {
+			log.Println("failed to restore the previous site: ", errR.Error())
+		}
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// WRITES EVERY PAGE OF THE SITE UNDER dir
+func (s *site) write(dir string) error {
+	css, err := fs.ReadFile(templates, "templates/style.css")
+	if err != nil {
+		return err
+	}
+	if errW := writeFile(dir, "style.css", css); errW != nil {
+		return errW
+	}
+	index := struct {
+		Seasons []seasonView
+		Teams   []*teamView
+	}{s.seasons, s.teamList()}
+	if errR := s.render(dir, "index.html", "index.html", s.title, index); errR != nil {
+		return errR
+	}
+	if errR := s.render(dir, "champions.html", "champions.html", "Champions", s.champions()); errR != nil {
+		return errR
+	}
+	for _, season := range s.seasons {
+		if errR := s.render(dir, season.Href, "season.html", season.Title, season); errR != nil {
+			return errR
+		}
+		for _, round := range season.Rounds {
+			for _, series := range round.Series {
+				if errR := s.render(dir, series.Href, "series.html", series.Title, series); errR != nil {
+					return errR
+				}
+			}
+		}
+	}
+	for _, team := range s.teamList() {
+		if errR := s.render(dir, team.Href, "team.html", team.Name, team); errR != nil {
+			return errR
+		}
+	}
+	return nil
+}
+
+// RENDERS THE TEMPLATE name TO THE PAGE AT path, RELATIVE TO THE ROOT OF THE SITE
+func (s *site) render(dir string, path string, name string, title string, data any) error {
+	root := strings.Repeat("../", strings.Count(path, "/"))
+	var buf bytes.Buffer
+	if err := loadPages()[name].ExecuteTemplate(&buf, "layout", page{Site: s.title, Title: title, Root: root, Data: data}); err != nil {
+		return fmt.Errorf("rendering %s: %w", path, err)
+	}
+	return writeFile(dir, path, buf.Bytes())
+}
+
+func writeFile(dir string, path string, content []byte) error {
+	file := filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, content, 0o644)
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// A FILE NAME SAFE ON EVERY HOST FOR A SEASON OR COMPETITION
+func slug(text string) string {
+	s := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(text), "-"), "-")
+	if s == "" {
+		return "x"
+	}
+	return s
+}
+
+// THE DIRECTORY OF A SEASON; THE MAIN COMPETITION KEEPS THE PLAIN SEASON NAME
+func seasonDir(season models.SeasonModel) string {
+	if season.Competition == "" || season.Competition == queries.DefaultCompetition {
+		return "seasons/" + slug(season.Season)
+	}
+	return "seasons/" + slug(season.Season) + "-" + slug(season.Competition)
+}
+
+// THE PAGE OF A SERIES INSIDE THE DIRECTORY OF ITS SEASON
+func seriesFile(round int, s bracket.Series) string {
+	if s.Final {
+		return "final.html"
+	}
+	count := ""
+	if len(s.Games) > 0 && s.Games[0].GameCount != nil {
+		count = slug(*s.Games[0].GameCount)
+	}
+	return fmt.Sprintf("r%d-%s.html", round, count)
+}
@@ -0,0 +1,122 @@
+package site
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/internal/bracketfixture"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	seasons  []models.SeasonModel
+	playoffs map[string][][][]models.PlayoffsModel
+	err      error
+}
+
+func (f *fakeStore) ListSeasons() ([]models.SeasonModel, error) {
+	return f.seasons, f.err
+}
+
+func (f *fakeStore) ListPlayoffs(season string, competition string) ([][][]models.PlayoffsModel, error) {
+	return f.playoffs[season+"/"+competition], nil
+}
+
+func read(t *testing.T, dir string, path string) string {
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func twoSeasons() (*fakeStore, bracketfixture.Team, bracketfixture.Team, bracketfixture.Team) {
+	lions, tigers, bears := bracketfixture.NewTeam("Lions", ""), bracketfixture.NewTeam("<Tigers>", ""), bracketfixture.NewTeam("Bears", "")
+	return &fakeStore{
+		seasons: []models.SeasonModel{
+			{Season: "2023-2024", Competition: "main", Games: 3},
+			{Season: "2022-2023", Competition: "main", Games: 1},
+		},
+		playoffs: map[string][][][]models.PlayoffsModel{
+			"2023-2024/main": {
+				{bracketfixture.Series(1, "1", &lions, &bears, 3, &bears, &bears)},
+				{bracketfixture.Series(2, "FINAL", nil, &bears, 1)},
+			},
+			"2022-2023/main": {
+				{bracketfixture.Series(1, "FINAL", &lions, &tigers, 1, &lions)},
+			},
+		},
+	}, lions, tigers, bears
+}
+
+func TestGenerate_Pages(t *testing.T) {
+	store, lions, tigers, bears := twoSeasons()
+	dir := filepath.Join(t.TempDir(), "public")
+
+	require.NoError(t, Generate(store, dir, "North League"))
+
+	index := read(t, dir, "index.html")
+	assert.Contains(t, index, `<title>North League</title>`)
+	assert.Contains(t, index, `<a href="seasons/2023-2024/index.html">Playoffs 2023-2024</a>`)
+	assert.Contains(t, index, `<a href="teams/`+lions.Id.String()+`.html">Lions</a> <span class="titles">1 title</span>`)
+	assert.Contains(t, read(t, dir, "style.css"), "body")
+
+	season := read(t, dir, "seasons/2023-2024/index.html")
+	assert.Contains(t, season, `<link rel="stylesheet" href="../../style.css">`)
+	assert.Contains(t, season, "<svg")
+	assert.Contains(t, season, `<a href="../../seasons/2023-2024/r1-1.html">R1 #1</a>`)
+
+	semi := read(t, dir, "seasons/2023-2024/r1-1.html")
+	assert.Contains(t, semi, `<strong>Bears</strong>`)
+	assert.Contains(t, semi, `Not played`)
+	final := read(t, dir, "seasons/2023-2024/final.html")
+	assert.Contains(t, final, `<span class="tbd">TBD</span>`)
+
+	champions := read(t, dir, "champions.html")
+	assert.Contains(t, champions, `<a href="seasons/2022-2023/index.html">`)
+	assert.Contains(t, champions, "&lt;Tigers&gt;")
+	assert.NotContains(t, champions, "<Tigers>")
+	assert.NotContains(t, champions, "2023-2024")
+
+	bearsPage := read(t, dir, "teams/"+bears.Id.String()+".html")
+	assert.Contains(t, bearsPage, "Won 2-0")
+	assert.Contains(t, bearsPage, "In progress 0-0")
+	assert.Contains(t, read(t, dir, "teams/"+tigers.Id.String()+".html"), "Lost 0-1")
+}
+
+func TestGenerate_ReplacesThePreviousSite(t *testing.T) {
+	store, _, _, _ := twoSeasons()
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "public")
+	require.NoError(t, Generate(store, dir, ""))
+
+	store.seasons = store.seasons[:1]
+	require.NoError(t, Generate(store, dir, ""))
+
+	assert.NoFileExists(t, filepath.Join(dir, "seasons", "2022-2023", "index.html"))
+	assert.FileExists(t, filepath.Join(dir, "seasons", "2023-2024", "index.html"))
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary directories are left next to the site")
+}
+
+func TestGenerate_KeepsTheSiteWhenTheStoreFails(t *testing.T) {
+	store, _, _, _ := twoSeasons()
+	dir := filepath.Join(t.TempDir(), "public")
+	require.NoError(t, Generate(store, dir, ""))
+
+	store.err = errors.New("connection refused")
+
+	assert.Error(t, Generate(store, dir, ""))
+	assert.FileExists(t, filepath.Join(dir, "seasons", "2022-2023", "final.html"))
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "2023-2024", slug("2023/2024"))
+	assert.Equal(t, "women-s-cup", slug("Women's Cup"))
+	assert.Equal(t, "x", slug("../.."))
+	assert.Equal(t, "seasons/2023-2024-cup", seasonDir(models.SeasonModel{Season: "2023-2024", Competition: "cup"}))
+}
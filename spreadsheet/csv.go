@@ -0,0 +1,66 @@
+// PACKAGE spreadsheet WRITES BRACKETS AND STANDINGS AS CSV FILES THAT SPREADSHEETS OPEN AS THEY ARE, AND READS
+// STANDINGS BACK FROM THEM. FILES START WITH A UTF-8 BYTE ORDER MARK AND USE CRLF LINE ENDINGS SO EXCEL SHOWS
+// ACCENTED TEAM NAMES CORRECTLY, AND TEXT CELLS THAT A SPREADSHEET WOULD RUN AS A FORMULA ARE ESCAPED
+package spreadsheet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// WRITTEN FIRST SO EXCEL READS THE FILE AS UTF-8 INSTEAD OF THE CODE PAGE OF THE COMPUTER
+const bom = "\uFEFF"
+
+// FIRST CHARACTERS THAT MAKE A SPREADSHEET READ A CELL AS A FORMULA
+const formulaPrefixes = "=+-@\t\r"
+
+// ESCAPES A TEXT CELL A SPREADSHEET WOULD RUN AS A FORMULA BY PREFIXING IT WITH A QUOTE, WHICH SPREADSHEETS HIDE.
+// NUMBERS ARE WRITTEN AS THEY ARE SO NEGATIVE VALUES STAY NUMBERS
+func text(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// UNDOES text
+func unescape(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func newWriter(w io.Writer) (*csv.Writer, error) {
+	if _, err := io.WriteString(w, bom); err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+	return cw, nil
+}
+
+// READS A FILE WRITTEN BY newWriter OR SAVED BY A SPREADSHEET. THE BYTE ORDER MARK IS SKIPPED AND THE DELIMITER
+// IS A SEMICOLON WHEN THE HEADER HAS SEMICOLONS BUT NO COMMAS, AS EXCEL SAVES CSV FILES IN MANY LOCALES
+func newReader(r io.Reader) (*csv.Reader, error) {
+	br := bufio.NewReader(r)
+	if head, err := br.Peek(len(bom)); err == nil && string(head) == bom {
+		_, _ = br.Discard(len(bom))
+	}
+	header, err := br.Peek(br.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if i := strings.IndexAny(string(header), "\r\n"); i >= 0 {
+		header = header[:i]
+	}
+	cr := csv.NewReader(br)
+	if strings.Contains(string(header), ";") && !strings.Contains(string(header), ",") {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	return cr, nil
+}
@@ -0,0 +1,89 @@
+package spreadsheet
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+)
+
+// COLUMNS OF THE BRACKET FILE, ONE ROW PER GAME
+var playoffsHeader = []string{
+	"season", "competition", "round", "series", "game",
+	"home_team", "home_team_id", "away_team", "away_team_id", "winner", "winner_id",
+	"scheduled_at", "version", "playoffs_id",
+}
+
+// WRITES THE BRACKET RETURNED BY ListPlayoffs WITH A ROW PER GAME, ROUND BY ROUND AND SERIES BY SERIES. EMPTY
+// SLOTS AND GAMES WITHOUT A WINNER OR A DATE HAVE EMPTY CELLS
+func WritePlayoffs(w io.Writer, playoffs [][][]models.PlayoffsModel) error {
+	cw, err := newWriter(w)
+	if err != nil {
+		return err
+	}
+	if errW := cw.Write(playoffsHeader); errW != nil {
+		return errW
+	}
+	for _, round := range playoffs {
+		for _, series := range round {
+			for _, g := range series {
+				if errW := cw.Write(playoffsRow(g)); errW != nil {
+					return errW
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func playoffsRow(g models.PlayoffsModel) []string {
+	var winner string
+	switch {
+	case g.Winner == nil:
+	case g.HomeTeamId != nil && *g.Winner == *g.HomeTeamId:
+		winner = deref(g.HomeTeamName)
+	case g.AwayTeamId != nil && *g.Winner == *g.AwayTeamId:
+		winner = deref(g.AwayTeamName)
+	}
+	var round, scheduledAt string
+	if g.FixtureRound != nil {
+		round = strconv.Itoa(*g.FixtureRound)
+	}
+	if g.ScheduledAt != nil {
+		scheduledAt = g.ScheduledAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		text(g.Season),
+		text(g.Competition),
+		round,
+		text(deref(g.GameCount)),
+		text(g.GameRound),
+		text(deref(g.HomeTeamName)),
+		id(g.HomeTeamId),
+		text(deref(g.AwayTeamName)),
+		id(g.AwayTeamId),
+		text(winner),
+		id(g.Winner),
+		scheduledAt,
+		strconv.Itoa(g.Version),
+		g.PlayoffsId.String(),
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func id(i *uuid.UUID) string {
+	if i == nil {
+		return ""
+	}
+	return i.String()
+}
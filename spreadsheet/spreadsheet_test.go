@@ -0,0 +1,142 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// READS A FILE WRITTEN BY THE PACKAGE AFTER CHECKING IT IS EXCEL-COMPATIBLE
+func readBack(t *testing.T, file []byte) [][]string {
+	require.True(t, bytes.HasPrefix(file, []byte(bom)), "the file starts with a byte order mark")
+	assert.True(t, bytes.HasSuffix(file, []byte("\r\n")), "lines end with CRLF")
+	records, err := csv.NewReader(bytes.NewReader(file[len(bom):])).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestWritePlayoffs(t *testing.T) {
+	lions, tigers := uuid.New(), uuid.New()
+	lionsName, tigersName := "Lions", "=Tigers"
+	round, count := 1, "2"
+	at := time.Date(2024, 4, 20, 21, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	game := models.PlayoffsModel{
+		PlayoffsId:   uuid.New(),
+		FixtureRound: &round,
+		GameCount:    &count,
+		GameRound:    "1",
+		Season:       "2023-2024",
+		Competition:  "main",
+		HomeTeamId:   &lions,
+		HomeTeamName: &lionsName,
+		AwayTeamId:   &tigers,
+		AwayTeamName: &tigersName,
+		Winner:       &tigers,
+		Version:      3,
+		ScheduledAt:  &at,
+	}
+	unplayed := models.PlayoffsModel{PlayoffsId: uuid.New(), FixtureRound: &round, GameCount: &count, GameRound: "2", Season: "2023-2024"}
+
+	var out bytes.Buffer
+	require.NoError(t, WritePlayoffs(&out, [][][]models.PlayoffsModel{{{game, unplayed}}}))
+
+	records := readBack(t, out.Bytes())
+	require.Len(t, records, 3)
+	assert.Equal(t, playoffsHeader, records[0])
+	assert.Equal(t, []string{
+		"2023-2024", "main", "1", "2", "1",
+		"Lions", lions.String(), "'=Tigers", tigers.String(), "'=Tigers", tigers.String(),
+		"2024-04-20T19:00:00Z", "3", game.PlayoffsId.String(),
+	}, records[1])
+	assert.Equal(t, []string{"", "", "", ""}, []string{records[2][5], records[2][7], records[2][9], records[2][11]})
+}
+
+func TestStandings_RoundTrip(t *testing.T) {
+	teamId := uuid.New()
+	pic := "https://example.com/lions.png"
+	standings := []models.StandingsModel{
+		{StandingsId: uuid.New(), TeamId: &teamId, Position: 1, TeamName: "Lions", Acronym: "LIO", TeamPicUrl: &pic, Gp: 10, W: 8, L: 2, WinPercentage: 0.8, Gf: 900, Pts: 18, Conference: "East", Season: "2023-2024", League: "north"},
+		{StandingsId: uuid.New(), Position: 2, TeamName: "-Tigers", Gp: 10, W: 5, L: 5, WinPercentage: 0.5, Gf: 850, Pts: 15, Conference: "East", Season: "2023-2024", League: "north"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteStandings(&out, standings))
+	read, report, err := ReadStandings(&out, "")
+
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	assert.Equal(t, 2, report.Rows)
+	require.Len(t, read, 2)
+	for i := range standings {
+		// THE IMPORT GIVES NEW IDS AND THE LEAGUE OF THE CONNECTION
+		standings[i].StandingsId, standings[i].League = uuid.Nil, ""
+	}
+	assert.Equal(t, standings, read)
+}
+
+func TestReadStandings_SpreadsheetFormats(t *testing.T) {
+	file := bom + "Team_Name;Conference;W;Win_Percentage\r\nLions;East;8;80,5%\r\n;;;\r\n"
+
+	read, report, err := ReadStandings(strings.NewReader(file), "2024-2025")
+
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	require.Len(t, read, 1)
+	assert.Equal(t, "Lions", read[0].TeamName)
+	assert.Equal(t, "2024-2025", read[0].Season)
+	assert.Equal(t, 8, read[0].W)
+	assert.InDelta(t, 0.805, read[0].WinPercentage, 1e-9)
+}
+
+func TestReadStandings_Report(t *testing.T) {
+	file := strings.Join([]string{
+		"team_name,conference,season,gp,team_id",
+		"Lions,East,2023-2024,ten,",
+		",East,2023-2024,10,",
+		"Tigers,East,2023-2024,-1,not-an-id",
+		"lions,East,2023-2024,10,",
+		"Bears,West,2023-2024,10,,extra",
+	}, "\n")
+
+	read, report, err := ReadStandings(strings.NewReader(file), "")
+
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Equal(t, 5, report.Rows)
+	assert.Len(t, read, 5)
+	var problems []string
+	for _, p := range report.Problems {
+		problems = append(problems, p.String())
+	}
+	assert.Equal(t, []string{
+		`line 2, column gp: "ten" is not a whole number`,
+		"line 3: invalid standings record. teamName, conference and season are required",
+		`line 4, column team_id: "not-an-id" is not a team id`,
+		"line 5: team lions is already in conference East of season 2023-2024 on line 2",
+		"line 6: 6 cells for 5 columns",
+	}, problems)
+}
+
+func TestReadStandings_Header(t *testing.T) {
+	_, report, err := ReadStandings(strings.NewReader("team,conference,conference\nLions,East,East\n"), "")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Problem{
+		{Line: 1, Column: "team", Message: "unknown column"},
+		{Line: 1, Column: "conference", Message: "duplicated column"},
+		{Line: 1, Column: "team_name", Message: "missing column"},
+		{Line: 1, Column: "season", Message: "missing column"},
+	}, report.Problems)
+
+	_, report, err = ReadStandings(strings.NewReader(""), "")
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+}
@@ -0,0 +1,281 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+)
+
+// A COLUMN OF THE STANDINGS FILE, NAMED AFTER THE COLUMN OF THE standings TABLE
+type standingsColumn struct {
+	name string
+	get  func(s models.StandingsModel) string
+	// NIL FOR THE COLUMNS THE IMPORT IGNORES
+	set func(s *models.StandingsModel, value string) error
+}
+
+var standingsColumns = []standingsColumn{
+	{"position", func(s models.StandingsModel) string { return strconv.Itoa(s.Position) }, func(s *models.StandingsModel, v string) error { return parseInt(&s.Position, v) }},
+	{"team_name", func(s models.StandingsModel) string { return text(s.TeamName) }, func(s *models.StandingsModel, v string) error { s.TeamName = v; return nil }},
+	{"acronym", func(s models.StandingsModel) string { return text(s.Acronym) }, func(s *models.StandingsModel, v string) error { s.Acronym = v; return nil }},
+	{"conference", func(s models.StandingsModel) string { return text(s.Conference) }, func(s *models.StandingsModel, v string) error { s.Conference = v; return nil }},
+	{"season", func(s models.StandingsModel) string { return text(s.Season) }, func(s *models.StandingsModel, v string) error { s.Season = v; return nil }},
+	{"gp", func(s models.StandingsModel) string { return strconv.Itoa(s.Gp) }, func(s *models.StandingsModel, v string) error { return parseInt(&s.Gp, v) }},
+	{"w", func(s models.StandingsModel) string { return strconv.Itoa(s.W) }, func(s *models.StandingsModel, v string) error { return parseInt(&s.W, v) }},
+	{"l", func(s models.StandingsModel) string { return strconv.Itoa(s.L) }, func(s *models.StandingsModel, v string) error { return parseInt(&s.L, v) }},
+	{"win_percentage", func(s models.StandingsModel) string { return strconv.FormatFloat(s.WinPercentage, 'f', -1, 64) }, func(s *models.StandingsModel, v string) error { return parseFloat(&s.WinPercentage, v) }},
+	{"gf", func(s models.StandingsModel) string { return strconv.Itoa(s.Gf) }, func(s *models.StandingsModel, v string) error { return parseInt(&s.Gf, v) }},
+	{"pts", func(s models.StandingsModel) string { return strconv.Itoa(s.Pts) }, func(s *models.StandingsModel, v string) error { return parseInt(&s.Pts, v) }},
+	{"team_id", func(s models.StandingsModel) string { return id(s.TeamId) }, func(s *models.StandingsModel, v string) error { return parseId(&s.TeamId, v) }},
+	{"team_pic_url", func(s models.StandingsModel) string { return text(deref(s.TeamPicUrl)) }, func(s *models.StandingsModel, v string) error { s.TeamPicUrl = optional(v); return nil }},
+	// A NEW ID IS GIVEN TO EVERY IMPORTED RECORD AND THE LEAGUE IS THE ONE OF THE CONNECTION
+	{"standings_id", func(s models.StandingsModel) string { return s.StandingsId.String() }, nil},
+	{"league", func(s models.StandingsModel) string { return text(s.League) }, nil},
+}
+
+// COLUMNS AN IMPORTED FILE MUST HAVE. season CAN BE LEFT OUT WHEN THE IMPORT SETS IT
+var requiredStandingsColumns = []string{"team_name", "conference"}
+
+// A PROBLEM FOUND IN A LINE OF AN IMPORTED FILE. LINE 1 IS THE HEADER AND Column IS EMPTY WHEN THE PROBLEM IS THE
+// WHOLE RECORD
+type Problem struct {
+	Line    int    `json:"line"`
+	Column  string `json:"column"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Column == "" {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return fmt.Sprintf("line %d, column %s: %s", p.Line, p.Column, p.Message)
+}
+
+// WHAT READING A STANDINGS FILE FOUND. THE FILE CAN BE IMPORTED WHEN THERE ARE NO PROBLEMS
+type Report struct {
+	Rows     int
+	Problems []Problem
+}
+
+func (r Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// WRITES STANDINGS WITH A COLUMN PER FIELD OF THE standings TABLE, IN THE SHAPE ReadStandings READS BACK
+func WriteStandings(w io.Writer, standings []models.StandingsModel) error {
+	cw, err := newWriter(w)
+	if err != nil {
+		return err
+	}
+	header := make([]string, len(standingsColumns))
+	for i, c := range standingsColumns {
+		header[i] = c.name
+	}
+	if errW := cw.Write(header); errW != nil {
+		return errW
+	}
+	for _, s := range standings {
+		row := make([]string, len(standingsColumns))
+		for i, c := range standingsColumns {
+			row[i] = c.get(s)
+		}
+		if errW := cw.Write(row); errW != nil {
+			return errW
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// READS THE STANDINGS OF A CSV FILE AND VALIDATES EVERY RECORD AS ImportStandings WOULD, COLLECTING EVERY PROBLEM
+// INSTEAD OF STOPPING AT THE FIRST ONE SO THE WHOLE FILE CAN BE FIXED AT ONCE. A NON EMPTY season IS SET ON EVERY
+// RECORD, OVERRIDING THE FILE. COLUMNS ARE MATCHED BY NAME IN ANY ORDER, AND EMPTY NUMBERS READ AS 0. THE ERROR
+// IS ONLY FOR A FILE THAT CAN NOT BE READ
+func ReadStandings(r io.Reader, season string) ([]models.StandingsModel, Report, error) {
+	report := Report{}
+	cr, err := newReader(r)
+	if err != nil {
+		return nil, report, err
+	}
+	header, err := cr.Read()
+	if err == io.EOF {
+		report.Problems = append(report.Problems, Problem{Line: 1, Message: "the file is empty"})
+		return nil, report, nil
+	}
+	if err != nil {
+		report, err = readProblem(report, err)
+		return nil, report, err
+	}
+	columns, problems := standingsHeader(header, season != "")
+	if len(problems) > 0 {
+		report.Problems = problems
+		return nil, report, nil
+	}
+
+	var standings []models.StandingsModel
+	seen := map[string]int{}
+	for {
+		record, errR := cr.Read()
+		if errR == io.EOF {
+			break
+		}
+		if errR != nil {
+			report, errR = readProblem(report, errR)
+			return nil, report, errR
+		}
+		line, _ := cr.FieldPos(0)
+		if blank(record) {
+			continue
+		}
+		report.Rows++
+		s, rowProblems := standingsRecord(line, columns, record)
+		if season != "" {
+			s.Season = season
+		}
+		if len(rowProblems) == 0 {
+			if errV := queries.ValidateStandings(s); errV != nil {
+				rowProblems = append(rowProblems, Problem{Line: line, Message: errV.Error()})
+			}
+		}
+		key := strings.ToLower(s.Season + "\x00" + s.Conference + "\x00" + s.TeamName)
+		if first, ok := seen[key]; ok && s.TeamName != "" {
+			rowProblems = append(rowProblems, Problem{Line: line, Message: fmt.Sprintf("team %s is already in conference %s of season %s on line %d", s.TeamName, s.Conference, s.Season, first)})
+		} else {
+			seen[key] = line
+		}
+		report.Problems = append(report.Problems, rowProblems...)
+		standings = append(standings, s)
+	}
+	if report.Rows == 0 {
+		report.Problems = append(report.Problems, Problem{Line: 2, Message: "the file has no standings records"})
+	}
+	return standings, report, nil
+}
+
+// A CSV SYNTAX ERROR IS A PROBLEM OF THE FILE, ANYTHING ELSE FAILED TO READ IT
+func readProblem(report Report, err error) (Report, error) {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		report.Problems = append(report.Problems, Problem{Line: parseErr.Line, Message: parseErr.Err.Error()})
+		return report, nil
+	}
+	return report, err
+}
+
+// MAPS EVERY CELL OF A RECORD TO ITS COLUMN. A NIL COLUMN IS IGNORED
+func standingsHeader(header []string, seasonSet bool) ([]*standingsColumn, []Problem) {
+	var problems []Problem
+	columns := make([]*standingsColumn, len(header))
+	found := map[string]bool{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for j := range standingsColumns {
+			if standingsColumns[j].name == name {
+				columns[i] = &standingsColumns[j]
+			}
+		}
+		switch {
+		case columns[i] == nil:
+			problems = append(problems, Problem{Line: 1, Column: name, Message: "unknown column"})
+		case found[name]:
+			problems = append(problems, Problem{Line: 1, Column: name, Message: "duplicated column"})
+		}
+		found[name] = true
+	}
+	required := requiredStandingsColumns
+	if !seasonSet {
+		required = append(required, "season")
+	}
+	for _, name := range required {
+		if !found[name] {
+			problems = append(problems, Problem{Line: 1, Column: name, Message: "missing column"})
+		}
+	}
+	return columns, problems
+}
+
+func standingsRecord(line int, columns []*standingsColumn, record []string) (models.StandingsModel, []Problem) {
+	var s models.StandingsModel
+	var problems []Problem
+	if len(record) > len(columns) {
+		problems = append(problems, Problem{Line: line, Message: fmt.Sprintf("%d cells for %d columns", len(record), len(columns))})
+	}
+	for i, value := range record {
+		if i >= len(columns) || columns[i].set == nil {
+			continue
+		}
+		if err := columns[i].set(&s, unescape(strings.TrimSpace(value))); err != nil {
+			problems = append(problems, Problem{Line: line, Column: columns[i].name, Message: err.Error()})
+		}
+	}
+	return s, problems
+}
+
+func blank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseInt(field *int, value string) error {
+	if value == "" {
+		*field = 0
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a whole number", value)
+	}
+	*field = n
+	return nil
+}
+
+// ACCEPTS A DECIMAL COMMA AND A PERCENT SIGN, AS SPREADSHEETS FORMAT PERCENTAGES IN MANY LOCALES
+func parseFloat(field *float64, value string) error {
+	if value == "" {
+		*field = 0
+		return nil
+	}
+	v := strings.Replace(value, ",", ".", 1)
+	percent := strings.HasSuffix(v, "%")
+	f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(v, "%")), 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", value)
+	}
+	if percent {
+		f /= 100
+	}
+	*field = f
+	return nil
+}
+
+func parseId(field **uuid.UUID, value string) error {
+	if value == "" {
+		*field = nil
+		return nil
+	}
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a team id", value)
+	}
+	*field = &parsed
+	return nil
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+)
+
+// RETURNED WHEN A WEBHOOK RESOLVES TO AN ADDRESS THAT IS NOT PUBLIC (LOOPBACK, PRIVATE, LINK-LOCAL...)
+var ErrAddressNotPublic = errors.New("webhook address is not public")
+
+// THE CLIENT OF NewDispatcher. IT ONLY CONNECTS TO PUBLIC ADDRESSES, CHECKED ON THE RESOLVED IP RIGHT BEFORE EACH
+// CONNECTION SO A HOST NAME CAN NOT BE POINTED AT THE PRIVATE NETWORK AFTER THE WEBHOOK WAS CREATED, AND IT DOES NOT
+// FOLLOW REDIRECTS: A 3xx IS RECORDED AS A FAILED DELIVERY. THE ENVIRONMENT PROXY IS NOT USED SINCE THE CHECK WOULD
+// THEN ONLY SEE THE PROXY
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func dialPublicOnly(network string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !queries.PublicAddress(addrPort.Addr()) {
+		return ErrAddressNotPublic
+	}
+	return nil
+}
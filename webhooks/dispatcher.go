@@ -0,0 +1,237 @@
+// PACKAGE webhooks POSTS SIGNED JSON EVENTS TO THE WEBHOOKS SUBSCRIBED TO A LEAGUE WHEN A BRACKET IS CREATED,
+// A SERIES IS DECIDED, A TEAM ADVANCES OR A CHAMPION IS CROWNED. EVERY ATTEMPT IS RECORDED IN THE DELIVERY LOG
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// EVENTS WAITING FOR A WORKER. WHEN THE QUEUE IS FULL NEW EVENTS ARE DROPPED AND LOGGED
+const queueSize = 1024
+
+// WHERE THE DISPATCHER FINDS THE WEBHOOKS AND RECORDS THE DELIVERIES
+type Store interface {
+	ListWebhooksForEvent(league string, eventType string) ([]models.WebhookModel, error)
+	RecordWebhookDelivery(delivery models.WebhookDeliveryModel) error
+	IsWebhookDelivered(webhookId uuid.UUID, eventId uuid.UUID) (bool, error)
+}
+
+type dbStore struct {
+	conn *queries.PlayoffsDBConnection
+}
+
+// STORES THE WEBHOOKS AND DELIVERIES IN THE DATABASE OF THE CONNECTION
+func DBStore(conn *queries.PlayoffsDBConnection) Store {
+	return &dbStore{conn: conn}
+}
+
+func (s *dbStore) ListWebhooksForEvent(league string, eventType string) ([]models.WebhookModel, error) {
+	return s.conn.ForLeague(league).ListWebhooksForEvent(eventType)
+}
+
+func (s *dbStore) RecordWebhookDelivery(delivery models.WebhookDeliveryModel) error {
+	return s.conn.RecordWebhookDelivery(delivery)
+}
+
+func (s *dbStore) IsWebhookDelivered(webhookId uuid.UUID, eventId uuid.UUID) (bool, error) {
+	return s.conn.IsWebhookDelivered(webhookId, eventId)
+}
+
+// WAIT BEFORE THE NEXT ATTEMPT: 1s, 2s, 4s... UP TO A MINUTE
+func DefaultBackoff(attempt int) time.Duration {
+	wait := time.Second << (attempt - 1)
+	if wait > time.Minute || wait <= 0 {
+		return time.Minute
+	}
+	return wait
+}
+
+type Dispatcher struct {
+	// ATTEMPTS PER WEBHOOK AND EVENT, INCLUDING THE FIRST ONE
+	MaxAttempts int
+	Backoff     func(attempt int) time.Duration
+	// NewClient BY DEFAULT, WHICH ONLY REACHES PUBLIC ADDRESSES
+	Client *http.Client
+
+	store  Store
+	queue  chan Event
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+// STARTS workers GOROUTINES DELIVERING THE EVENTS QUEUED BY Handle. SET Handle AS THE OnChange HOOK OF THE
+// CONNECTION AND CALL Close ON SHUTDOWN, OR PASS 0 WORKERS AND HAND Deliver TO AN outbox.Relay
+func NewDispatcher(store Store, workers int) *Dispatcher {
+	d := &Dispatcher{
+		MaxAttempts: 5,
+		Backoff:     DefaultBackoff,
+		Client:      NewClient(10 * time.Second),
+		store:       store,
+		queue:       make(chan Event, queueSize),
+	}
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// QUEUES THE EVENTS OF A COMMITTED CHANGE. IT HAS THE SIGNATURE OF PlayoffsDBConnection.OnChange AND NEVER BLOCKS
+func (d *Dispatcher) Handle(change models.BracketChangeModel) {
+	for _, e := range FromChange(change) {
+		d.Enqueue(e)
+	}
+}
+
+func (d *Dispatcher) Enqueue(e Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		log.Println("webhook dispatcher is closed, dropping event ", e.Type, " ", e.Id)
+		return
+	}
+	select {
+	case d.queue <- e:
+	default:
+		log.Println("webhook queue is full, dropping event ", e.Type, " ", e.Id)
+	}
+}
+
+// STOPS ACCEPTING EVENTS AND WAITS FOR THE QUEUED ONES TO BE DELIVERED OR TO EXHAUST THEIR ATTEMPTS
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for e := range d.queue {
+		d.dispatch(e)
+	}
+}
+
+func (d *Dispatcher) dispatch(e Event) {
+	hooks, err := d.store.ListWebhooksForEvent(e.League, e.Type)
+	if err != nil {
+		log.Println("failed to list webhooks for event ", e.Type, ": ", err.Error())
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		log.Println("failed to encode webhook event: ", err.Error())
+		return
+	}
+	for _, hook := range hooks {
+		d.deliver(hook, e, payload)
+	}
+}
+
+func (d *Dispatcher) deliver(hook models.WebhookModel, e Event, payload []byte) {
+	for attempt := 1; attempt <= d.MaxAttempts; attempt++ {
+		if d.attempt(hook, e, payload, attempt) {
+			return
+		}
+		if attempt < d.MaxAttempts {
+			time.Sleep(d.Backoff(attempt))
+		}
+	}
+	log.Println("giving up delivering event ", e.Id, " to webhook ", hook.WebhookId)
+}
+
+// POSTS THE EVENT ONCE AND RECORDS THE ATTEMPT IN THE DELIVERY LOG. IT REPORTS WHETHER THE WEBHOOK ACCEPTED IT
+func (d *Dispatcher) attempt(hook models.WebhookModel, e Event, payload []byte, attempt int) bool {
+	delivery := models.WebhookDeliveryModel{
+		WebhookId: hook.WebhookId,
+		EventId:   e.Id,
+		EventType: e.Type,
+		Payload:   types.JSONText(payload),
+		Attempt:   attempt,
+	}
+	status, err := d.post(hook, e, payload)
+	if status != 0 {
+		delivery.StatusCode = &status
+	}
+	if err != nil {
+		message := err.Error()
+		delivery.Error = &message
+	}
+	delivery.Delivered = err == nil && status >= 200 && status < 300
+	if errR := d.store.RecordWebhookDelivery(delivery); errR != nil {
+		log.Println("failed to record webhook delivery: ", errR.Error())
+	}
+	return delivery.Delivered
+}
+
+// POSTS THE EVENTS OF A CHANGE CLAIMED FROM THE OUTBOX, ONCE PER WEBHOOK, ON THE CALLER'S GOROUTINE. IT HAS THE
+// SIGNATURE OF outbox.Handler AND FAILS WHEN A WEBHOOK DID NOT ACCEPT AN EVENT, SO THE RELAY HANDS THE CHANGE
+// AGAIN AFTER ITS BACKOFF. WEBHOOKS THAT ALREADY ACCEPTED AN EVENT ARE NOT SENT IT AGAIN
+func (d *Dispatcher) Deliver(change models.BracketChangeModel, attempt int) error {
+	failed := 0
+	for _, e := range FromChange(change) {
+		hooks, err := d.store.ListWebhooksForEvent(e.League, e.Type)
+		if err != nil {
+			return err
+		}
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		for _, hook := range hooks {
+			delivered, errD := d.store.IsWebhookDelivered(hook.WebhookId, e.Id)
+			if errD != nil {
+				return errD
+			}
+			if !delivered && !d.attempt(hook, e, payload, attempt) {
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return errors.New(strconv.Itoa(failed) + " webhook deliveries failed")
+	}
+	return nil
+}
+
+func (d *Dispatcher) post(hook models.WebhookModel, e Event, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventId, e.Id.String())
+	req.Header.Set(HeaderEventType, e.Type)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, payload))
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// READING THE BODY LETS THE CLIENT REUSE THE CONNECTION
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	return res.StatusCode, nil
+}
@@ -0,0 +1,141 @@
+package webhooks
+
+import (
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+)
+
+// EVENT TYPES A WEBHOOK CAN SUBSCRIBE TO
+const (
+	EventBracketCreated  = "bracket.created"
+	EventSeriesDecided   = "series.decided"
+	EventTeamAdvanced    = "team.advanced"
+	EventChampionCrowned = "champion.crowned"
+)
+
+// THE JSON PAYLOAD POSTED TO THE WEBHOOKS. Id IS THE SAME FOR EVERY ATTEMPT AND EVERY REDELIVERY OF THE CHANGE
+// FROM THE OUTBOX, SO RECEIVERS CAN DROP DUPLICATES
+type Event struct {
+	Id          uuid.UUID `json:"id"`
+	Type        string    `json:"type"`
+	League      string    `json:"league"`
+	Season      string    `json:"season"`
+	Competition string    `json:"competition"`
+	Actor       string    `json:"actor"`
+	OccurredAt  time.Time `json:"occurredAt"`
+	Data        any       `json:"data"`
+}
+
+type TeamData struct {
+	TeamId   uuid.UUID `json:"teamId"`
+	TeamName *string   `json:"teamName"`
+	TeamURL  *string   `json:"teamURL"`
+}
+
+type SeriesDecidedData struct {
+	TeamData
+	FixtureRound *int    `json:"fixtureRound"`
+	GameCount    *string `json:"gameCount"`
+}
+
+type TeamAdvancedData struct {
+	TeamData
+	PlayoffsId   uuid.UUID `json:"playoffsId"`
+	FixtureRound *int      `json:"fixtureRound"`
+	GameCount    *string   `json:"gameCount"`
+	Slot         string    `json:"slot"`
+}
+
+// RETURNS THE NAME AND LOGO OF THE WINNER OF A GAME FROM ITS HOME OR AWAY SLOT
+func winnerData(game models.PlayoffsModel) TeamData {
+	team := TeamData{TeamId: *game.Winner}
+	if game.HomeTeamId != nil && *game.HomeTeamId == *game.Winner {
+		team.TeamName, team.TeamURL = game.HomeTeamName, game.HomeTeamURL
+	} else if game.AwayTeamId != nil && *game.AwayTeamId == *game.Winner {
+		team.TeamName, team.TeamURL = game.AwayTeamName, game.AwayTeamURL
+	}
+	return team
+}
+
+// DERIVES THE ID OF AN EVENT FROM THE ID OF ITS CHANGE, SO THE SAME CHANGE ALWAYS GIVES THE SAME EVENTS.
+// CHANGES WITHOUT AN ID, WHICH DID NOT GO THROUGH THE OUTBOX, GET RANDOM IDS
+func eventId(changeId uuid.UUID, eventType string, data any) uuid.UUID {
+	if changeId == uuid.Nil {
+		return uuid.New()
+	}
+	name := eventType
+	if advanced, ok := data.(TeamAdvancedData); ok {
+		name += "/" + advanced.PlayoffsId.String() + "/" + advanced.Slot
+	}
+	return uuid.NewSHA1(changeId, []byte(name))
+}
+
+func newlySet(before *uuid.UUID, after *uuid.UUID) bool {
+	return after != nil && (before == nil || *before != *after)
+}
+
+// TURNS A COMMITTED CHANGE OF CreatePlayoffs, ImportPlayoffs OR UpdatePlayoffs INTO WEBHOOK EVENTS. A SERIES IS DECIDED
+// WHEN ITS WINNER IS ADVANCED TO THE NEXT ROUND, AND THE WINNER OF THE FINAL IS THE CHAMPION
+func FromChange(change models.BracketChangeModel) []Event {
+	base := Event{
+		League:      change.League,
+		Season:      change.Season,
+		Competition: change.Competition,
+		Actor:       change.Actor,
+		OccurredAt:  time.Now().UTC(),
+	}
+	event := func(eventType string, data any) Event {
+		e := base
+		e.Id = eventId(change.EventId, eventType, data)
+		e.Type = eventType
+		e.Data = data
+		return e
+	}
+	if change.Operation == queries.AuditCreatePlayoffs || change.Operation == queries.AuditImportPlayoffs {
+		return []Event{event(EventBracketCreated, nil)}
+	}
+	if change.Operation != queries.AuditUpdatePlayoffs {
+		return nil
+	}
+
+	var decider *models.GameChangeModel
+	var advanced []TeamAdvancedData
+	for i, g := range change.Games {
+		if change.PlayoffsId != nil && g.After.PlayoffsId == *change.PlayoffsId && newlySet(g.Before.Winner, g.After.Winner) {
+			decider = &change.Games[i]
+		}
+		slot := TeamAdvancedData{PlayoffsId: g.After.PlayoffsId, FixtureRound: g.After.FixtureRound, GameCount: g.After.GameCount}
+		if newlySet(g.Before.HomeTeamId, g.After.HomeTeamId) {
+			home := slot
+			home.Slot = "home"
+			home.TeamData = TeamData{TeamId: *g.After.HomeTeamId, TeamName: g.After.HomeTeamName, TeamURL: g.After.HomeTeamURL}
+			advanced = append(advanced, home)
+		}
+		if newlySet(g.Before.AwayTeamId, g.After.AwayTeamId) {
+			away := slot
+			away.Slot = "away"
+			away.TeamData = TeamData{TeamId: *g.After.AwayTeamId, TeamName: g.After.AwayTeamName, TeamURL: g.After.AwayTeamURL}
+			advanced = append(advanced, away)
+		}
+	}
+	if decider == nil {
+		return nil
+	}
+	final := decider.After.GameCount != nil && *decider.After.GameCount == "FINAL"
+	if len(advanced) == 0 && !final {
+		return nil
+	}
+	winner := winnerData(decider.After)
+	events := []Event{event(EventSeriesDecided, SeriesDecidedData{TeamData: winner, FixtureRound: decider.After.FixtureRound, GameCount: decider.After.GameCount})}
+	for _, a := range advanced {
+		events = append(events, event(EventTeamAdvanced, a))
+	}
+	if final {
+		events = append(events, event(EventChampionCrowned, winner))
+	}
+	return events
+}
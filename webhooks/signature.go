@@ -0,0 +1,30 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HEADERS SENT WITH EVERY DELIVERY
+const (
+	HeaderEventId   = "X-Webhook-Id"
+	HeaderEventType = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// SIGNS "<timestamp>.<body>" WITH HMAC-SHA256. THE TIMESTAMP IS PART OF THE SIGNED CONTENT SO A CAPTURED
+// DELIVERY CAN NOT BE REPLAYED LATER WITH A FRESH TIMESTAMP
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CHECKS THE X-Webhook-Signature OF A DELIVERY, FOR RECEIVERS WRITTEN IN GO
+func Verify(secret string, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
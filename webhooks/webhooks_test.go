@@ -0,0 +1,275 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/models"
+	"AmHughesAbsalom/GO_CODE_SAMPLE.git/queries"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	mu         sync.Mutex
+	webhooks   []models.WebhookModel
+	deliveries []models.WebhookDeliveryModel
+}
+
+func (s *memoryStore) ListWebhooksForEvent(league string, eventType string) ([]models.WebhookModel, error) {
+	var hooks []models.WebhookModel
+	for _, w := range s.webhooks {
+		if w.League != league {
+			continue
+		}
+		for _, t := range w.EventTypes {
+			if t == eventType {
+				hooks = append(hooks, w)
+			}
+		}
+	}
+	return hooks, nil
+}
+
+func (s *memoryStore) RecordWebhookDelivery(delivery models.WebhookDeliveryModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries = append(s.deliveries, delivery)
+	return nil
+}
+
+func (s *memoryStore) IsWebhookDelivered(webhookId uuid.UUID, eventId uuid.UUID) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.deliveries {
+		if d.WebhookId == webhookId && d.EventId == eventId && d.Delivered {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// A SEMI FINAL WIN OF THE LIONS THAT ADVANCES THEM TO THE HOME SLOT OF THE FINAL
+func semiFinalWin() models.BracketChangeModel {
+	lions := uuid.New()
+	name := "Lions"
+	round, final := 2, 3
+	series, finalLabel := "1", "FINAL"
+	game := models.PlayoffsModel{PlayoffsId: uuid.New(), FixtureRound: &round, GameCount: &series, HomeTeamId: &lions, HomeTeamName: &name}
+	gameWon := game
+	gameWon.Winner = &lions
+	next := models.PlayoffsModel{PlayoffsId: uuid.New(), FixtureRound: &final, GameCount: &finalLabel}
+	nextFilled := next
+	nextFilled.HomeTeamId = &lions
+	nextFilled.HomeTeamName = &name
+	return models.BracketChangeModel{
+		League:      queries.DefaultLeague,
+		Season:      "2023-2024",
+		Competition: queries.DefaultCompetition,
+		Operation:   queries.AuditUpdatePlayoffs,
+		PlayoffsId:  &game.PlayoffsId,
+		Games:       []models.GameChangeModel{{Before: game, After: gameWon}, {Before: next, After: nextFilled}},
+	}
+}
+
+func TestFromChange_SeriesDecidedAndTeamAdvanced(t *testing.T) {
+	events := FromChange(semiFinalWin())
+
+	require.Len(t, events, 2)
+	assert.Equal(t, EventSeriesDecided, events[0].Type)
+	assert.Equal(t, "Lions", *events[0].Data.(SeriesDecidedData).TeamName)
+	assert.Equal(t, EventTeamAdvanced, events[1].Type)
+	assert.Equal(t, "home", events[1].Data.(TeamAdvancedData).Slot)
+	assert.NotEqual(t, events[0].Id, events[1].Id)
+}
+
+func TestFromChange_ChampionCrowned(t *testing.T) {
+	lions := uuid.New()
+	name := "Lions"
+	finalLabel := "FINAL"
+	final := models.PlayoffsModel{PlayoffsId: uuid.New(), GameCount: &finalLabel, AwayTeamId: &lions, AwayTeamName: &name}
+	finalWon := final
+	finalWon.Winner = &lions
+
+	events := FromChange(models.BracketChangeModel{
+		Operation:  queries.AuditUpdatePlayoffs,
+		PlayoffsId: &final.PlayoffsId,
+		Games:      []models.GameChangeModel{{Before: final, After: finalWon}},
+	})
+
+	require.Len(t, events, 2)
+	assert.Equal(t, EventSeriesDecided, events[0].Type)
+	assert.Equal(t, EventChampionCrowned, events[1].Type)
+	assert.Equal(t, "Lions", *events[1].Data.(TeamData).TeamName)
+}
+
+func TestFromChange_BracketCreated(t *testing.T) {
+	for _, operation := range []string{queries.AuditCreatePlayoffs, queries.AuditImportPlayoffs} {
+		events := FromChange(models.BracketChangeModel{Season: "2023-2024", Operation: operation})
+
+		require.Len(t, events, 1, operation)
+		assert.Equal(t, EventBracketCreated, events[0].Type)
+		assert.Equal(t, "2023-2024", events[0].Season)
+	}
+}
+
+func TestFromChange_GameWonWithoutDecidingTheSeries(t *testing.T) {
+	change := semiFinalWin()
+	change.Games = change.Games[:1]
+
+	assert.Empty(t, FromChange(change))
+	assert.Empty(t, FromChange(models.BracketChangeModel{Operation: queries.AuditUndoPlayoffs, Games: semiFinalWin().Games}))
+}
+
+func TestFromChange_SameChangeSameIds(t *testing.T) {
+	change := semiFinalWin()
+	change.EventId = uuid.New()
+
+	first, again := FromChange(change), FromChange(change)
+
+	require.Len(t, again, len(first))
+	for i := range first {
+		assert.Equal(t, first[i].Id, again[i].Id)
+	}
+	assert.NotEqual(t, first[0].Id, first[1].Id)
+}
+
+func TestSignature_Verify(t *testing.T) {
+	body := []byte(`{"type":"series.decided"}`)
+	signature := Sign("s3cret", "1700000000", body)
+
+	assert.True(t, Verify("s3cret", "1700000000", body, signature))
+	assert.False(t, Verify("s3cret", "1700000001", body, signature))
+	assert.False(t, Verify("other", "1700000000", body, signature))
+}
+
+func TestDispatcher_RetriesUntilDelivered(t *testing.T) {
+	var calls int32
+	received := make(chan Event, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !Verify("s3cret", r.Header.Get(HeaderTimestamp), body, r.Header.Get(HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var e Event
+		_ = json.Unmarshal(body, &e)
+		assert.Equal(t, e.Id.String(), r.Header.Get(HeaderEventId))
+		received <- e
+	}))
+	defer receiver.Close()
+
+	store := &memoryStore{webhooks: []models.WebhookModel{{
+		WebhookId:  uuid.New(),
+		League:     queries.DefaultLeague,
+		URL:        receiver.URL,
+		Secret:     "s3cret",
+		EventTypes: []string{EventSeriesDecided},
+	}}}
+	dispatcher := NewDispatcher(store, 1)
+	dispatcher.Backoff = func(int) time.Duration { return time.Millisecond }
+	// THE RECEIVER LISTENS ON LOOPBACK, WHICH THE DEFAULT CLIENT REFUSES
+	dispatcher.Client = receiver.Client()
+
+	dispatcher.Handle(semiFinalWin())
+	dispatcher.Close()
+
+	select {
+	case e := <-received:
+		assert.Equal(t, EventSeriesDecided, e.Type)
+		assert.Equal(t, "2023-2024", e.Season)
+	default:
+		t.Fatal("the event was not delivered")
+	}
+	require.Len(t, store.deliveries, 3)
+	assert.False(t, store.deliveries[0].Delivered)
+	assert.Equal(t, http.StatusServiceUnavailable, *store.deliveries[0].StatusCode)
+	assert.True(t, store.deliveries[2].Delivered)
+	assert.Equal(t, 3, store.deliveries[2].Attempt)
+	assert.Equal(t, store.deliveries[0].EventId, store.deliveries[2].EventId)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	store := &memoryStore{webhooks: []models.WebhookModel{{
+		WebhookId:  uuid.New(),
+		League:     queries.DefaultLeague,
+		URL:        "http://127.0.0.1:1/unreachable",
+		Secret:     "s3cret",
+		EventTypes: []string{EventTeamAdvanced},
+	}}}
+	dispatcher := NewDispatcher(store, 1)
+	dispatcher.MaxAttempts = 2
+	dispatcher.Backoff = func(int) time.Duration { return time.Millisecond }
+
+	dispatcher.Handle(semiFinalWin())
+	dispatcher.Close()
+
+	require.Len(t, store.deliveries, 2)
+	assert.False(t, store.deliveries[1].Delivered)
+	assert.Nil(t, store.deliveries[1].StatusCode)
+	assert.NotNil(t, store.deliveries[1].Error)
+}
+
+func TestDispatcher_DeliverSkipsAcceptedWebhooks(t *testing.T) {
+	var accepted, rejected int32
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&accepted, 1)
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&rejected, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	store := &memoryStore{webhooks: []models.WebhookModel{
+		{WebhookId: uuid.New(), League: queries.DefaultLeague, URL: ok.URL, Secret: "s3cret", EventTypes: []string{EventSeriesDecided}},
+		{WebhookId: uuid.New(), League: queries.DefaultLeague, URL: failing.URL, Secret: "s3cret", EventTypes: []string{EventSeriesDecided}},
+	}}
+	dispatcher := NewDispatcher(store, 0)
+	dispatcher.Client = ok.Client()
+	change := semiFinalWin()
+	change.EventId = uuid.New()
+
+	assert.Error(t, dispatcher.Deliver(change, 1))
+	assert.Error(t, dispatcher.Deliver(change, 2))
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&accepted))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&rejected))
+	require.Len(t, store.deliveries, 3)
+	assert.Equal(t, 2, store.deliveries[2].Attempt)
+	assert.Equal(t, store.deliveries[0].EventId, store.deliveries[2].EventId)
+}
+
+func TestNewClient_RefusesNonPublicAddresses(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the client reached a loopback address")
+	}))
+	defer receiver.Close()
+	client := NewClient(time.Second)
+
+	for _, target := range []string{receiver.URL, "http://10.0.0.1/hook", "http://169.254.169.254/latest/meta-data", "http://[::1]:1/hook", "http://[::ffff:127.0.0.1]:1/hook"} {
+		_, err := client.Post(target, "application/json", nil)
+
+		assert.ErrorIs(t, err, ErrAddressNotPublic, target)
+	}
+}
+
+func TestNewClient_DoesNotFollowRedirects(t *testing.T) {
+	client := NewClient(time.Second)
+	req := httptest.NewRequest(http.MethodPost, "https://partner.test/hook", nil)
+
+	assert.ErrorIs(t, client.CheckRedirect(req, []*http.Request{req}), http.ErrUseLastResponse)
+}